@@ -206,9 +206,43 @@ func buildProvider(
 		}
 		p, err = awssd.NewAWSSDProvider(domainFilter, cfg.AWSZoneType, cfg.DryRun, cfg.AWSSDServiceCleanup, cfg.TXTOwnerID, cfg.AWSSDCreateTag, sd.NewFromConfig(aws.CreateDefaultV2Config(cfg)))
 	case "azure-dns", "azure":
-		p, err = azure.NewAzureProvider(cfg.AzureConfigFile, domainFilter, zoneNameFilter, zoneIDFilter, cfg.AzureSubscriptionID, cfg.AzureResourceGroup, cfg.AzureUserAssignedIdentityClientID, cfg.AzureActiveDirectoryAuthorityHost, cfg.AzureZonesCacheDuration, cfg.AzureMaxRetriesCount, cfg.DryRun)
+		p, err = azure.NewAzureProvider(
+			azure.AzureConfig{
+				ConfigFile:                   cfg.AzureConfigFile,
+				DomainFilter:                 domainFilter,
+				ZoneNameFilter:               zoneNameFilter,
+				ZoneIDFilter:                 zoneIDFilter,
+				SubscriptionID:               cfg.AzureSubscriptionID,
+				ResourceGroup:                cfg.AzureResourceGroup,
+				UserAssignedIdentityClientID: cfg.AzureUserAssignedIdentityClientID,
+				ActiveDirectoryAuthorityHost: cfg.AzureActiveDirectoryAuthorityHost,
+				ZonesCacheDuration:           cfg.AzureZonesCacheDuration,
+				MaxRetriesCount:              cfg.AzureMaxRetriesCount,
+				UserAgent:                    cfg.AzureUserAgent,
+				RequireOwnershipMetadata:     cfg.AzureRequireOwnershipMetadata,
+				ManagedRecordTypes:           cfg.AzureManagedRecordTypes,
+				DefaultTTL:                   endpoint.TTL(cfg.DefaultTTL.Seconds()),
+				DryRun:                       cfg.DryRun,
+			})
 	case "azure-private-dns":
-		p, err = azure.NewAzurePrivateDNSProvider(cfg.AzureConfigFile, domainFilter, zoneNameFilter, zoneIDFilter, cfg.AzureSubscriptionID, cfg.AzureResourceGroup, cfg.AzureUserAssignedIdentityClientID, cfg.AzureActiveDirectoryAuthorityHost, cfg.AzureZonesCacheDuration, cfg.AzureMaxRetriesCount, cfg.DryRun)
+		p, err = azure.NewAzurePrivateDNSProvider(
+			azure.AzureConfig{
+				ConfigFile:                   cfg.AzureConfigFile,
+				DomainFilter:                 domainFilter,
+				ZoneNameFilter:               zoneNameFilter,
+				ZoneIDFilter:                 zoneIDFilter,
+				SubscriptionID:               cfg.AzureSubscriptionID,
+				ResourceGroup:                cfg.AzureResourceGroup,
+				UserAssignedIdentityClientID: cfg.AzureUserAssignedIdentityClientID,
+				ActiveDirectoryAuthorityHost: cfg.AzureActiveDirectoryAuthorityHost,
+				ZonesCacheDuration:           cfg.AzureZonesCacheDuration,
+				MaxRetriesCount:              cfg.AzureMaxRetriesCount,
+				UserAgent:                    cfg.AzureUserAgent,
+				ManagedRecordTypes:           cfg.AzureManagedRecordTypes,
+				PrivateDNSMinTTL:             cfg.AzurePrivateDNSMinTTL,
+				DefaultTTL:                   endpoint.TTL(cfg.DefaultTTL.Seconds()),
+				DryRun:                       cfg.DryRun,
+			})
 	case "civo":
 		p, err = civo.NewCivoProvider(domainFilter, cfg.DryRun)
 	case "cloudflare":
@@ -231,9 +265,26 @@ func buildProvider(
 				Comment: cfg.CloudflareDNSRecordsComment,
 			})
 	case "google":
-		p, err = google.NewGoogleProvider(ctx, cfg.GoogleProject, domainFilter, zoneIDFilter, cfg.GoogleBatchChangeSize, cfg.GoogleBatchChangeInterval, cfg.GoogleZoneVisibility, cfg.DryRun)
+		p, err = google.NewGoogleProvider(ctx,
+			google.GoogleConfig{
+				Project:               cfg.GoogleProject,
+				DomainFilter:          domainFilter,
+				ZoneIDFilter:          zoneIDFilter,
+				BatchChangeSize:       cfg.GoogleBatchChangeSize,
+				BatchChangeInterval:   cfg.GoogleBatchChangeInterval,
+				RequestTimeout:        cfg.GoogleRequestTimeout,
+				ZoneVisibility:        cfg.GoogleZoneVisibility,
+				DefaultZoneVisibility: cfg.GoogleDefaultZoneVisibility,
+				RecordExclusion:       cfg.GoogleRecordExclusion,
+				ProtectedZoneLabel:    cfg.GoogleProtectedZoneLabel,
+				ReadOnlyZonePrefixes:  cfg.GoogleReadOnlyZonePrefixes,
+				RecordNameFilter:      cfg.GoogleRecordNameFilter,
+				TXTPrefix:             cfg.TXTPrefix,
+				TXTSuffix:             cfg.TXTSuffix,
+				DryRun:                cfg.DryRun,
+			})
 	case "digitalocean":
-		p, err = digitalocean.NewDigitalOceanProvider(ctx, domainFilter, cfg.DryRun, cfg.DigitalOceanAPIPageSize)
+		p, err = digitalocean.NewDigitalOceanProvider(ctx, domainFilter, cfg.DryRun, cfg.DigitalOceanAPIPageSize, cfg.DigitalOceanRecordsConcurrency, endpoint.TTL(cfg.DefaultTTL.Seconds()))
 	case "ovh":
 		p, err = ovh.NewOVHProvider(ctx, domainFilter, cfg.OVHEndpoint, cfg.OVHApiRateLimit, cfg.OVHEnableCNAMERelative, cfg.DryRun)
 	case "linode":
@@ -241,7 +292,7 @@ func buildProvider(
 	case "dnsimple":
 		p, err = dnsimple.NewDnsimpleProvider(domainFilter, zoneIDFilter, cfg.DryRun)
 	case "coredns", "skydns":
-		p, err = coredns.NewCoreDNSProvider(domainFilter, cfg.CoreDNSPrefix, cfg.DryRun)
+		p, err = coredns.NewCoreDNSProvider(domainFilter, cfg.CoreDNSPrefix, cfg.CoreDNSDefaultPriority, cfg.DryRun)
 	case "exoscale":
 		p, err = exoscale.NewExoscaleProvider(
 			cfg.ExoscaleAPIEnvironment,
@@ -258,11 +309,19 @@ func buildProvider(
 		p, err = pdns.NewPDNSProvider(
 			ctx,
 			pdns.PDNSConfig{
-				DomainFilter: domainFilter,
-				DryRun:       cfg.DryRun,
-				Server:       cfg.PDNSServer,
-				ServerID:     cfg.PDNSServerID,
-				APIKey:       cfg.PDNSAPIKey,
+				DomainFilter:          domainFilter,
+				ZoneIDFilter:          zoneIDFilter,
+				ZoneExclusionFilter:   endpoint.NewDomainFilter(cfg.PDNSExcludeZones),
+				DryRun:                cfg.DryRun,
+				CreateZones:           cfg.PDNSCreateZones,
+				RecordTypes:           cfg.PDNSRecordTypes,
+				Server:                cfg.PDNSServer,
+				ServerID:              cfg.PDNSServerID,
+				APIKey:                cfg.PDNSAPIKey,
+				RequestTimeout:        cfg.PDNSRequestTimeout,
+				ResponseHeaderTimeout: cfg.PDNSResponseHeaderTimeout,
+				ZoneTTLs:              cfg.PDNSZoneTTLs,
+				DefaultTTL:            endpoint.TTL(cfg.DefaultTTL.Seconds()),
 				TLSConfig: pdns.TLSConfig{
 					SkipTLSVerify:         cfg.PDNSSkipTLSVerify,
 					CAFilePath:            cfg.TLSCA,
@@ -286,6 +345,10 @@ func buildProvider(
 			config, err = oci.LoadOCIConfig(cfg.OCIConfigFile)
 		}
 		config.ZoneCacheDuration = cfg.OCIZoneCacheDuration
+		// --default-ttl takes precedence over the default TTL of the OCI config file
+		if cfg.DefaultTTL > 0 {
+			config.DefaultTTL = endpoint.TTL(cfg.DefaultTTL.Seconds())
+		}
 		if err == nil {
 			p, err = oci.NewOCIProvider(*config, domainFilter, zoneIDFilter, cfg.OCIZoneScope, cfg.DryRun)
 		}
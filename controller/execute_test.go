@@ -495,3 +495,7 @@ func (m *MockProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpo
 func (m *MockProvider) GetDomainFilter() endpoint.DomainFilterInterface {
 	return nil
 }
+
+func (m *MockProvider) SupportedRecordTypes() []string {
+	return nil
+}
@@ -222,6 +222,28 @@ func (df *DomainFilter) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// WithWildcardSubdomains returns a copy of the domain filter in which wildcard entries like
+// *.example.org match the subdomains of example.org, as .example.org does, instead of only the
+// literal wildcard name. Regex domain filters are returned unchanged.
+func (df *DomainFilter) WithWildcardSubdomains() *DomainFilter {
+	if df == nil || df.regex != nil || df.regexExclusion != nil {
+		return df
+	}
+	return NewDomainFilterWithExclusions(wildcardSubdomains(df.Filters), wildcardSubdomains(df.exclude))
+}
+
+// wildcardSubdomains replaces the wildcard entries of the filters by subdomain entries.
+func wildcardSubdomains(filters []string) []string {
+	var fs []string
+	for _, filter := range filters {
+		if strings.HasPrefix(filter, "*.") {
+			filter = strings.TrimPrefix(filter, "*")
+		}
+		fs = append(fs, filter)
+	}
+	return fs
+}
+
 func (df *DomainFilter) MatchParent(domain string) bool {
 	if df == nil {
 		return true // nil filter matches everything
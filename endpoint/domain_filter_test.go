@@ -886,6 +886,32 @@ func TestSimpleDomainFilterWithExclusion(t *testing.T) {
 	}
 }
 
+func TestDomainFilterWithWildcardSubdomains(t *testing.T) {
+	// by default a wildcard entry only matches the literal wildcard name
+	domainFilter := NewDomainFilterWithExclusions([]string{"*.example.org"}, []string{"*.internal.example.org"})
+	assert.True(t, domainFilter.Match("*.example.org"))
+	assert.False(t, domainFilter.Match("test.example.org"))
+
+	wildcardFilter := domainFilter.WithWildcardSubdomains()
+	for domain, want := range map[string]bool{
+		"test.example.org":          true,
+		"foo.test.example.org":      true,
+		"*.example.org":             true,
+		"example.org":               false,
+		"anexample.org":             false,
+		"test.internal.example.org": false,
+		"internal.example.org":      true,
+	} {
+		assert.Equal(t, want, wildcardFilter.Match(domain), domain)
+	}
+	// the original domain filter is left untouched
+	assert.False(t, domainFilter.Match("test.example.org"))
+
+	regexFilter := NewRegexDomainFilter(regexp.MustCompile(`\.example\.org$`), nil)
+	assert.Same(t, regexFilter, regexFilter.WithWildcardSubdomains())
+	assert.Nil(t, (*DomainFilter)(nil).WithWildcardSubdomains())
+}
+
 func TestDomainFilterNormalizeDomain(t *testing.T) {
 	records := []struct {
 		dnsName string
@@ -322,6 +322,46 @@ func (e *Endpoint) DeleteProviderSpecificProperty(key string) {
 	}
 }
 
+// GetBoolProviderSpecificProperty returns the boolean value of a ProviderSpecificProperty.
+// The second return value reports whether the property exists. An error is returned if the
+// property exists but its value cannot be parsed as a boolean.
+func (e *Endpoint) GetBoolProviderSpecificProperty(key string) (bool, bool, error) {
+	value, ok := e.GetProviderSpecificProperty(key)
+	if !ok {
+		return false, false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, true, fmt.Errorf("failed to parse provider specific property %q: %w", key, err)
+	}
+	return b, true, nil
+}
+
+// SetBoolProviderSpecificProperty sets the boolean value of a ProviderSpecificProperty.
+func (e *Endpoint) SetBoolProviderSpecificProperty(key string, value bool) {
+	e.SetProviderSpecificProperty(key, strconv.FormatBool(value))
+}
+
+// GetInt64ProviderSpecificProperty returns the integer value of a ProviderSpecificProperty.
+// The second return value reports whether the property exists. An error is returned if the
+// property exists but its value cannot be parsed as an integer.
+func (e *Endpoint) GetInt64ProviderSpecificProperty(key string) (int64, bool, error) {
+	value, ok := e.GetProviderSpecificProperty(key)
+	if !ok {
+		return 0, false, nil
+	}
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, true, fmt.Errorf("failed to parse provider specific property %q: %w", key, err)
+	}
+	return i, true, nil
+}
+
+// SetInt64ProviderSpecificProperty sets the integer value of a ProviderSpecificProperty.
+func (e *Endpoint) SetInt64ProviderSpecificProperty(key string, value int64) {
+	e.SetProviderSpecificProperty(key, strconv.FormatInt(value, 10))
+}
+
 // WithLabel adds or updates a label for the Endpoint.
 //
 // Example usage:
@@ -447,6 +487,25 @@ func (m *MXTarget) GetHost() *string {
 	return &m.host
 }
 
+// ParseMXTarget parses an MX record target (e.g. "10 mail.example.com") into its preference
+// and host. The host is always returned fully qualified, with a trailing dot.
+func ParseMXTarget(target string) (uint16, string, error) {
+	mx, err := NewMXRecord(target)
+	if err != nil {
+		return 0, "", err
+	}
+	host := mx.host
+	if !strings.HasSuffix(host, ".") {
+		host += "."
+	}
+	return mx.priority, host, nil
+}
+
+// FormatMXTarget renders an MX record target from its preference and host, as accepted by ParseMXTarget.
+func FormatMXTarget(priority uint16, host string) string {
+	return fmt.Sprintf("%d %s", priority, host)
+}
+
 func (t Targets) ValidateMXRecord() bool {
 	for _, target := range t {
 		_, err := NewMXRecord(target)
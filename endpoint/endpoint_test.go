@@ -352,6 +352,79 @@ func TestSetProviderSpecficProperty(t *testing.T) {
 	}
 }
 
+func TestBoolProviderSpecificProperty(t *testing.T) {
+	e := &Endpoint{}
+
+	t.Run("key is not present in provider specific", func(t *testing.T) {
+		val, ok, err := e.GetBoolProviderSpecificProperty("proxied")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.False(t, val)
+	})
+
+	t.Run("set overwrites existing value", func(t *testing.T) {
+		e.SetBoolProviderSpecificProperty("proxied", true)
+		e.SetBoolProviderSpecificProperty("proxied", false)
+		assert.Equal(t, ProviderSpecific{{Name: "proxied", Value: "false"}}, e.ProviderSpecific)
+
+		val, ok, err := e.GetBoolProviderSpecificProperty("proxied")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.False(t, val)
+	})
+
+	t.Run("value cannot be parsed", func(t *testing.T) {
+		e.SetProviderSpecificProperty("proxied", "maybe")
+		_, ok, err := e.GetBoolProviderSpecificProperty("proxied")
+		assert.Error(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("deleted key is not present anymore", func(t *testing.T) {
+		e.DeleteProviderSpecificProperty("proxied")
+		_, ok, err := e.GetBoolProviderSpecificProperty("proxied")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.Empty(t, e.ProviderSpecific)
+	})
+}
+
+func TestInt64ProviderSpecificProperty(t *testing.T) {
+	e := &Endpoint{}
+
+	t.Run("key is not present in provider specific", func(t *testing.T) {
+		val, ok, err := e.GetInt64ProviderSpecificProperty("weight")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.Zero(t, val)
+	})
+
+	t.Run("set overwrites existing value", func(t *testing.T) {
+		e.SetInt64ProviderSpecificProperty("weight", 10)
+		e.SetInt64ProviderSpecificProperty("weight", -20)
+		assert.Equal(t, ProviderSpecific{{Name: "weight", Value: "-20"}}, e.ProviderSpecific)
+
+		val, ok, err := e.GetInt64ProviderSpecificProperty("weight")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, int64(-20), val)
+	})
+
+	t.Run("value cannot be parsed", func(t *testing.T) {
+		e.SetProviderSpecificProperty("weight", "heavy")
+		_, ok, err := e.GetInt64ProviderSpecificProperty("weight")
+		assert.Error(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("deleted key is not present anymore", func(t *testing.T) {
+		e.DeleteProviderSpecificProperty("weight")
+		_, ok, err := e.GetInt64ProviderSpecificProperty("weight")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
 func TestDeleteProviderSpecificProperty(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -865,6 +938,74 @@ func TestNewMXTarget(t *testing.T) {
 	}
 }
 
+func TestParseMXTarget(t *testing.T) {
+	tests := []struct {
+		description      string
+		target           string
+		expectedPriority uint16
+		expectedHost     string
+		expectError      bool
+	}{
+		{
+			description:      "Host without trailing dot",
+			target:           "10 mx.example.com",
+			expectedPriority: 10,
+			expectedHost:     "mx.example.com.",
+		},
+		{
+			description:      "Host with trailing dot",
+			target:           "10 mx.example.com.",
+			expectedPriority: 10,
+			expectedHost:     "mx.example.com.",
+		},
+		{
+			description:      "Multi-digit preference",
+			target:           "1000 mx.example.com",
+			expectedPriority: 1000,
+			expectedHost:     "mx.example.com.",
+		},
+		{
+			description:      "Maximum preference with surrounding whitespace",
+			target:           "  65535   mx.example.com.  ",
+			expectedPriority: 65535,
+			expectedHost:     "mx.example.com.",
+		},
+		{
+			description: "Preference out of range",
+			target:      "65536 mx.example.com",
+			expectError: true,
+		},
+		{
+			description: "Missing host",
+			target:      "10",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			priority, host, err := ParseMXTarget(tt.target)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedPriority, priority)
+			assert.Equal(t, tt.expectedHost, host)
+		})
+	}
+}
+
+func TestFormatMXTarget(t *testing.T) {
+	assert.Equal(t, "10 mx.example.com", FormatMXTarget(10, "mx.example.com"))
+	assert.Equal(t, "100 mx.example.com.", FormatMXTarget(100, "mx.example.com."))
+
+	priority, host, err := ParseMXTarget(FormatMXTarget(250, "mx.example.com"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(250), priority)
+	assert.Equal(t, "mx.example.com.", host)
+}
+
 func TestCheckEndpoint(t *testing.T) {
 	tests := []struct {
 		description string
@@ -35,6 +35,7 @@ import (
 	// the imports is necessary for the code generation process.
 	_ "sigs.k8s.io/external-dns/controller"
 	_ "sigs.k8s.io/external-dns/provider"
+	_ "sigs.k8s.io/external-dns/provider/google"
 	_ "sigs.k8s.io/external-dns/provider/webhook"
 )
 
@@ -49,6 +49,12 @@ type Config struct {
 	AnnotationFilter                              string
 	LabelFilter                                   string
 	IngressClassNames                             []string
+	IngressAnnotationFilters                      map[string]string
+	IngressEndpointCache                          bool
+	IngressPendingAddress                         string
+	IngressClassTTL                               bool
+	IngressTargetService                          bool
+	IngressDefaultBackendDomain                   string
 	FQDNTemplate                                  string
 	CombineFQDNAndAnnotation                      bool
 	IgnoreHostnameAnnotation                      bool
@@ -68,10 +74,17 @@ type Config struct {
 	ConnectorSourceServer                         string
 	Provider                                      string
 	ProviderCacheTime                             time.Duration
+	DefaultTTL                                    time.Duration
 	GoogleProject                                 string
 	GoogleBatchChangeSize                         int
 	GoogleBatchChangeInterval                     time.Duration
+	GoogleRequestTimeout                          time.Duration
 	GoogleZoneVisibility                          string
+	GoogleDefaultZoneVisibility                   string
+	GoogleRecordExclusion                         *regexp.Regexp
+	GoogleProtectedZoneLabel                      string
+	GoogleReadOnlyZonePrefixes                    []string
+	GoogleRecordNameFilter                        []string
 	DomainFilter                                  []string
 	ExcludeDomains                                []string
 	RegexDomainFilter                             *regexp.Regexp
@@ -107,6 +120,10 @@ type Config struct {
 	AzureActiveDirectoryAuthorityHost             string
 	AzureZonesCacheDuration                       time.Duration
 	AzureMaxRetriesCount                          int
+	AzureRequireOwnershipMetadata                 bool
+	AzureUserAgent                                string
+	AzureManagedRecordTypes                       []string
+	AzurePrivateDNSMinTTL                         int
 	CloudflareProxied                             bool
 	CloudflareCustomHostnames                     bool
 	CloudflareDNSRecordsPerPage                   int
@@ -116,6 +133,7 @@ type Config struct {
 	CloudflareRegionalServices                    bool
 	CloudflareRegionKey                           string
 	CoreDNSPrefix                                 string
+	CoreDNSDefaultPriority                        int
 	AkamaiServiceConsumerDomain                   string
 	AkamaiClientToken                             string
 	AkamaiClientSecret                            string
@@ -135,6 +153,12 @@ type Config struct {
 	PDNSServerID                                  string
 	PDNSAPIKey                                    string `secure:"yes"`
 	PDNSSkipTLSVerify                             bool
+	PDNSExcludeZones                              []string
+	PDNSCreateZones                               bool
+	PDNSRecordTypes                               []string
+	PDNSRequestTimeout                            time.Duration
+	PDNSResponseHeaderTimeout                     time.Duration
+	PDNSZoneTTLs                                  map[string]string
 	TLSCA                                         string
 	TLSClientCert                                 string
 	TLSClientCertKey                              string
@@ -191,6 +215,7 @@ type Config struct {
 	TransIPAccountName                            string
 	TransIPPrivateKeyFile                         string
 	DigitalOceanAPIPageSize                       int
+	DigitalOceanRecordsConcurrency                int
 	ManagedDNSRecordTypes                         []string
 	ExcludeDNSRecordTypes                         []string
 	GoDaddyAPIKey                                 string `secure:"yes"`
@@ -247,6 +272,8 @@ var defaultConfig = &Config{
 	AzureSubscriptionID:         "",
 	AzureZonesCacheDuration:     0 * time.Second,
 	AzureMaxRetriesCount:        3,
+	AzureManagedRecordTypes:     []string{},
+	AzurePrivateDNSMinTTL:       0,
 	CFAPIEndpoint:               "",
 	CFPassword:                  "",
 	CFUsername:                  "",
@@ -258,130 +285,148 @@ var defaultConfig = &Config{
 	CloudflareRegionalServices:                    false,
 	CloudflareRegionKey:                           "earth",
 
-	CombineFQDNAndAnnotation:     false,
-	Compatibility:                "",
-	ConnectorSourceServer:        "localhost:8080",
-	CoreDNSPrefix:                "/skydns/",
-	CRDSourceAPIVersion:          "externaldns.k8s.io/v1alpha1",
-	CRDSourceKind:                "DNSEndpoint",
-	DefaultTargets:               []string{},
-	DigitalOceanAPIPageSize:      50,
-	DomainFilter:                 []string{},
-	DryRun:                       false,
-	ExcludeDNSRecordTypes:        []string{},
-	ExcludeDomains:               []string{},
-	ExcludeTargetNets:            []string{},
-	ExcludeUnschedulable:         true,
-	ExoscaleAPIEnvironment:       "api",
-	ExoscaleAPIKey:               "",
-	ExoscaleAPISecret:            "",
-	ExoscaleAPIZone:              "ch-gva-2",
-	ExposeInternalIPV6:           false,
-	FQDNTemplate:                 "",
-	GatewayLabelFilter:           "",
-	GatewayName:                  "",
-	GatewayNamespace:             "",
-	GlooNamespaces:               []string{"gloo-system"},
-	GoDaddyAPIKey:                "",
-	GoDaddyOTE:                   false,
-	GoDaddySecretKey:             "",
-	GoDaddyTTL:                   600,
-	GoogleBatchChangeInterval:    time.Second,
-	GoogleBatchChangeSize:        1000,
-	GoogleProject:                "",
-	GoogleZoneVisibility:         "",
-	IgnoreHostnameAnnotation:     false,
-	IgnoreIngressRulesSpec:       false,
-	IgnoreIngressTLSSpec:         false,
-	IngressClassNames:            nil,
-	InMemoryZones:                []string{},
-	Interval:                     time.Minute,
-	KubeConfig:                   "",
-	LabelFilter:                  labels.Everything().String(),
-	LogFormat:                    "text",
-	LogLevel:                     logrus.InfoLevel.String(),
-	ManagedDNSRecordTypes:        []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME},
-	MetricsAddress:               ":7979",
-	MinEventSyncInterval:         5 * time.Second,
-	Namespace:                    "",
-	NAT64Networks:                []string{},
-	NS1Endpoint:                  "",
-	NS1IgnoreSSL:                 false,
-	OCIConfigFile:                "/etc/kubernetes/oci.yaml",
-	OCIZoneCacheDuration:         0 * time.Second,
-	OCIZoneScope:                 "GLOBAL",
-	Once:                         false,
-	OVHApiRateLimit:              20,
-	OVHEnableCNAMERelative:       false,
-	OVHEndpoint:                  "ovh-eu",
-	PDNSAPIKey:                   "",
-	PDNSServer:                   "http://localhost:8081",
-	PDNSServerID:                 "localhost",
-	PDNSSkipTLSVerify:            false,
-	PiholeApiVersion:             "5",
-	PiholePassword:               "",
-	PiholeServer:                 "",
-	PiholeTLSInsecureSkipVerify:  false,
-	PluralCluster:                "",
-	PluralProvider:               "",
-	PodSourceDomain:              "",
-	Policy:                       "sync",
-	Provider:                     "",
-	ProviderCacheTime:            0,
-	PublishHostIP:                false,
-	PublishInternal:              false,
-	RegexDomainExclusion:         regexp.MustCompile(""),
-	RegexDomainFilter:            regexp.MustCompile(""),
-	Registry:                     "txt",
-	RequestTimeout:               time.Second * 30,
-	RFC2136BatchChangeSize:       50,
-	RFC2136GSSTSIG:               false,
-	RFC2136Host:                  []string{""},
-	RFC2136Insecure:              false,
-	RFC2136KerberosPassword:      "",
-	RFC2136KerberosRealm:         "",
-	RFC2136KerberosUsername:      "",
-	RFC2136LoadBalancingStrategy: "disabled",
-	RFC2136MinTTL:                0,
-	RFC2136Port:                  0,
-	RFC2136SkipTLSVerify:         false,
-	RFC2136TAXFR:                 true,
-	RFC2136TSIGKeyName:           "",
-	RFC2136TSIGSecret:            "",
-	RFC2136TSIGSecretAlg:         "",
-	RFC2136UseTLS:                false,
-	RFC2136Zone:                  []string{},
-	ServiceTypeFilter:            []string{},
-	SkipperRouteGroupVersion:     "zalando.org/v1",
-	Sources:                      nil,
-	TargetNetFilter:              []string{},
-	TLSCA:                        "",
-	TLSClientCert:                "",
-	TLSClientCertKey:             "",
-	TraefikEnableLegacy:          false,
-	TraefikDisableNew:            false,
-	TransIPAccountName:           "",
-	TransIPPrivateKeyFile:        "",
-	TXTCacheInterval:             0,
-	TXTEncryptAESKey:             "",
-	TXTEncryptEnabled:            false,
-	TXTOwnerID:                   "default",
-	TXTPrefix:                    "",
-	TXTSuffix:                    "",
-	TXTWildcardReplacement:       "",
-	UpdateEvents:                 false,
-	WebhookProviderReadTimeout:   5 * time.Second,
-	WebhookProviderURL:           "http://localhost:8888",
-	WebhookProviderWriteTimeout:  10 * time.Second,
-	WebhookServer:                false,
-	ZoneIDFilter:                 []string{},
-	ForceDefaultTargets:          false,
+	CombineFQDNAndAnnotation:       false,
+	Compatibility:                  "",
+	ConnectorSourceServer:          "localhost:8080",
+	CoreDNSPrefix:                  "/skydns/",
+	CoreDNSDefaultPriority:         10,
+	CRDSourceAPIVersion:            "externaldns.k8s.io/v1alpha1",
+	CRDSourceKind:                  "DNSEndpoint",
+	DefaultTargets:                 []string{},
+	DigitalOceanAPIPageSize:        50,
+	DigitalOceanRecordsConcurrency: 1,
+	DomainFilter:                   []string{},
+	DryRun:                         false,
+	ExcludeDNSRecordTypes:          []string{},
+	ExcludeDomains:                 []string{},
+	ExcludeTargetNets:              []string{},
+	ExcludeUnschedulable:           true,
+	ExoscaleAPIEnvironment:         "api",
+	ExoscaleAPIKey:                 "",
+	ExoscaleAPISecret:              "",
+	ExoscaleAPIZone:                "ch-gva-2",
+	ExposeInternalIPV6:             false,
+	FQDNTemplate:                   "",
+	GatewayLabelFilter:             "",
+	GatewayName:                    "",
+	GatewayNamespace:               "",
+	GlooNamespaces:                 []string{"gloo-system"},
+	GoDaddyAPIKey:                  "",
+	GoDaddyOTE:                     false,
+	GoDaddySecretKey:               "",
+	GoDaddyTTL:                     600,
+	GoogleBatchChangeInterval:      time.Second,
+	GoogleBatchChangeSize:          1000,
+	GoogleRequestTimeout:           0,
+	GoogleProject:                  "",
+	GoogleZoneVisibility:           "",
+	GoogleDefaultZoneVisibility:    "",
+	GoogleRecordExclusion:          regexp.MustCompile(""),
+	GoogleProtectedZoneLabel:       "",
+	GoogleReadOnlyZonePrefixes:     []string{},
+	GoogleRecordNameFilter:         []string{},
+	IgnoreHostnameAnnotation:       false,
+	IgnoreIngressRulesSpec:         false,
+	IgnoreIngressTLSSpec:           false,
+	IngressClassNames:              nil,
+	IngressAnnotationFilters:       map[string]string{},
+	IngressPendingAddress:          "skip",
+	IngressDefaultBackendDomain:    "",
+	InMemoryZones:                  []string{},
+	Interval:                       time.Minute,
+	KubeConfig:                     "",
+	LabelFilter:                    labels.Everything().String(),
+	LogFormat:                      "text",
+	LogLevel:                       logrus.InfoLevel.String(),
+	ManagedDNSRecordTypes:          []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME},
+	MetricsAddress:                 ":7979",
+	MinEventSyncInterval:           5 * time.Second,
+	Namespace:                      "",
+	NAT64Networks:                  []string{},
+	NS1Endpoint:                    "",
+	NS1IgnoreSSL:                   false,
+	OCIConfigFile:                  "/etc/kubernetes/oci.yaml",
+	OCIZoneCacheDuration:           0 * time.Second,
+	OCIZoneScope:                   "GLOBAL",
+	Once:                           false,
+	OVHApiRateLimit:                20,
+	OVHEnableCNAMERelative:         false,
+	OVHEndpoint:                    "ovh-eu",
+	PDNSAPIKey:                     "",
+	PDNSServer:                     "http://localhost:8081",
+	PDNSServerID:                   "localhost",
+	PDNSSkipTLSVerify:              false,
+	PDNSExcludeZones:               []string{},
+	PDNSRequestTimeout:             0,
+	PDNSResponseHeaderTimeout:      0,
+	PDNSZoneTTLs:                   map[string]string{},
+	PiholeApiVersion:               "5",
+	PiholePassword:                 "",
+	PiholeServer:                   "",
+	PiholeTLSInsecureSkipVerify:    false,
+	PluralCluster:                  "",
+	PluralProvider:                 "",
+	PodSourceDomain:                "",
+	Policy:                         "sync",
+	Provider:                       "",
+	ProviderCacheTime:              0,
+	DefaultTTL:                     0,
+	PublishHostIP:                  false,
+	PublishInternal:                false,
+	RegexDomainExclusion:           regexp.MustCompile(""),
+	RegexDomainFilter:              regexp.MustCompile(""),
+	Registry:                       "txt",
+	RequestTimeout:                 time.Second * 30,
+	RFC2136BatchChangeSize:         50,
+	RFC2136GSSTSIG:                 false,
+	RFC2136Host:                    []string{""},
+	RFC2136Insecure:                false,
+	RFC2136KerberosPassword:        "",
+	RFC2136KerberosRealm:           "",
+	RFC2136KerberosUsername:        "",
+	RFC2136LoadBalancingStrategy:   "disabled",
+	RFC2136MinTTL:                  0,
+	RFC2136Port:                    0,
+	RFC2136SkipTLSVerify:           false,
+	RFC2136TAXFR:                   true,
+	RFC2136TSIGKeyName:             "",
+	RFC2136TSIGSecret:              "",
+	RFC2136TSIGSecretAlg:           "",
+	RFC2136UseTLS:                  false,
+	RFC2136Zone:                    []string{},
+	ServiceTypeFilter:              []string{},
+	SkipperRouteGroupVersion:       "zalando.org/v1",
+	Sources:                        nil,
+	TargetNetFilter:                []string{},
+	TLSCA:                          "",
+	TLSClientCert:                  "",
+	TLSClientCertKey:               "",
+	TraefikEnableLegacy:            false,
+	TraefikDisableNew:              false,
+	TransIPAccountName:             "",
+	TransIPPrivateKeyFile:          "",
+	TXTCacheInterval:               0,
+	TXTEncryptAESKey:               "",
+	TXTEncryptEnabled:              false,
+	TXTOwnerID:                     "default",
+	TXTPrefix:                      "",
+	TXTSuffix:                      "",
+	TXTWildcardReplacement:         "",
+	UpdateEvents:                   false,
+	WebhookProviderReadTimeout:     5 * time.Second,
+	WebhookProviderURL:             "http://localhost:8888",
+	WebhookProviderWriteTimeout:    10 * time.Second,
+	WebhookServer:                  false,
+	ZoneIDFilter:                   []string{},
+	ForceDefaultTargets:            false,
 }
 
 // NewConfig returns new Config object
 func NewConfig() *Config {
 	return &Config{
-		AWSSDCreateTag: map[string]string{},
+		AWSSDCreateTag:           map[string]string{},
+		IngressAnnotationFilters: map[string]string{},
+		PDNSZoneTTLs:             map[string]string{},
 	}
 }
 
@@ -473,6 +518,12 @@ func App(cfg *Config) *kingpin.Application {
 	app.Flag("ignore-ingress-tls-spec", "Ignore the spec.tls section in Ingress resources (default: false)").BoolVar(&cfg.IgnoreIngressTLSSpec)
 	app.Flag("ignore-non-host-network-pods", "Ignore pods not running on host network when using pod source (default: false)").BoolVar(&cfg.IgnoreNonHostNetworkPods)
 	app.Flag("ingress-class", "Require an Ingress to have this class name; specify multiple times to allow more than one class (optional; defaults to any class)").StringsVar(&cfg.IngressClassNames)
+	app.Flag("ingress-annotation-filter", "Filter the ingresses of a namespace by annotation, using label selector semantics, instead of --annotation-filter; specify multiple times for multiple namespaces, e.g. team-a=team=a (optional)").StringMapVar(&cfg.IngressAnnotationFilters)
+	app.Flag("ingress-endpoint-cache", "Reuse the endpoints generated from ingresses until an ingress changes; reduces CPU usage with many ingresses (default: false)").BoolVar(&cfg.IngressEndpointCache)
+	app.Flag("ingress-pending-address", "How to handle ingresses that have neither a load balancer address nor a target annotation: skip them, or wait by keeping the endpoints generated before the address was lost (default: skip, options: skip, wait)").Default(defaultConfig.IngressPendingAddress).EnumVar(&cfg.IngressPendingAddress, "skip", "wait")
+	app.Flag("ingress-class-ttl", "Use the TTL annotation of the IngressClass of an Ingress as the default TTL of its records, the TTL annotation of the Ingress takes precedence; requires permission to list and watch IngressClasses (default: false)").BoolVar(&cfg.IngressClassTTL)
+	app.Flag("ingress-target-service", "Use the load balancer addresses of the Service named by the target-service annotation of an Ingress as its targets; requires permission to list and watch Services (default: false)").BoolVar(&cfg.IngressTargetService)
+	app.Flag("ingress-default-backend-domain", "Domain of the records named after the default backend service of an Ingress without hosts, as <service>.<domain> (optional)").Default(defaultConfig.IngressDefaultBackendDomain).StringVar(&cfg.IngressDefaultBackendDomain)
 	app.Flag("label-filter", "Filter resources queried for endpoints by label selector; currently supported by source types crd, gateway-httproute, gateway-grpcroute, gateway-tlsroute, gateway-tcproute, gateway-udproute, ingress, node, openshift-route, service and ambassador-host").Default(defaultConfig.LabelFilter).StringVar(&cfg.LabelFilter)
 	managedRecordTypesHelp := fmt.Sprintf("Record types to manage; specify multiple times to include many; (default: %s) (supported records: A, AAAA, CNAME, NS, SRV, TXT)", strings.Join(defaultConfig.ManagedDNSRecordTypes, ","))
 	app.Flag("managed-record-types", managedRecordTypesHelp).Default(defaultConfig.ManagedDNSRecordTypes...).StringsVar(&cfg.ManagedDNSRecordTypes)
@@ -492,6 +543,7 @@ func App(cfg *Config) *kingpin.Application {
 	providers := []string{"akamai", "alibabacloud", "aws", "aws-sd", "azure", "azure-dns", "azure-private-dns", "civo", "cloudflare", "coredns", "digitalocean", "dnsimple", "exoscale", "gandi", "godaddy", "google", "inmemory", "linode", "ns1", "oci", "ovh", "pdns", "pihole", "plural", "rfc2136", "scaleway", "skydns", "transip", "webhook"}
 	app.Flag("provider", "The DNS provider where the DNS records will be created (required, options: "+strings.Join(providers, ", ")+")").Required().PlaceHolder("provider").EnumVar(&cfg.Provider, providers...)
 	app.Flag("provider-cache-time", "The time to cache the DNS provider record list requests.").Default(defaultConfig.ProviderCacheTime.String()).DurationVar(&cfg.ProviderCacheTime)
+	app.Flag("default-ttl", "The TTL of records whose endpoint does not specify one (0s to use the provider default of 300s; supported by the Azure, Azure Private DNS, DigitalOcean, OCI and PDNS providers).").Default(defaultConfig.DefaultTTL.String()).DurationVar(&cfg.DefaultTTL)
 	app.Flag("domain-filter", "Limit possible target zones by a domain suffix; specify multiple times for multiple domains (optional)").Default("").StringsVar(&cfg.DomainFilter)
 	app.Flag("exclude-domains", "Exclude subdomains (optional)").Default("").StringsVar(&cfg.ExcludeDomains)
 	app.Flag("regex-domain-filter", "Limit possible domains and target zones by a Regex filter; Overrides domain-filter (optional)").Default(defaultConfig.RegexDomainFilter.String()).RegexpVar(&cfg.RegexDomainFilter)
@@ -501,7 +553,13 @@ func App(cfg *Config) *kingpin.Application {
 	app.Flag("google-project", "When using the Google provider, current project is auto-detected, when running on GCP. Specify other project with this. Must be specified when running outside GCP.").Default(defaultConfig.GoogleProject).StringVar(&cfg.GoogleProject)
 	app.Flag("google-batch-change-size", "When using the Google provider, set the maximum number of changes that will be applied in each batch.").Default(strconv.Itoa(defaultConfig.GoogleBatchChangeSize)).IntVar(&cfg.GoogleBatchChangeSize)
 	app.Flag("google-batch-change-interval", "When using the Google provider, set the interval between batch changes.").Default(defaultConfig.GoogleBatchChangeInterval.String()).DurationVar(&cfg.GoogleBatchChangeInterval)
+	app.Flag("google-request-timeout", "When using the Google provider, set the timeout of each call to the Google Cloud DNS API; 0s means no timeout (optional)").Default(defaultConfig.GoogleRequestTimeout.String()).DurationVar(&cfg.GoogleRequestTimeout)
 	app.Flag("google-zone-visibility", "When using the Google provider, filter for zones with this visibility (optional, options: public, private)").Default(defaultConfig.GoogleZoneVisibility).EnumVar(&cfg.GoogleZoneVisibility, "", "public", "private")
+	app.Flag("google-default-zone-visibility", "When using the Google provider without --google-zone-visibility, prefer the zone with this visibility if a public and a private zone share a DNS name (optional, options: public, private)").Default(defaultConfig.GoogleDefaultZoneVisibility).EnumVar(&cfg.GoogleDefaultZoneVisibility, "", "public", "private")
+	app.Flag("google-record-exclusion", "When using the Google provider, never report or modify records whose name matches this regex (optional)").Default(defaultConfig.GoogleRecordExclusion.String()).RegexpVar(&cfg.GoogleRecordExclusion)
+	app.Flag("google-protected-zone-label", "When using the Google provider, never report or modify records of zones carrying this label, given as key or key=value (optional)").Default(defaultConfig.GoogleProtectedZoneLabel).StringVar(&cfg.GoogleProtectedZoneLabel)
+	app.Flag("google-read-only-zone-prefix", "When using the Google provider, report but never modify records of zones whose name starts with this prefix; specify multiple times for multiple prefixes (optional)").StringsVar(&cfg.GoogleReadOnlyZonePrefixes)
+	app.Flag("google-record-name-filter", "When using the Google provider, only read the records with this fully qualified name and their TXT registry records, requested by name from the API; specify multiple times for multiple names (optional)").StringsVar(&cfg.GoogleRecordNameFilter)
 	app.Flag("alibaba-cloud-config-file", "When using the Alibaba Cloud provider, specify the Alibaba Cloud configuration file (required when --provider=alibabacloud)").Default(defaultConfig.AlibabaCloudConfigFile).StringVar(&cfg.AlibabaCloudConfigFile)
 	app.Flag("alibaba-cloud-zone-type", "When using the Alibaba Cloud provider, filter for zones of this type (optional, options: public, private)").Default(defaultConfig.AlibabaCloudZoneType).EnumVar(&cfg.AlibabaCloudZoneType, "", "public", "private")
 	app.Flag("aws-zone-type", "When using the AWS provider, filter for zones of this type (optional, options: public, private)").Default(defaultConfig.AWSZoneType).EnumVar(&cfg.AWSZoneType, "", "public", "private")
@@ -526,6 +584,10 @@ func App(cfg *Config) *kingpin.Application {
 	app.Flag("azure-user-assigned-identity-client-id", "When using the Azure provider, override the client id of user assigned identity in config file (optional)").Default("").StringVar(&cfg.AzureUserAssignedIdentityClientID)
 	app.Flag("azure-zones-cache-duration", "When using the Azure provider, set the zones list cache TTL (0s to disable).").Default(defaultConfig.AzureZonesCacheDuration.String()).DurationVar(&cfg.AzureZonesCacheDuration)
 	app.Flag("azure-maxretries-count", "When using the Azure provider, set the number of retries for API calls (When less than 0, it disables retries). (optional)").Default(strconv.Itoa(defaultConfig.AzureMaxRetriesCount)).IntVar(&cfg.AzureMaxRetriesCount)
+	app.Flag("azure-require-ownership-metadata", "When using the Azure provider, only update or delete existing record sets carrying the external-dns ownership metadata or owned according to the TXT registry, which stamps the metadata on their next update (default: disabled)").BoolVar(&cfg.AzureRequireOwnershipMetadata)
+	app.Flag("azure-user-agent", "When using the Azure provider, set the application ID sent in the user agent of Azure API calls; at most 24 characters (optional)").Default("").StringVar(&cfg.AzureUserAgent)
+	app.Flag("azure-managed-record-types", "When using the Azure or Azure Private DNS provider, only read and write record sets of this type; specify multiple times for multiple types (default: all supported types)").StringsVar(&cfg.AzureManagedRecordTypes)
+	app.Flag("azure-private-dns-min-ttl", "When using the Azure Private DNS provider, raise record set TTLs below this value to it (default: 0, disabled)").Default(strconv.Itoa(defaultConfig.AzurePrivateDNSMinTTL)).IntVar(&cfg.AzurePrivateDNSMinTTL)
 
 	app.Flag("cloudflare-proxied", "When using the Cloudflare provider, specify if the proxy mode must be enabled (default: disabled)").BoolVar(&cfg.CloudflareProxied)
 	app.Flag("cloudflare-custom-hostnames", "When using the Cloudflare provider, specify if the Custom Hostnames feature will be used. Requires \"Cloudflare for SaaS\" enabled. (default: disabled)").BoolVar(&cfg.CloudflareCustomHostnames)
@@ -537,6 +599,7 @@ func App(cfg *Config) *kingpin.Application {
 	app.Flag("cloudflare-record-comment", "When using the Cloudflare provider, specify the comment for the DNS records (default: '')").Default("").StringVar(&cfg.CloudflareDNSRecordsComment)
 
 	app.Flag("coredns-prefix", "When using the CoreDNS provider, specify the prefix name").Default(defaultConfig.CoreDNSPrefix).StringVar(&cfg.CoreDNSPrefix)
+	app.Flag("coredns-default-priority", "When using the CoreDNS provider, specify the priority of records without a coredns-priority annotation").Default(strconv.Itoa(defaultConfig.CoreDNSDefaultPriority)).IntVar(&cfg.CoreDNSDefaultPriority)
 	app.Flag("akamai-serviceconsumerdomain", "When using the Akamai provider, specify the base URL (required when --provider=akamai and edgerc-path not specified)").Default(defaultConfig.AkamaiServiceConsumerDomain).StringVar(&cfg.AkamaiServiceConsumerDomain)
 	app.Flag("akamai-client-token", "When using the Akamai provider, specify the client token (required when --provider=akamai and edgerc-path not specified)").Default(defaultConfig.AkamaiClientToken).StringVar(&cfg.AkamaiClientToken)
 	app.Flag("akamai-client-secret", "When using the Akamai provider, specify the client secret (required when --provider=akamai and edgerc-path not specified)").Default(defaultConfig.AkamaiClientSecret).StringVar(&cfg.AkamaiClientSecret)
@@ -553,13 +616,20 @@ func App(cfg *Config) *kingpin.Application {
 	app.Flag("ovh-api-rate-limit", "When using the OVH provider, specify the API request rate limit, X operations by seconds (default: 20)").Default(strconv.Itoa(defaultConfig.OVHApiRateLimit)).IntVar(&cfg.OVHApiRateLimit)
 	app.Flag("ovh-enable-cname-relative", "When using the OVH provider, specify if CNAME should be treated as relative on target without final dot (default: false)").Default(strconv.FormatBool(defaultConfig.OVHEnableCNAMERelative)).BoolVar(&cfg.OVHEnableCNAMERelative)
 	app.Flag("pdns-server", "When using the PowerDNS/PDNS provider, specify the URL to the pdns server (required when --provider=pdns)").Default(defaultConfig.PDNSServer).StringVar(&cfg.PDNSServer)
-	app.Flag("pdns-server-id", "When using the PowerDNS/PDNS provider, specify the id of the server to retrieve. Should be `localhost` except when the server is behind a proxy (optional when --provider=pdns) (default: localhost)").Default(defaultConfig.PDNSServerID).StringVar(&cfg.PDNSServerID)
+	app.Flag("pdns-server-id", "When using the PowerDNS/PDNS provider, specify the id of the server to retrieve. Should be `localhost` except when the server is behind a proxy; leave empty to discover it through the API (optional when --provider=pdns) (default: localhost)").Default(defaultConfig.PDNSServerID).StringVar(&cfg.PDNSServerID)
 	app.Flag("pdns-api-key", "When using the PowerDNS/PDNS provider, specify the API key to use to authorize requests (required when --provider=pdns)").Default(defaultConfig.PDNSAPIKey).StringVar(&cfg.PDNSAPIKey)
 	app.Flag("pdns-skip-tls-verify", "When using the PowerDNS/PDNS provider, disable verification of any TLS certificates (optional when --provider=pdns) (default: false)").Default(strconv.FormatBool(defaultConfig.PDNSSkipTLSVerify)).BoolVar(&cfg.PDNSSkipTLSVerify)
+	app.Flag("pdns-exclude-zone", "When using the PowerDNS/PDNS provider, exclude a zone and its subzones from being managed even if it matches the domain filter; specify multiple times for multiple zones (optional)").Default("").StringsVar(&cfg.PDNSExcludeZones)
+	app.Flag("pdns-create-zones", "When using the PowerDNS/PDNS provider, create the zone of the matching domain filter when an endpoint has no matching zone (optional) (default: false)").BoolVar(&cfg.PDNSCreateZones)
+	app.Flag("pdns-record-type", "When using the PowerDNS/PDNS provider, record types to read from the zones; specify multiple times for multiple types (optional) (default: A, AAAA, CNAME, TXT, MX, SRV, ALIAS)").StringsVar(&cfg.PDNSRecordTypes)
+	app.Flag("pdns-request-timeout", "When using the PowerDNS/PDNS provider, set the timeout of each request to the PowerDNS API including reading the response; 0s means no timeout (optional)").Default(defaultConfig.PDNSRequestTimeout.String()).DurationVar(&cfg.PDNSRequestTimeout)
+	app.Flag("pdns-response-header-timeout", "When using the PowerDNS/PDNS provider, set the time to wait for the response headers of the PowerDNS API once a request is sent; 0s means no timeout (optional)").Default(defaultConfig.PDNSResponseHeaderTimeout.String()).DurationVar(&cfg.PDNSResponseHeaderTimeout)
+	app.Flag("pdns-zone-ttl", "When using the PowerDNS/PDNS provider, force the TTL in seconds of all records of a zone and its subdomains regardless of endpoint TTLs, e.g. example.org=300; specify multiple times for multiple zones (optional)").StringMapVar(&cfg.PDNSZoneTTLs)
 	app.Flag("ns1-endpoint", "When using the NS1 provider, specify the URL of the API endpoint to target (default: https://api.nsone.net/v1/)").Default(defaultConfig.NS1Endpoint).StringVar(&cfg.NS1Endpoint)
 	app.Flag("ns1-ignoressl", "When using the NS1 provider, specify whether to verify the SSL certificate (default: false)").Default(strconv.FormatBool(defaultConfig.NS1IgnoreSSL)).BoolVar(&cfg.NS1IgnoreSSL)
 	app.Flag("ns1-min-ttl", "Minimal TTL (in seconds) for records. This value will be used if the provided TTL for a service/ingress is lower than this.").IntVar(&cfg.NS1MinTTLSeconds)
 	app.Flag("digitalocean-api-page-size", "Configure the page size used when querying the DigitalOcean API.").Default(strconv.Itoa(defaultConfig.DigitalOceanAPIPageSize)).IntVar(&cfg.DigitalOceanAPIPageSize)
+	app.Flag("digitalocean-records-concurrency", "Configure the number of domains whose records are fetched at once from the DigitalOcean API.").Default(strconv.Itoa(defaultConfig.DigitalOceanRecordsConcurrency)).IntVar(&cfg.DigitalOceanRecordsConcurrency)
 	// GoDaddy flags
 	app.Flag("godaddy-api-key", "When using the GoDaddy provider, specify the API Key (required when --provider=godaddy)").Default(defaultConfig.GoDaddyAPIKey).StringVar(&cfg.GoDaddyAPIKey)
 	app.Flag("godaddy-api-secret", "When using the GoDaddy provider, specify the API secret (required when --provider=godaddy)").Default(defaultConfig.GoDaddySecretKey).StringVar(&cfg.GoDaddySecretKey)
@@ -45,6 +45,8 @@ var (
 		GoogleBatchChangeSize:                  1000,
 		GoogleBatchChangeInterval:              time.Second,
 		GoogleZoneVisibility:                   "",
+		GoogleDefaultZoneVisibility:            "",
+		GoogleRecordExclusion:                  regexp.MustCompile(""),
 		DomainFilter:                           []string{""},
 		ExcludeDomains:                         []string{""},
 		RegexDomainFilter:                      regexp.MustCompile(""),
@@ -68,6 +70,9 @@ var (
 		AWSZoneCacheDuration:                   0 * time.Second,
 		AWSSDServiceCleanup:                    false,
 		AWSSDCreateTag:                         map[string]string{},
+		IngressAnnotationFilters:               map[string]string{},
+		PDNSZoneTTLs:                           map[string]string{},
+		IngressPendingAddress:                  "skip",
 		AWSDynamoDBTable:                       "external-dns",
 		AzureConfigFile:                        "/etc/kubernetes/azure.json",
 		AzureResourceGroup:                     "",
@@ -81,6 +86,7 @@ var (
 		CloudflareDNSRecordsComment:                   "",
 		CloudflareRegionKey:                           "",
 		CoreDNSPrefix:                                 "/skydns/",
+		CoreDNSDefaultPriority:                        10,
 		AkamaiServiceConsumerDomain:                   "",
 		AkamaiClientToken:                             "",
 		AkamaiClientSecret:                            "",
@@ -96,6 +102,7 @@ var (
 		PDNSServer:                                    "http://localhost:8081",
 		PDNSServerID:                                  "localhost",
 		PDNSAPIKey:                                    "",
+		PDNSExcludeZones:                              []string{""},
 		Policy:                                        "sync",
 		Registry:                                      "txt",
 		TXTOwnerID:                                    "default",
@@ -119,6 +126,7 @@ var (
 		TransIPAccountName:                            "",
 		TransIPPrivateKeyFile:                         "",
 		DigitalOceanAPIPageSize:                       50,
+		DigitalOceanRecordsConcurrency:                1,
 		ManagedDNSRecordTypes:                         []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME},
 		RFC2136BatchChangeSize:                        50,
 		RFC2136Host:                                   []string{""},
@@ -146,10 +154,17 @@ var (
 		FQDNTemplate:                           "{{.Name}}.service.example.com",
 		Compatibility:                          "mate",
 		Provider:                               "google",
+		DefaultTTL:                             2 * time.Minute,
 		GoogleProject:                          "project",
 		GoogleBatchChangeSize:                  100,
 		GoogleBatchChangeInterval:              time.Second * 2,
+		GoogleRequestTimeout:                   time.Second * 30,
 		GoogleZoneVisibility:                   "private",
+		GoogleDefaultZoneVisibility:            "public",
+		GoogleRecordExclusion:                  regexp.MustCompile("legacy-.*"),
+		GoogleProtectedZoneLabel:               "external-dns=protected",
+		GoogleReadOnlyZonePrefixes:             []string{"legacy-", "shared-"},
+		GoogleRecordNameFilter:                 []string{"api.example.org", "www.example.org"},
 		DomainFilter:                           []string{"example.org", "company.com"},
 		ExcludeDomains:                         []string{"xapi.example.org", "xapi.company.com"},
 		RegexDomainFilter:                      regexp.MustCompile("(example\\.org|company\\.com)$"),
@@ -175,11 +190,21 @@ var (
 		AWSZoneCacheDuration:                   10 * time.Second,
 		AWSSDServiceCleanup:                    true,
 		AWSSDCreateTag:                         map[string]string{"key1": "value1", "key2": "value2"},
+		IngressAnnotationFilters:               map[string]string{"team-a": "team=a"},
+		IngressEndpointCache:                   true,
+		IngressPendingAddress:                  "wait",
+		IngressClassTTL:                        true,
+		IngressTargetService:                   true,
+		IngressDefaultBackendDomain:            "backends.example.org",
 		AWSDynamoDBTable:                       "custom-table",
 		AzureConfigFile:                        "azure.json",
 		AzureResourceGroup:                     "arg",
 		AzureSubscriptionID:                    "arg",
 		AzureMaxRetriesCount:                   4,
+		AzureRequireOwnershipMetadata:          true,
+		AzureUserAgent:                         "external-dns-test",
+		AzureManagedRecordTypes:                []string{"A", "TXT"},
+		AzurePrivateDNSMinTTL:                  10,
 		CloudflareProxied:                      true,
 		CloudflareCustomHostnames:              true,
 		CloudflareCustomHostnamesMinTLSVersion: "1.3",
@@ -188,6 +213,7 @@ var (
 		CloudflareRegionalServices:                    true,
 		CloudflareRegionKey:                           "us",
 		CoreDNSPrefix:                                 "/coredns/",
+		CoreDNSDefaultPriority:                        20,
 		AkamaiServiceConsumerDomain:                   "oooo-xxxxxxxxxxxxxxxx-xxxxxxxxxxxxxxxx.luna.akamaiapis.net",
 		AkamaiClientToken:                             "o184671d5307a388180fbf7f11dbdf46",
 		AkamaiClientSecret:                            "o184671d5307a388180fbf7f11dbdf46",
@@ -204,6 +230,12 @@ var (
 		PDNSServerID:                                  "localhost",
 		PDNSAPIKey:                                    "some-secret-key",
 		PDNSSkipTLSVerify:                             true,
+		PDNSExcludeZones:                              []string{"legacy.example.org", "legacy.company.com"},
+		PDNSCreateZones:                               true,
+		PDNSRecordTypes:                               []string{"A", "TXT"},
+		PDNSRequestTimeout:                            time.Minute,
+		PDNSResponseHeaderTimeout:                     time.Second * 45,
+		PDNSZoneTTLs:                                  map[string]string{"example.org": "300", "legacy.company.com": "3600"},
 		TLSCA:                                         "/path/to/ca.crt",
 		TLSClientCert:                                 "/path/to/cert.pem",
 		TLSClientCertKey:                              "/path/to/key.pem",
@@ -233,6 +265,7 @@ var (
 		TransIPAccountName:                            "transip",
 		TransIPPrivateKeyFile:                         "/path/to/transip.key",
 		DigitalOceanAPIPageSize:                       100,
+		DigitalOceanRecordsConcurrency:                4,
 		ManagedDNSRecordTypes:                         []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME, endpoint.RecordTypeNS},
 		RFC2136BatchChangeSize:                        100,
 		RFC2136Host:                                   []string{"rfc2136-host1", "rfc2136-host2"},
@@ -282,14 +315,28 @@ func TestParseFlags(t *testing.T) {
 				"--ignore-ingress-rules-spec",
 				"--compatibility=mate",
 				"--provider=google",
+				"--default-ttl=2m",
 				"--google-project=project",
 				"--google-batch-change-size=100",
 				"--google-batch-change-interval=2s",
+				"--google-request-timeout=30s",
 				"--google-zone-visibility=private",
+				"--google-default-zone-visibility=public",
+				"--google-record-exclusion=legacy-.*",
+				"--google-protected-zone-label=external-dns=protected",
+				"--google-read-only-zone-prefix=legacy-",
+				"--google-read-only-zone-prefix=shared-",
+				"--google-record-name-filter=api.example.org",
+				"--google-record-name-filter=www.example.org",
 				"--azure-config-file=azure.json",
 				"--azure-resource-group=arg",
 				"--azure-subscription-id=arg",
 				"--azure-maxretries-count=4",
+				"--azure-require-ownership-metadata",
+				"--azure-user-agent=external-dns-test",
+				"--azure-managed-record-types=A",
+				"--azure-managed-record-types=TXT",
+				"--azure-private-dns-min-ttl=10",
 				"--cloudflare-proxied",
 				"--cloudflare-custom-hostnames",
 				"--cloudflare-custom-hostnames-min-tls-version=1.3",
@@ -298,6 +345,7 @@ func TestParseFlags(t *testing.T) {
 				"--cloudflare-regional-services",
 				"--cloudflare-region-key=us",
 				"--coredns-prefix=/coredns/",
+				"--coredns-default-priority=20",
 				"--akamai-serviceconsumerdomain=oooo-xxxxxxxxxxxxxxxx-xxxxxxxxxxxxxxxx.luna.akamaiapis.net",
 				"--akamai-client-token=o184671d5307a388180fbf7f11dbdf46",
 				"--akamai-client-secret=o184671d5307a388180fbf7f11dbdf46",
@@ -312,6 +360,15 @@ func TestParseFlags(t *testing.T) {
 				"--pdns-server-id=localhost",
 				"--pdns-api-key=some-secret-key",
 				"--pdns-skip-tls-verify",
+				"--pdns-exclude-zone=legacy.example.org",
+				"--pdns-exclude-zone=legacy.company.com",
+				"--pdns-create-zones",
+				"--pdns-record-type=A",
+				"--pdns-record-type=TXT",
+				"--pdns-request-timeout=1m",
+				"--pdns-response-header-timeout=45s",
+				"--pdns-zone-ttl=example.org=300",
+				"--pdns-zone-ttl=legacy.company.com=3600",
 				"--oci-config-file=oci.yaml",
 				"--oci-zone-scope=PRIVATE",
 				"--oci-zones-cache-duration=30s",
@@ -350,6 +407,12 @@ func TestParseFlags(t *testing.T) {
 				"--aws-sd-service-cleanup",
 				"--aws-sd-create-tag=key1=value1",
 				"--aws-sd-create-tag=key2=value2",
+				"--ingress-annotation-filter=team-a=team=a",
+				"--ingress-endpoint-cache",
+				"--ingress-pending-address=wait",
+				"--ingress-class-ttl",
+				"--ingress-target-service",
+				"--ingress-default-backend-domain=backends.example.org",
 				"--no-aws-evaluate-target-health",
 				"--pihole-api-version=6",
 				"--policy=upsert-only",
@@ -378,6 +441,7 @@ func TestParseFlags(t *testing.T) {
 				"--transip-account=transip",
 				"--transip-keyfile=/path/to/transip.key",
 				"--digitalocean-api-page-size=100",
+				"--digitalocean-records-concurrency=4",
 				"--managed-record-types=A",
 				"--managed-record-types=AAAA",
 				"--managed-record-types=CNAME",
@@ -410,14 +474,25 @@ func TestParseFlags(t *testing.T) {
 				"EXTERNAL_DNS_IGNORE_INGRESS_RULES_SPEC":                         "1",
 				"EXTERNAL_DNS_COMPATIBILITY":                                     "mate",
 				"EXTERNAL_DNS_PROVIDER":                                          "google",
+				"EXTERNAL_DNS_DEFAULT_TTL":                                       "2m",
 				"EXTERNAL_DNS_GOOGLE_PROJECT":                                    "project",
 				"EXTERNAL_DNS_GOOGLE_BATCH_CHANGE_SIZE":                          "100",
 				"EXTERNAL_DNS_GOOGLE_BATCH_CHANGE_INTERVAL":                      "2s",
+				"EXTERNAL_DNS_GOOGLE_REQUEST_TIMEOUT":                            "30s",
 				"EXTERNAL_DNS_GOOGLE_ZONE_VISIBILITY":                            "private",
+				"EXTERNAL_DNS_GOOGLE_DEFAULT_ZONE_VISIBILITY":                    "public",
+				"EXTERNAL_DNS_GOOGLE_RECORD_EXCLUSION":                           "legacy-.*",
+				"EXTERNAL_DNS_GOOGLE_PROTECTED_ZONE_LABEL":                       "external-dns=protected",
+				"EXTERNAL_DNS_GOOGLE_READ_ONLY_ZONE_PREFIX":                      "legacy-\nshared-",
+				"EXTERNAL_DNS_GOOGLE_RECORD_NAME_FILTER":                         "api.example.org\nwww.example.org",
 				"EXTERNAL_DNS_AZURE_CONFIG_FILE":                                 "azure.json",
 				"EXTERNAL_DNS_AZURE_RESOURCE_GROUP":                              "arg",
 				"EXTERNAL_DNS_AZURE_SUBSCRIPTION_ID":                             "arg",
 				"EXTERNAL_DNS_AZURE_MAXRETRIES_COUNT":                            "4",
+				"EXTERNAL_DNS_AZURE_REQUIRE_OWNERSHIP_METADATA":                  "1",
+				"EXTERNAL_DNS_AZURE_USER_AGENT":                                  "external-dns-test",
+				"EXTERNAL_DNS_AZURE_MANAGED_RECORD_TYPES":                        "A\nTXT",
+				"EXTERNAL_DNS_AZURE_PRIVATE_DNS_MIN_TTL":                         "10",
 				"EXTERNAL_DNS_CLOUDFLARE_PROXIED":                                "1",
 				"EXTERNAL_DNS_CLOUDFLARE_CUSTOM_HOSTNAMES":                       "1",
 				"EXTERNAL_DNS_CLOUDFLARE_CUSTOM_HOSTNAMES_MIN_TLS_VERSION":       "1.3",
@@ -426,6 +501,7 @@ func TestParseFlags(t *testing.T) {
 				"EXTERNAL_DNS_CLOUDFLARE_REGIONAL_SERVICES":                      "1",
 				"EXTERNAL_DNS_CLOUDFLARE_REGION_KEY":                             "us",
 				"EXTERNAL_DNS_COREDNS_PREFIX":                                    "/coredns/",
+				"EXTERNAL_DNS_COREDNS_DEFAULT_PRIORITY":                          "20",
 				"EXTERNAL_DNS_AKAMAI_SERVICECONSUMERDOMAIN":                      "oooo-xxxxxxxxxxxxxxxx-xxxxxxxxxxxxxxxx.luna.akamaiapis.net",
 				"EXTERNAL_DNS_AKAMAI_CLIENT_TOKEN":                               "o184671d5307a388180fbf7f11dbdf46",
 				"EXTERNAL_DNS_AKAMAI_CLIENT_SECRET":                              "o184671d5307a388180fbf7f11dbdf46",
@@ -449,6 +525,12 @@ func TestParseFlags(t *testing.T) {
 				"EXTERNAL_DNS_PDNS_ID":                                           "localhost",
 				"EXTERNAL_DNS_PDNS_API_KEY":                                      "some-secret-key",
 				"EXTERNAL_DNS_PDNS_SKIP_TLS_VERIFY":                              "1",
+				"EXTERNAL_DNS_PDNS_EXCLUDE_ZONE":                                 "legacy.example.org\nlegacy.company.com",
+				"EXTERNAL_DNS_PDNS_CREATE_ZONES":                                 "1",
+				"EXTERNAL_DNS_PDNS_RECORD_TYPE":                                  "A\nTXT",
+				"EXTERNAL_DNS_PDNS_REQUEST_TIMEOUT":                              "1m",
+				"EXTERNAL_DNS_PDNS_RESPONSE_HEADER_TIMEOUT":                      "45s",
+				"EXTERNAL_DNS_PDNS_ZONE_TTL":                                     "example.org=300\nlegacy.company.com=3600",
 				"EXTERNAL_DNS_RDNS_ROOT_DOMAIN":                                  "lb.rancher.cloud",
 				"EXTERNAL_DNS_TLS_CA":                                            "/path/to/ca.crt",
 				"EXTERNAL_DNS_TLS_CLIENT_CERT":                                   "/path/to/cert.pem",
@@ -471,6 +553,12 @@ func TestParseFlags(t *testing.T) {
 				"EXTERNAL_DNS_AWS_ZONES_CACHE_DURATION":                          "10s",
 				"EXTERNAL_DNS_AWS_SD_SERVICE_CLEANUP":                            "true",
 				"EXTERNAL_DNS_AWS_SD_CREATE_TAG":                                 "key1=value1\nkey2=value2",
+				"EXTERNAL_DNS_INGRESS_ANNOTATION_FILTER":                         "team-a=team=a",
+				"EXTERNAL_DNS_INGRESS_ENDPOINT_CACHE":                            "true",
+				"EXTERNAL_DNS_INGRESS_PENDING_ADDRESS":                           "wait",
+				"EXTERNAL_DNS_INGRESS_CLASS_TTL":                                 "true",
+				"EXTERNAL_DNS_INGRESS_TARGET_SERVICE":                            "true",
+				"EXTERNAL_DNS_INGRESS_DEFAULT_BACKEND_DOMAIN":                    "backends.example.org",
 				"EXTERNAL_DNS_DYNAMODB_TABLE":                                    "custom-table",
 				"EXTERNAL_DNS_PIHOLE_API_VERSION":                                "6",
 				"EXTERNAL_DNS_POLICY":                                            "upsert-only",
@@ -499,6 +587,7 @@ func TestParseFlags(t *testing.T) {
 				"EXTERNAL_DNS_TRANSIP_ACCOUNT":                                   "transip",
 				"EXTERNAL_DNS_TRANSIP_KEYFILE":                                   "/path/to/transip.key",
 				"EXTERNAL_DNS_DIGITALOCEAN_API_PAGE_SIZE":                        "100",
+				"EXTERNAL_DNS_DIGITALOCEAN_RECORDS_CONCURRENCY":                  "4",
 				"EXTERNAL_DNS_MANAGED_RECORD_TYPES":                              "A\nAAAA\nCNAME\nNS",
 				"EXTERNAL_DNS_EXCLUDE_UNSCHEDULABLE":                             "false",
 				"EXTERNAL_DNS_RFC2136_BATCH_CHANGE_SIZE":                         "100",
@@ -28,6 +28,7 @@ import (
 
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/internal/testutils"
+	"sigs.k8s.io/external-dns/source/annotations"
 )
 
 type PlanTestSuite struct {
@@ -416,6 +417,30 @@ func (suite *PlanTestSuite) TestSyncSecondRoundWithProviderSpecificNoChange() {
 	suite.False(changes.HasChanges())
 }
 
+func (suite *PlanTestSuite) TestSyncSecondRoundWithHealthCheckAnnotationNoChange() {
+	providerSpecific, _ := annotations.ProviderSpecificAnnotations(map[string]string{
+		annotations.HealthCheckIDKey: "abc-123",
+	})
+	current := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("bar", endpoint.RecordTypeA, "127.0.0.1").
+			WithProviderSpecific("aws/health-check-id", "abc-123"),
+	}
+	desired := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("bar", endpoint.RecordTypeA, "127.0.0.1"),
+	}
+	desired[0].ProviderSpecific = providerSpecific
+
+	p := &Plan{
+		Policies:       []Policy{&SyncPolicy{}},
+		Current:        current,
+		Desired:        desired,
+		ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME},
+	}
+
+	changes := p.Calculate().Changes
+	suite.False(changes.HasChanges())
+}
+
 func (suite *PlanTestSuite) TestHasChanges() {
 	current := []*endpoint.Endpoint{suite.bar127AWithProviderSpecificTrue}
 	desired := []*endpoint.Endpoint{suite.bar127AWithProviderSpecificFalse}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+const (
+	SummaryActionCreate = "create"
+	SummaryActionUpdate = "update"
+	SummaryActionDelete = "delete"
+)
+
+// summaryActionOrder defines the order in which actions appear in a summary.
+var summaryActionOrder = map[string]int{
+	SummaryActionCreate: 0,
+	SummaryActionUpdate: 1,
+	SummaryActionDelete: 2,
+}
+
+// ChangeSummary is a single entry of a plan summary.
+type ChangeSummary struct {
+	Action        string   `json:"action"`
+	Name          string   `json:"name"`
+	Type          string   `json:"type"`
+	SetIdentifier string   `json:"setIdentifier,omitempty"`
+	Targets       []string `json:"targets"`
+	TTL           int64    `json:"ttl"`
+}
+
+// Summarize returns one summary entry per change, ordered by action, name,
+// type and set identifier. Updates are summarized by their desired state.
+func (changes *Changes) Summarize() []ChangeSummary {
+	summary := make([]ChangeSummary, 0, len(changes.Create)+len(changes.Update)+len(changes.Delete))
+	for _, ep := range changes.Create {
+		summary = append(summary, newChangeSummary(SummaryActionCreate, ep))
+	}
+	for _, update := range changes.Update {
+		summary = append(summary, newChangeSummary(SummaryActionUpdate, update.New))
+	}
+	for _, ep := range changes.Delete {
+		summary = append(summary, newChangeSummary(SummaryActionDelete, ep))
+	}
+
+	slices.SortStableFunc(summary, func(a, b ChangeSummary) int {
+		return cmp.Or(
+			cmp.Compare(summaryActionOrder[a.Action], summaryActionOrder[b.Action]),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Type, b.Type),
+			cmp.Compare(a.SetIdentifier, b.SetIdentifier),
+		)
+	})
+	return summary
+}
+
+// MarshalSummary serializes the summary of the changes to JSON, e.g. for
+// auditing a plan before it is applied.
+func (changes *Changes) MarshalSummary() ([]byte, error) {
+	return json.Marshal(changes.Summarize())
+}
+
+func newChangeSummary(action string, ep *endpoint.Endpoint) ChangeSummary {
+	targets := slices.Clone([]string(ep.Targets))
+	if targets == nil {
+		targets = []string{}
+	}
+	slices.Sort(targets)
+	return ChangeSummary{
+		Action:        action,
+		Name:          ep.DNSName,
+		Type:          ep.RecordType,
+		SetIdentifier: ep.SetIdentifier,
+		Targets:       targets,
+		TTL:           int64(ep.RecordTTL),
+	}
+}
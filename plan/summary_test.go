@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestChangesMarshalSummary(t *testing.T) {
+	changes := &Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpointWithTTL("foo.example.com", endpoint.RecordTypeTXT, 300, "txt"),
+			endpoint.NewEndpointWithTTL("foo.example.com", endpoint.RecordTypeA, 300, "1.2.3.5", "1.2.3.4"),
+			endpoint.NewEndpoint("bar.example.com", endpoint.RecordTypeCNAME, "foo.example.com"),
+		},
+		Update: []*Update{
+			{
+				Old: endpoint.NewEndpointWithTTL("qux.example.com", endpoint.RecordTypeA, 60, "10.0.0.1"),
+				New: endpoint.NewEndpointWithTTL("qux.example.com", endpoint.RecordTypeA, 120, "10.0.0.2"),
+			},
+			{
+				Old: endpoint.NewEndpoint("baz.example.com", endpoint.RecordTypeA, "10.0.0.3").WithSetIdentifier("b"),
+				New: endpoint.NewEndpoint("baz.example.com", endpoint.RecordTypeA, "10.0.0.4").WithSetIdentifier("b"),
+			},
+			{
+				Old: endpoint.NewEndpoint("baz.example.com", endpoint.RecordTypeA, "10.0.0.5").WithSetIdentifier("a"),
+				New: endpoint.NewEndpoint("baz.example.com", endpoint.RecordTypeA, "10.0.0.6").WithSetIdentifier("a"),
+			},
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("old.example.com", endpoint.RecordTypeAAAA, "2001:db8::1"),
+			{DNSName: "empty.example.com", RecordType: endpoint.RecordTypeA},
+		},
+	}
+
+	expected := `[` +
+		`{"action":"create","name":"bar.example.com","type":"CNAME","targets":["foo.example.com"],"ttl":0},` +
+		`{"action":"create","name":"foo.example.com","type":"A","targets":["1.2.3.4","1.2.3.5"],"ttl":300},` +
+		`{"action":"create","name":"foo.example.com","type":"TXT","targets":["txt"],"ttl":300},` +
+		`{"action":"update","name":"baz.example.com","type":"A","setIdentifier":"a","targets":["10.0.0.6"],"ttl":0},` +
+		`{"action":"update","name":"baz.example.com","type":"A","setIdentifier":"b","targets":["10.0.0.4"],"ttl":0},` +
+		`{"action":"update","name":"qux.example.com","type":"A","targets":["10.0.0.2"],"ttl":120},` +
+		`{"action":"delete","name":"empty.example.com","type":"A","targets":[],"ttl":0},` +
+		`{"action":"delete","name":"old.example.com","type":"AAAA","targets":["2001:db8::1"],"ttl":0}` +
+		`]`
+
+	out, err := changes.MarshalSummary()
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(out))
+
+	// the summary must not depend on the order of the changes
+	slices.Reverse(changes.Create)
+	slices.Reverse(changes.Update)
+	slices.Reverse(changes.Delete)
+	out, err = changes.MarshalSummary()
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(out))
+
+	// summarizing must not reorder the targets of the changes
+	assert.Equal(t, endpoint.Targets{"1.2.3.5", "1.2.3.4"}, changes.Create[1].Targets)
+}
+
+func TestChangesMarshalSummaryEmpty(t *testing.T) {
+	out, err := (&Changes{}).MarshalSummary()
+	require.NoError(t, err)
+	assert.Equal(t, `[]`, string(out))
+}
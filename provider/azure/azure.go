@@ -18,13 +18,19 @@ limitations under the License.
 package azure
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	azcoreruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	dns "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
@@ -35,12 +41,15 @@ import (
 )
 
 const (
-	defaultTTL = 300
+	// ownershipMetadataKey and ownershipMetadataValue mark the record sets written by external-dns.
+	ownershipMetadataKey   = "managedby"
+	ownershipMetadataValue = "external-dns"
 )
 
 // ZonesClient is an interface of dns.ZoneClient that can be stubbed for testing.
 type ZonesClient interface {
 	NewListByResourceGroupPager(resourceGroupName string, options *dns.ZonesClientListByResourceGroupOptions) *azcoreruntime.Pager[dns.ZonesClientListByResourceGroupResponse]
+	Get(ctx context.Context, resourceGroupName string, zoneName string, options *dns.ZonesClientGetOptions) (dns.ZonesClientGetResponse, error)
 }
 
 // RecordSetsClient is an interface of dns.RecordSetsClient that can be stubbed for testing.
@@ -64,18 +73,58 @@ type AzureProvider struct {
 	zonesCache                   *zonesCache[dns.Zone]
 	recordSetsClient             RecordSetsClient
 	maxRetriesCount              int
+	requireOwnershipMetadata     bool
+	// managedRecordTypes restricts the record types read and written; all supported types if empty
+	managedRecordTypes []string
+	// defaultTTL is used for records without a TTL
+	defaultTTL endpoint.TTL
+	// listedRecordSets holds the record sets listed by the last call of Records, keyed by recordSetKey;
+	// nil before the first call
+	listedRecordSets map[string]listedRecordSet
+}
+
+// listedRecordSet is the state of a record set when it was listed, which changes must still match.
+type listedRecordSet struct {
+	etag *string
+	// owned is set if the record set carries the external-dns ownership metadata
+	owned bool
+}
+
+// AzureConfig is comprised of the fields necessary to create a new AzureProvider or AzurePrivateDNSProvider
+type AzureConfig struct {
+	// ConfigFile is the Azure config file, whose values are overridden by the non-empty fields below
+	ConfigFile                   string
+	DomainFilter                 *endpoint.DomainFilter
+	ZoneNameFilter               *endpoint.DomainFilter
+	ZoneIDFilter                 provider.ZoneIDFilter
+	SubscriptionID               string
+	ResourceGroup                string
+	UserAssignedIdentityClientID string
+	ActiveDirectoryAuthorityHost string
+	ZonesCacheDuration           time.Duration
+	MaxRetriesCount              int
+	UserAgent                    string
+	// RequireOwnershipMetadata skips record sets without external-dns ownership metadata, public zones only
+	RequireOwnershipMetadata bool
+	// ManagedRecordTypes restricts the record types read and written; all supported types if empty
+	ManagedRecordTypes []string
+	// PrivateDNSMinTTL is the lowest TTL set on private DNS record sets; disabled if 0
+	PrivateDNSMinTTL int
+	// DefaultTTL is used for records without a TTL, defaulting to provider.DefaultTTL
+	DefaultTTL endpoint.TTL
+	DryRun     bool
 }
 
 // NewAzureProvider creates a new Azure provider.
 //
 // Returns the provider or an error if a provider could not be created.
-func NewAzureProvider(configFile string, domainFilter *endpoint.DomainFilter, zoneNameFilter *endpoint.DomainFilter, zoneIDFilter provider.ZoneIDFilter, subscriptionID string, resourceGroup string, userAssignedIdentityClientID string, activeDirectoryAuthorityHost string, zonesCacheDuration time.Duration, maxRetriesCount int, dryRun bool) (*AzureProvider, error) {
-	cfg, err := getConfig(configFile, subscriptionID, resourceGroup, userAssignedIdentityClientID, activeDirectoryAuthorityHost)
+func NewAzureProvider(azureConfig AzureConfig) (*AzureProvider, error) {
+	cfg, err := getConfig(azureConfig.ConfigFile, azureConfig.SubscriptionID, azureConfig.ResourceGroup, azureConfig.UserAssignedIdentityClientID, azureConfig.ActiveDirectoryAuthorityHost)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read Azure config file '%s': %w", configFile, err)
+		return nil, fmt.Errorf("failed to read Azure config file '%s': %w", azureConfig.ConfigFile, err)
 	}
 
-	cred, clientOpts, err := getCredentials(*cfg, maxRetriesCount)
+	cred, clientOpts, err := getCredentials(*cfg, azureConfig.MaxRetriesCount, azureConfig.UserAgent)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get credentials: %w", err)
 	}
@@ -89,17 +138,20 @@ func NewAzureProvider(configFile string, domainFilter *endpoint.DomainFilter, zo
 		return nil, err
 	}
 	return &AzureProvider{
-		domainFilter:                 domainFilter,
-		zoneNameFilter:               zoneNameFilter,
-		zoneIDFilter:                 zoneIDFilter,
-		dryRun:                       dryRun,
+		domainFilter:                 azureConfig.DomainFilter,
+		zoneNameFilter:               azureConfig.ZoneNameFilter,
+		zoneIDFilter:                 azureConfig.ZoneIDFilter,
+		dryRun:                       azureConfig.DryRun,
 		resourceGroup:                cfg.ResourceGroup,
 		userAssignedIdentityClientID: cfg.UserAssignedIdentityID,
 		activeDirectoryAuthorityHost: cfg.ActiveDirectoryAuthorityHost,
 		zonesClient:                  zonesClient,
-		zonesCache:                   &zonesCache[dns.Zone]{duration: zonesCacheDuration},
+		zonesCache:                   newZonesCache[dns.Zone](azureConfig.ZonesCacheDuration),
 		recordSetsClient:             recordSetsClient,
-		maxRetriesCount:              maxRetriesCount,
+		maxRetriesCount:              azureConfig.MaxRetriesCount,
+		requireOwnershipMetadata:     azureConfig.RequireOwnershipMetadata,
+		managedRecordTypes:           azureConfig.ManagedRecordTypes,
+		defaultTTL:                   azureConfig.DefaultTTL,
 	}, nil
 }
 
@@ -113,6 +165,7 @@ func (p *AzureProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, erro
 	}
 
 	endpoints := make([]*endpoint.Endpoint, 0)
+	listed := map[string]listedRecordSet{}
 
 	for _, zone := range zones {
 		pager := p.recordSetsClient.NewListAllByDNSZonePager(p.resourceGroup, *zone.Name, &dns.RecordSetsClientListAllByDNSZoneOptions{Top: nil})
@@ -127,7 +180,8 @@ func (p *AzureProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, erro
 					continue
 				}
 				recordType := strings.TrimPrefix(*recordSet.Type, "Microsoft.Network/dnszones/")
-				if !p.SupportedRecordType(recordType) {
+				listed[recordSetKey(*zone.Name, *recordSet.Name, recordType)] = listedRecordSet{etag: recordSet.Etag, owned: isOwnedRecordSet(recordSet)}
+				if !p.SupportedRecordType(recordType) || !p.isManagedRecordType(recordType) {
 					continue
 				}
 				name := formatAzureDNSName(*recordSet.Name, *zone.Name)
@@ -155,9 +209,15 @@ func (p *AzureProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, erro
 			}
 		}
 	}
+	p.listedRecordSets = listed
 	return endpoints, nil
 }
 
+// recordSetKey identifies the record set of the given relative name and type in a zone.
+func recordSetKey(zone, name, recordType string) string {
+	return strings.ToLower(zone) + "/" + strings.ToLower(name) + "/" + recordType
+}
+
 // ApplyChanges applies the given changes.
 //
 // Returns nil if the operation was successful or an error if the operation failed.
@@ -168,6 +228,14 @@ func (p *AzureProvider) ApplyChanges(ctx context.Context, changes *plan.Changes)
 	}
 
 	deleted, updated := p.mapChanges(zones, changes)
+	if p.requireOwnershipMetadata {
+		if p.listedRecordSets == nil {
+			if _, err := p.Records(ctx); err != nil {
+				return err
+			}
+		}
+		p.dropUnownedChanges(registryOwnedEndpoints(changes), deleted, updated)
+	}
 	p.deleteRecords(ctx, deleted)
 	p.updateRecords(ctx, updated)
 	return nil
@@ -175,11 +243,25 @@ func (p *AzureProvider) ApplyChanges(ctx context.Context, changes *plan.Changes)
 
 func (p *AzureProvider) zones(ctx context.Context) ([]dns.Zone, error) {
 	log.Debugf("Retrieving Azure DNS zones for resource group: %s.", p.resourceGroup)
-	if !p.zonesCache.Expired() {
-		log.Debugf("Using cached Azure DNS zones for resource group: %s zone count: %d.", p.resourceGroup, len(p.zonesCache.Get()))
-		return p.zonesCache.Get(), nil
-	}
+	return p.zonesCache.GetOrLoad(func() ([]dns.Zone, error) { return p.listZones(ctx) })
+}
+
+// listZones lists the Azure DNS zones matching the filters, bypassing the zones cache.
+func (p *AzureProvider) listZones(ctx context.Context) ([]dns.Zone, error) {
 	var zones []dns.Zone
+	if names, ok := p.explicitZoneNames(); ok {
+		for _, name := range names {
+			resp, err := p.zonesClient.Get(ctx, p.resourceGroup, name, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get Azure DNS zone %s: %w", name, err)
+			}
+			if resp.Name != nil && p.domainFilter.Match(*resp.Name) {
+				zones = append(zones, resp.Zone)
+			}
+		}
+		log.Debugf("Found %d Azure DNS zone(s) by ID.", len(zones))
+		return zones, nil
+	}
 	pager := p.zonesClient.NewListByResourceGroupPager(p.resourceGroup, &dns.ZonesClientListByResourceGroupOptions{Top: nil})
 	for pager.More() {
 		nextResult, err := pager.NextPage(ctx)
@@ -195,11 +277,60 @@ func (p *AzureProvider) zones(ctx context.Context) ([]dns.Zone, error) {
 			}
 		}
 	}
-	log.Debugf("Found %d Azure DNS zone(s). Updating zones cache", len(zones))
-	p.zonesCache.Reset(zones)
+	log.Debugf("Found %d Azure DNS zone(s).", len(zones))
 	return zones, nil
 }
 
+// ZoneNameServers returns the name servers assigned by Azure to each managed zone, keyed by zone name,
+// for setting up the delegation of the zones.
+func (p *AzureProvider) ZoneNameServers(ctx context.Context) (map[string][]string, error) {
+	zones, err := p.zones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nameServers := make(map[string][]string, len(zones))
+	for _, zone := range zones {
+		if zone.Name == nil {
+			continue
+		}
+		var servers []string
+		if zone.Properties != nil {
+			for _, server := range zone.Properties.NameServers {
+				if server != nil {
+					servers = append(servers, *server)
+				}
+			}
+		}
+		nameServers[*zone.Name] = servers
+	}
+	return nameServers, nil
+}
+
+// explicitZoneNames returns the names of the zones selected by the zone ID filter if every entry
+// is the full resource ID of a zone in the provider's resource group. These zones can be fetched
+// individually instead of listing all zones of the resource group.
+func (p *AzureProvider) explicitZoneNames() ([]string, bool) {
+	if !p.zoneIDFilter.IsConfigured() || len(p.zoneNameFilter.Filters) > 0 {
+		return nil, false
+	}
+	names := make([]string, 0, len(p.zoneIDFilter.ZoneIDs))
+	for _, id := range p.zoneIDFilter.ZoneIDs {
+		resourceID, err := arm.ParseResourceID(id)
+		if err != nil ||
+			!strings.EqualFold(resourceID.ResourceType.String(), "Microsoft.Network/dnszones") ||
+			!strings.EqualFold(resourceID.ResourceGroupName, p.resourceGroup) {
+			return nil, false
+		}
+		names = append(names, resourceID.Name)
+	}
+	return names, true
+}
+
+// isManagedRecordType reports whether record sets of the type are managed by the provider.
+func (p *AzureProvider) isManagedRecordType(recordType string) bool {
+	return len(p.managedRecordTypes) == 0 || slices.Contains(p.managedRecordTypes, recordType)
+}
+
 func (p *AzureProvider) SupportedRecordType(recordType string) bool {
 	switch recordType {
 	case "MX":
@@ -218,11 +349,11 @@ func (p *AzureProvider) mapChanges(zones []dns.Zone, changes *plan.Changes) (azu
 	zoneNameIDMapper := provider.ZoneIDName{}
 	for _, z := range zones {
 		if z.Name != nil {
-			zoneNameIDMapper.Add(*z.Name, *z.Name)
+			zoneNameIDMapper.Add(*z.Name, strings.ToLower(*z.Name))
 		}
 	}
 	mapChange := func(changeMap azureChangeMap, change *endpoint.Endpoint) {
-		zone, _ := zoneNameIDMapper.FindZone(change.DNSName)
+		zone, _ := zoneNameIDMapper.FindZone(strings.ToLower(change.DNSName))
 		if zone == "" {
 			if _, ok := ignored[change.DNSName]; !ok {
 				ignored[change.DNSName] = true
@@ -230,6 +361,15 @@ func (p *AzureProvider) mapChanges(zones []dns.Zone, changes *plan.Changes) (azu
 			}
 			return
 		}
+		if !p.isManagedRecordType(change.RecordType) {
+			log.Debugf("Ignoring changes to the %s record of '%s' because the record type is not managed.", change.RecordType, change.DNSName)
+			return
+		}
+		// The apex NS record set is managed by Azure, only delegations at subnames may be changed
+		if change.RecordType == endpoint.RecordTypeNS && p.recordSetNameForZone(zone, change) == "@" {
+			log.Infof("Ignoring changes to the NS record of '%s' because it is the apex of the Azure DNS zone.", change.DNSName)
+			return
+		}
 		// Ensure the record type is suitable
 		changeMap[zone] = append(changeMap[zone], change)
 	}
@@ -243,11 +383,59 @@ func (p *AzureProvider) mapChanges(zones []dns.Zone, changes *plan.Changes) (azu
 	}
 
 	for _, change := range changes.UpdateNew() {
+		// A record set left without targets is deleted rather than emptied
+		if len(change.Targets) == 0 {
+			mapChange(deleted, change)
+			continue
+		}
 		mapChange(updated, change)
 	}
 	return deleted, updated
 }
 
+// dropUnownedChanges removes the changes targeting listed record sets that neither carry the
+// external-dns ownership metadata nor are owned according to the registry, so that records managed
+// by other tools are left untouched. Record sets written before the metadata was introduced receive
+// it with their next update owned according to the registry.
+func (p *AzureProvider) dropUnownedChanges(registryOwned map[*endpoint.Endpoint]bool, changeMaps ...azureChangeMap) {
+	for _, changeMap := range changeMaps {
+		for zone, endpoints := range changeMap {
+			owned := make([]*endpoint.Endpoint, 0, len(endpoints))
+			for _, ep := range endpoints {
+				name := p.recordSetNameForZone(zone, ep)
+				listed, ok := p.listedRecordSets[recordSetKey(zone, name, ep.RecordType)]
+				if ok && !listed.owned && !registryOwned[ep] {
+					log.Warnf("Skipping change of %s record named '%s' for Azure DNS zone '%s' because it is not owned by external-dns.", ep.RecordType, name, zone)
+					continue
+				}
+				owned = append(owned, ep)
+			}
+			changeMap[zone] = owned
+		}
+	}
+}
+
+// registryOwnedEndpoints returns the endpoints of updates and deletions carrying an owner label.
+// The registry only labels the current records it owns, and the plan passes the label on to their
+// updates. Creations are labelled regardless of existing records, so they are not included.
+func registryOwnedEndpoints(changes *plan.Changes) map[*endpoint.Endpoint]bool {
+	owned := map[*endpoint.Endpoint]bool{}
+	for _, ep := range slices.Concat(changes.UpdateNew(), changes.Delete) {
+		if ep.Labels[endpoint.OwnerLabelKey] != "" {
+			owned[ep] = true
+		}
+	}
+	return owned
+}
+
+func isOwnedRecordSet(recordSet *dns.RecordSet) bool {
+	if recordSet.Properties == nil {
+		return false
+	}
+	value, ok := recordSet.Properties.Metadata[ownershipMetadataKey]
+	return ok && value != nil && *value == ownershipMetadataValue
+}
+
 func (p *AzureProvider) deleteRecords(ctx context.Context, deleted azureChangeMap) {
 	// Delete records first
 	for zone, endpoints := range deleted {
@@ -269,6 +457,8 @@ func (p *AzureProvider) deleteRecords(ctx context.Context, deleted azureChangeMa
 						zone,
 						err,
 					)
+				} else {
+					delete(p.listedRecordSets, recordSetKey(zone, name, ep.RecordType))
 				}
 			}
 		}
@@ -304,15 +494,10 @@ func (p *AzureProvider) updateRecords(ctx context.Context, updated azureChangeMa
 
 			recordSet, err := p.newRecordSet(ep)
 			if err == nil {
-				_, err = p.recordSetsClient.CreateOrUpdate(
-					ctx,
-					p.resourceGroup,
-					zone,
-					name,
-					dns.RecordType(ep.RecordType),
-					recordSet,
-					nil,
-				)
+				recordSet.Properties.Metadata = map[string]*string{
+					ownershipMetadataKey: to.Ptr(ownershipMetadataValue),
+				}
+				err = p.conditionalCreateOrUpdate(ctx, zone, name, dns.RecordType(ep.RecordType), recordSet)
 			}
 			if err != nil {
 				log.Errorf(
@@ -328,11 +513,46 @@ func (p *AzureProvider) updateRecords(ctx context.Context, updated azureChangeMa
 	}
 }
 
+// conditionalCreateOrUpdate writes the record set only if it did not change since Records listed it:
+// the etag of the listed record set is passed as If-Match, and a record set that was not listed is only
+// created if it is still absent. A record set changed by another writer in the meantime is left alone,
+// the next synchronization plans the change anew against its current state. Before the first listing,
+// record sets are written unconditionally.
+func (p *AzureProvider) conditionalCreateOrUpdate(ctx context.Context, zone, name string, recordType dns.RecordType, recordSet dns.RecordSet) error {
+	key := recordSetKey(zone, name, string(recordType))
+	options := &dns.RecordSetsClientCreateOrUpdateOptions{}
+	if p.listedRecordSets != nil {
+		if listed, ok := p.listedRecordSets[key]; ok {
+			options.IfMatch = listed.etag
+		} else {
+			options.IfNoneMatch = to.Ptr("*")
+		}
+	}
+
+	resp, err := p.recordSetsClient.CreateOrUpdate(ctx, p.resourceGroup, zone, name, recordType, recordSet, options)
+	if isResponseStatus(err, http.StatusPreconditionFailed) {
+		return fmt.Errorf("record set was modified since it was listed, retrying on the next synchronization: %w", err)
+	}
+	if err != nil {
+		return err
+	}
+	if p.listedRecordSets != nil {
+		p.listedRecordSets[key] = listedRecordSet{etag: resp.Etag, owned: true}
+	}
+	return nil
+}
+
+// isResponseStatus reports whether err is an Azure response error with the given HTTP status code.
+func isResponseStatus(err error, statusCode int) bool {
+	var respErr *azcore.ResponseError
+	return errors.As(err, &respErr) && respErr.StatusCode == statusCode
+}
+
 func (p *AzureProvider) recordSetNameForZone(zone string, endpoint *endpoint.Endpoint) string {
 	// Remove the zone from the record set
 	name := endpoint.DNSName
 	name = name[:len(name)-len(zone)]
-	name = strings.TrimSuffix(name, ".")
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
 
 	// For root, use @
 	if name == "" {
@@ -342,10 +562,7 @@ func (p *AzureProvider) recordSetNameForZone(zone string, endpoint *endpoint.End
 }
 
 func (p *AzureProvider) newRecordSet(endpoint *endpoint.Endpoint) (dns.RecordSet, error) {
-	var ttl int64 = defaultTTL
-	if endpoint.RecordTTL.IsConfigured() {
-		ttl = int64(endpoint.RecordTTL)
-	}
+	ttl := int64(provider.TTLOrDefault(endpoint, p.defaultTTL))
 	switch dns.RecordType(endpoint.RecordType) {
 	case dns.RecordTypeA:
 		aRecords := make([]*dns.ARecord, len(endpoint.Targets))
@@ -428,11 +645,14 @@ func (p *AzureProvider) newRecordSet(endpoint *endpoint.Endpoint) (dns.RecordSet
 }
 
 // Helper function (shared with test code)
+//
+// Azure returns record set and zone names in the casing they were created with,
+// so the resulting name is lowercased to avoid spurious diffs against sources.
 func formatAzureDNSName(recordName, zoneName string) string {
 	if recordName == "@" {
-		return zoneName
+		return strings.ToLower(zoneName)
 	}
-	return fmt.Sprintf("%s.%s", recordName, zoneName)
+	return strings.ToLower(fmt.Sprintf("%s.%s", recordName, zoneName))
 }
 
 // Helper function (shared with text code)
@@ -471,6 +691,11 @@ func extractAzureTargets(recordSet *dns.RecordSet) []string {
 	// Check for MX records
 	mxRecords := properties.MxRecords
 	if len(mxRecords) > 0 && (mxRecords)[0].Exchange != nil {
+		// Azure doesn't preserve the order of MX records, so sort them to keep the targets stable.
+		mxRecords = slices.Clone(mxRecords)
+		slices.SortFunc(mxRecords, func(a, b *dns.MxRecord) int {
+			return cmp.Or(cmp.Compare(*a.Preference, *b.Preference), cmp.Compare(*a.Exchange, *b.Exchange))
+		})
 		targets := make([]string, len(mxRecords))
 		for i, mxRecord := range mxRecords {
 			targets[i] = fmt.Sprintf("%d %s", *mxRecord.Preference, *mxRecord.Exchange)
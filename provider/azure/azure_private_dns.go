@@ -20,19 +20,23 @@ package azure
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
-	"time"
 
 	azcoreruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	privatedns "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/plan"
 	"sigs.k8s.io/external-dns/provider"
 )
 
+// maxConcurrentPrivateZoneReads bounds the number of private zones whose record sets are listed in parallel.
+const maxConcurrentPrivateZoneReads = 10
+
 // PrivateZonesClient is an interface of privatedns.PrivateZoneClient that can be stubbed for testing.
 type PrivateZonesClient interface {
 	NewListByResourceGroupPager(resourceGroupName string, options *privatedns.PrivateZonesClientListByResourceGroupOptions) *azcoreruntime.Pager[privatedns.PrivateZonesClientListByResourceGroupResponse]
@@ -59,18 +63,24 @@ type AzurePrivateDNSProvider struct {
 	zonesCache                   *zonesCache[privatedns.PrivateZone]
 	recordSetsClient             PrivateRecordSetsClient
 	maxRetriesCount              int
+	// managedRecordTypes restricts the record types read and written; all supported types if empty
+	managedRecordTypes []string
+	// minTTL is the lowest TTL set on record sets; disabled if 0
+	minTTL endpoint.TTL
+	// defaultTTL is used for records without a TTL
+	defaultTTL endpoint.TTL
 }
 
 // NewAzurePrivateDNSProvider creates a new Azure Private DNS provider.
 //
 // Returns the provider or an error if a provider could not be created.
-func NewAzurePrivateDNSProvider(configFile string, domainFilter *endpoint.DomainFilter, zoneNameFilter *endpoint.DomainFilter, zoneIDFilter provider.ZoneIDFilter, subscriptionID string, resourceGroup string, userAssignedIdentityClientID string, activeDirectoryAuthorityHost string, zonesCacheDuration time.Duration, maxRetriesCount int, dryRun bool) (*AzurePrivateDNSProvider, error) {
-	cfg, err := getConfig(configFile, subscriptionID, resourceGroup, userAssignedIdentityClientID, activeDirectoryAuthorityHost)
+func NewAzurePrivateDNSProvider(azureConfig AzureConfig) (*AzurePrivateDNSProvider, error) {
+	cfg, err := getConfig(azureConfig.ConfigFile, azureConfig.SubscriptionID, azureConfig.ResourceGroup, azureConfig.UserAssignedIdentityClientID, azureConfig.ActiveDirectoryAuthorityHost)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read Azure config file '%s': %w", configFile, err)
+		return nil, fmt.Errorf("failed to read Azure config file '%s': %w", azureConfig.ConfigFile, err)
 	}
 
-	cred, clientOpts, err := getCredentials(*cfg, maxRetriesCount)
+	cred, clientOpts, err := getCredentials(*cfg, azureConfig.MaxRetriesCount, azureConfig.UserAgent)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get credentials: %w", err)
 	}
@@ -84,17 +94,20 @@ func NewAzurePrivateDNSProvider(configFile string, domainFilter *endpoint.Domain
 		return nil, err
 	}
 	return &AzurePrivateDNSProvider{
-		domainFilter:                 domainFilter,
-		zoneNameFilter:               zoneNameFilter,
-		zoneIDFilter:                 zoneIDFilter,
-		dryRun:                       dryRun,
+		domainFilter:                 azureConfig.DomainFilter,
+		zoneNameFilter:               azureConfig.ZoneNameFilter,
+		zoneIDFilter:                 azureConfig.ZoneIDFilter,
+		dryRun:                       azureConfig.DryRun,
 		resourceGroup:                cfg.ResourceGroup,
 		userAssignedIdentityClientID: cfg.UserAssignedIdentityID,
 		activeDirectoryAuthorityHost: cfg.ActiveDirectoryAuthorityHost,
 		zonesClient:                  zonesClient,
-		zonesCache:                   &zonesCache[privatedns.PrivateZone]{duration: zonesCacheDuration},
+		zonesCache:                   newZonesCache[privatedns.PrivateZone](azureConfig.ZonesCacheDuration),
 		recordSetsClient:             recordSetsClient,
-		maxRetriesCount:              maxRetriesCount,
+		maxRetriesCount:              azureConfig.MaxRetriesCount,
+		managedRecordTypes:           azureConfig.ManagedRecordTypes,
+		minTTL:                       endpoint.TTL(azureConfig.PrivateDNSMinTTL),
+		defaultTTL:                   azureConfig.DefaultTTL,
 	}, nil
 }
 
@@ -109,59 +122,95 @@ func (p *AzurePrivateDNSProvider) Records(ctx context.Context) ([]*endpoint.Endp
 
 	log.Debugf("Retrieving Azure Private DNS Records for resource group '%s'", p.resourceGroup)
 
+	// each zone writes to its own slot so that the result keeps the order of the zones
+	zoneEndpoints := make([][]*endpoint.Endpoint, len(zones))
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(maxConcurrentPrivateZoneReads)
+	for i, zone := range zones {
+		eg.Go(func() error {
+			var err error
+			zoneEndpoints[i], err = p.zoneRecords(ctx, zone)
+			return err
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
 	endpoints := make([]*endpoint.Endpoint, 0)
-	for _, zone := range zones {
-		pager := p.recordSetsClient.NewListPager(p.resourceGroup, *zone.Name, &privatedns.RecordSetsClientListOptions{Top: nil})
-		for pager.More() {
-			nextResult, err := pager.NextPage(ctx)
-			if err != nil {
-				return nil, provider.NewSoftErrorf("failed to fetch dns records: %v", err)
-			}
+	for _, eps := range zoneEndpoints {
+		endpoints = append(endpoints, eps...)
+	}
 
-			for _, recordSet := range nextResult.Value {
-				var recordType string
-				if recordSet.Type == nil {
-					log.Debugf("Skipping invalid record set with missing type.")
-					continue
-				}
-				recordType = strings.TrimPrefix(*recordSet.Type, "Microsoft.Network/privateDnsZones/")
+	log.Debugf("Returning %d Azure Private DNS Records for resource group '%s'", len(endpoints), p.resourceGroup)
 
-				var name string
-				if recordSet.Name == nil {
-					log.Debugf("Skipping invalid record set with missing name.")
-					continue
-				}
-				name = formatAzureDNSName(*recordSet.Name, *zone.Name)
+	return endpoints, nil
+}
 
-				if len(p.zoneNameFilter.Filters) > 0 && !p.domainFilter.Match(name) {
-					log.Debugf("Skipping return of record %s because it was filtered out by the specified --domain-filter", name)
-					continue
-				}
-				targets := extractAzurePrivateDNSTargets(recordSet)
-				if len(targets) == 0 {
-					log.Debugf("Failed to extract targets for '%s' with type '%s'.", name, recordType)
-					continue
-				}
+// zoneRecords lists the record sets of a single private zone and converts them to endpoints.
+func (p *AzurePrivateDNSProvider) zoneRecords(ctx context.Context, zone privatedns.PrivateZone) ([]*endpoint.Endpoint, error) {
+	endpoints := make([]*endpoint.Endpoint, 0)
+	pager := p.recordSetsClient.NewListPager(p.resourceGroup, *zone.Name, &privatedns.RecordSetsClientListOptions{Top: nil})
+	for pager.More() {
+		nextResult, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, provider.NewSoftErrorf("failed to fetch dns records: %v", err)
+		}
 
-				var ttl endpoint.TTL
-				if recordSet.Properties.TTL != nil {
-					ttl = endpoint.TTL(*recordSet.Properties.TTL)
-				}
+		for _, recordSet := range nextResult.Value {
+			var recordType string
+			if recordSet.Type == nil {
+				log.Debugf("Skipping invalid record set with missing type.")
+				continue
+			}
+			recordType = strings.TrimPrefix(*recordSet.Type, "Microsoft.Network/privateDnsZones/")
+			if !p.isManagedRecordType(recordType) {
+				continue
+			}
 
-				ep := endpoint.NewEndpointWithTTL(name, recordType, ttl, targets...)
-				log.Debugf(
-					"Found %s record for '%s' with target '%s'.",
-					ep.RecordType,
-					ep.DNSName,
-					ep.Targets,
-				)
-				endpoints = append(endpoints, ep)
+			var name string
+			if recordSet.Name == nil {
+				log.Debugf("Skipping invalid record set with missing name.")
+				continue
+			}
+			name = formatAzureDNSName(*recordSet.Name, *zone.Name)
+
+			if len(p.zoneNameFilter.Filters) > 0 && !p.domainFilter.Match(name) {
+				log.Debugf("Skipping return of record %s because it was filtered out by the specified --domain-filter", name)
+				continue
+			}
+			targets := extractAzurePrivateDNSTargets(recordSet)
+			if len(targets) == 0 {
+				log.Debugf("Failed to extract targets for '%s' with type '%s'.", name, recordType)
+				continue
+			}
+
+			var ttl endpoint.TTL
+			if recordSet.Properties.TTL != nil {
+				ttl = endpoint.TTL(*recordSet.Properties.TTL)
 			}
+
+			ep := endpoint.NewEndpointWithTTL(name, recordType, ttl, targets...)
+			log.Debugf(
+				"Found %s record for '%s' with target '%s'.",
+				ep.RecordType,
+				ep.DNSName,
+				ep.Targets,
+			)
+			endpoints = append(endpoints, ep)
 		}
 	}
+	return endpoints, nil
+}
 
-	log.Debugf("Returning %d Azure Private DNS Records for resource group '%s'", len(endpoints), p.resourceGroup)
-
+// AdjustEndpoints raises configured TTLs below the configured minimum so that they match the
+// record sets that are created.
+func (p *AzurePrivateDNSProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
+	for _, ep := range endpoints {
+		if ep.RecordTTL.IsConfigured() && ep.RecordTTL < p.minTTL {
+			ep.RecordTTL = p.minTTL
+		}
+	}
 	return endpoints, nil
 }
 
@@ -184,10 +233,11 @@ func (p *AzurePrivateDNSProvider) ApplyChanges(ctx context.Context, changes *pla
 
 func (p *AzurePrivateDNSProvider) zones(ctx context.Context) ([]privatedns.PrivateZone, error) {
 	log.Debugf("Retrieving Azure Private DNS zones for Resource Group '%s'", p.resourceGroup)
-	if !p.zonesCache.Expired() {
-		log.Debugf("Using cached Azure Private DNS zones for resource group: %s zone count: %d.", p.resourceGroup, len(p.zonesCache.Get()))
-		return p.zonesCache.Get(), nil
-	}
+	return p.zonesCache.GetOrLoad(func() ([]privatedns.PrivateZone, error) { return p.listZones(ctx) })
+}
+
+// listZones lists the Azure Private DNS zones matching the filters, bypassing the zones cache.
+func (p *AzurePrivateDNSProvider) listZones(ctx context.Context) ([]privatedns.PrivateZone, error) {
 	var zones []privatedns.PrivateZone
 
 	pager := p.zonesClient.NewListByResourceGroupPager(p.resourceGroup, &privatedns.PrivateZonesClientListByResourceGroupOptions{Top: nil})
@@ -208,32 +258,50 @@ func (p *AzurePrivateDNSProvider) zones(ctx context.Context) ([]privatedns.Priva
 		}
 	}
 
-	log.Debugf("Found %d Azure Private DNS zone(s). Updating zones cache", len(zones))
-	p.zonesCache.Reset(zones)
+	log.Debugf("Found %d Azure Private DNS zone(s).", len(zones))
 	return zones, nil
 }
 
 type azurePrivateDNSChangeMap map[string][]*endpoint.Endpoint
 
+// isManagedRecordType reports whether record sets of the type are managed by the provider.
+func (p *AzurePrivateDNSProvider) isManagedRecordType(recordType string) bool {
+	return len(p.managedRecordTypes) == 0 || slices.Contains(p.managedRecordTypes, recordType)
+}
+
 func (p *AzurePrivateDNSProvider) mapChanges(zones []privatedns.PrivateZone, changes *plan.Changes) (azurePrivateDNSChangeMap, azurePrivateDNSChangeMap) {
 	ignored := map[string]bool{}
+	ambiguous := map[string]bool{}
 	deleted := azurePrivateDNSChangeMap{}
 	updated := azurePrivateDNSChangeMap{}
 	zoneNameIDMapper := provider.ZoneIDName{}
 	for _, z := range zones {
 		if z.Name != nil {
-			zoneNameIDMapper.Add(*z.Name, *z.Name)
+			zoneNameIDMapper.Add(*z.Name, strings.ToLower(*z.Name))
 		}
 	}
 	mapChange := func(changeMap azurePrivateDNSChangeMap, change *endpoint.Endpoint) {
-		zone, _ := zoneNameIDMapper.FindZone(change.DNSName)
-		if zone == "" {
+		matches := zoneNameIDMapper.FindZones(strings.ToLower(change.DNSName))
+		if len(matches) == 0 {
 			if _, ok := ignored[change.DNSName]; !ok {
 				ignored[change.DNSName] = true
 				log.Infof("Ignoring changes to '%s' because a suitable Azure Private DNS zone was not found.", change.DNSName)
 			}
 			return
 		}
+		zone := matches[0].ID
+		if len(matches) > 1 && !ambiguous[change.DNSName] {
+			ambiguous[change.DNSName] = true
+			zoneNames := make([]string, 0, len(matches))
+			for _, m := range matches {
+				zoneNames = append(zoneNames, m.Name)
+			}
+			log.Warnf("Record '%s' matches %d Azure Private DNS zones (%s); using '%s'.", change.DNSName, len(matches), strings.Join(zoneNames, ", "), zone)
+		}
+		if !p.isManagedRecordType(change.RecordType) {
+			log.Debugf("Ignoring changes to the %s record of '%s' because the record type is not managed.", change.RecordType, change.DNSName)
+			return
+		}
 		// Ensure the record type is suitable
 		changeMap[zone] = append(changeMap[zone], change)
 	}
@@ -247,6 +315,11 @@ func (p *AzurePrivateDNSProvider) mapChanges(zones []privatedns.PrivateZone, cha
 	}
 
 	for _, change := range changes.UpdateNew() {
+		// A record set left without targets is deleted rather than emptied
+		if len(change.Targets) == 0 {
+			mapChange(deleted, change)
+			continue
+		}
 		mapChange(updated, change)
 	}
 	return deleted, updated
@@ -338,7 +411,7 @@ func (p *AzurePrivateDNSProvider) recordSetNameForZone(zone string, endpoint *en
 	// Remove the zone from the record set
 	name := endpoint.DNSName
 	name = name[:len(name)-len(zone)]
-	name = strings.TrimSuffix(name, ".")
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
 
 	// For root, use @
 	if name == "" {
@@ -348,9 +421,10 @@ func (p *AzurePrivateDNSProvider) recordSetNameForZone(zone string, endpoint *en
 }
 
 func (p *AzurePrivateDNSProvider) newRecordSet(endpoint *endpoint.Endpoint) (privatedns.RecordSet, error) {
-	var ttl int64 = defaultTTL
-	if endpoint.RecordTTL.IsConfigured() {
-		ttl = int64(endpoint.RecordTTL)
+	ttl := int64(provider.TTLOrDefault(endpoint, p.defaultTTL))
+	if ttl < int64(p.minTTL) {
+		log.Warnf("TTL %d of %s record %s is below the minimum, using %d", ttl, endpoint.RecordType, endpoint.DNSName, p.minTTL)
+		ttl = int64(p.minTTL)
 	}
 	switch privatedns.RecordType(endpoint.RecordType) {
 	case privatedns.RecordTypeA:
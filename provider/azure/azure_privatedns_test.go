@@ -18,13 +18,18 @@ package azure
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	azcoreruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	privatedns "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/internal/testutils"
 	"sigs.k8s.io/external-dns/plan"
 	"sigs.k8s.io/external-dns/provider"
 )
@@ -37,6 +42,7 @@ const (
 // and returns static results which are defined per test
 type mockPrivateZonesClient struct {
 	pagingHandler azcoreruntime.PagingHandler[privatedns.PrivateZonesClientListByResourceGroupResponse]
+	listCalls     int
 }
 
 func newMockPrivateZonesClient(zones []*privatedns.PrivateZone) mockPrivateZonesClient {
@@ -58,6 +64,7 @@ func newMockPrivateZonesClient(zones []*privatedns.PrivateZone) mockPrivateZones
 }
 
 func (client *mockPrivateZonesClient) NewListByResourceGroupPager(resourceGroupName string, options *privatedns.PrivateZonesClientListByResourceGroupOptions) *azcoreruntime.Pager[privatedns.PrivateZonesClientListByResourceGroupResponse] {
+	client.listCalls++
 	return azcoreruntime.NewPager(client.pagingHandler)
 }
 
@@ -121,6 +128,17 @@ func (client *mockPrivateRecordSetsClient) CreateOrUpdate(ctx context.Context, r
 	//return parameters, nil
 }
 
+// mockPrivateZoneRecordSetsClient returns the record sets configured for the listed private zone.
+type mockPrivateZoneRecordSetsClient struct {
+	mockPrivateRecordSetsClient
+	recordSets map[string][]*privatedns.RecordSet
+}
+
+func (client *mockPrivateZoneRecordSetsClient) NewListPager(resourceGroupName string, privateZoneName string, options *privatedns.RecordSetsClientListOptions) *azcoreruntime.Pager[privatedns.RecordSetsClientListResponse] {
+	zoneClient := newMockPrivateRecordSectsClient(client.recordSets[privateZoneName])
+	return zoneClient.NewListPager(resourceGroupName, privateZoneName, options)
+}
+
 func createMockPrivateZone(zone string, id string) *privatedns.PrivateZone {
 	return &privatedns.PrivateZone{
 		ID:   to.Ptr(id),
@@ -239,7 +257,7 @@ func newAzurePrivateDNSProvider(domainFilter *endpoint.DomainFilter, zoneNameFil
 		dryRun:           dryRun,
 		resourceGroup:    resourceGroup,
 		zonesClient:      privateZonesClient,
-		zonesCache:       &zonesCache[privatedns.PrivateZone]{duration: 0},
+		zonesCache:       newZonesCache[privatedns.PrivateZone](0),
 		recordSetsClient: privateRecordsClient,
 		maxRetriesCount:  maxRetriesCount,
 	}
@@ -323,6 +341,167 @@ func TestAzurePrivateDNSMultiRecord(t *testing.T) {
 	validateAzureEndpoints(t, actual, expected)
 }
 
+func TestAzurePrivateDNSRecordsMultipleZones(t *testing.T) {
+	zones := []*privatedns.PrivateZone{}
+	recordSets := map[string][]*privatedns.RecordSet{}
+	expected := []*endpoint.Endpoint{}
+	for i := range 25 {
+		zone := fmt.Sprintf("zone%d.example.com", i)
+		zones = append(zones, createMockPrivateZone(zone, "/privateDnsZones/"+zone))
+		recordSets[zone] = []*privatedns.RecordSet{
+			createPrivateMockRecordSet("@", "SOA", "Email: azuredns-hostmaster.microsoft.com"),
+			createPrivateMockRecordSetWithTTL("nginx", endpoint.RecordTypeA, fmt.Sprintf("10.0.0.%d", i), 3600),
+		}
+		expected = append(expected, endpoint.NewEndpointWithTTL("nginx."+zone, endpoint.RecordTypeA, 3600, fmt.Sprintf("10.0.0.%d", i)))
+	}
+
+	zonesClient := newMockPrivateZonesClient(zones)
+	recordSetsClient := &mockPrivateZoneRecordSetsClient{recordSets: recordSets}
+	provider := newAzurePrivateDNSProvider(endpoint.NewDomainFilter([]string{"example.com"}), endpoint.NewDomainFilter([]string{}), provider.NewZoneIDFilter([]string{""}), true, "k8s", &zonesClient, recordSetsClient, 3)
+
+	actual, err := provider.Records(context.Background())
+	require.NoError(t, err)
+
+	// records are returned in the order of the zones
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		assert.Equal(t, expected[i].DNSName, actual[i].DNSName)
+		assert.Equal(t, expected[i].Targets, actual[i].Targets)
+	}
+}
+
+func TestAzurePrivateDNSZonesCacheDuration(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		duration     time.Duration
+		listCalls    int
+		expiredCalls int
+	}{
+		{
+			name:         "cache disabled",
+			duration:     0,
+			listCalls:    2,
+			expiredCalls: 3,
+		},
+		{
+			name:         "cache enabled",
+			duration:     time.Minute,
+			listCalls:    1,
+			expiredCalls: 2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewAzurePrivateDNSProvider(AzureConfig{ConfigFile: "fixtures/config_test.json", DomainFilter: endpoint.NewDomainFilter(nil), ZoneNameFilter: endpoint.NewDomainFilter(nil), ZoneIDFilter: provider.NewZoneIDFilter(nil), ZonesCacheDuration: tc.duration, MaxRetriesCount: 3})
+			if err != nil {
+				t.Fatal(err)
+			}
+			zonesClient := newMockPrivateZonesClient([]*privatedns.PrivateZone{createMockPrivateZone("example.com", "/privateDnsZones/example.com")})
+			p.zonesClient = &zonesClient
+
+			for range 2 {
+				zones, err := p.zones(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+				assert.Len(t, zones, 1)
+			}
+			assert.Equal(t, tc.listCalls, zonesClient.listCalls, "zones should be reused within the cache duration")
+
+			p.zonesCache.age = time.Now().Add(-2 * time.Minute)
+			if _, err := p.zones(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.expiredCalls, zonesClient.listCalls, "zones should be refreshed after the cache expired")
+		})
+	}
+}
+
+func TestAzurePrivateDNSApplyChangesLastTargetRemoved(t *testing.T) {
+	recordsClient := newMockPrivateRecordSectsClient([]*privatedns.RecordSet{
+		createPrivateMockRecordSetMultiWithTTL("multi", endpoint.RecordTypeA, recordTTL, "1.2.3.4", "5.6.7.8"),
+	})
+	zonesClient := newMockPrivateZonesClient([]*privatedns.PrivateZone{createMockPrivateZone("example.com", "/privateDnsZones/example.com")})
+	p := newAzurePrivateDNSProvider(endpoint.NewDomainFilter([]string{"example.com"}), endpoint.NewDomainFilter([]string{}), provider.NewZoneIDFilter([]string{""}), false, "group", &zonesClient, &recordsClient, 3)
+
+	err := p.ApplyChanges(context.Background(), &plan.Changes{
+		Update: []*plan.Update{{
+			Old: endpoint.NewEndpointWithTTL("multi.example.com", endpoint.RecordTypeA, recordTTL, "1.2.3.4", "5.6.7.8"),
+			New: endpoint.NewEndpointWithTTL("multi.example.com", endpoint.RecordTypeA, recordTTL),
+		}},
+	})
+	require.NoError(t, err)
+
+	validateAzureEndpoints(t, recordsClient.deletedEndpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("multi.example.com", endpoint.RecordTypeA, ""),
+	})
+	validateAzureEndpoints(t, recordsClient.updatedEndpoints, []*endpoint.Endpoint{})
+}
+
+func TestAzurePrivateDNSManagedRecordTypes(t *testing.T) {
+	recordsClient := newMockPrivateRecordSectsClient([]*privatedns.RecordSet{
+		createPrivateMockRecordSet("a", endpoint.RecordTypeA, "1.2.3.4"),
+		createPrivateMockRecordSet("txt", endpoint.RecordTypeTXT, "tag"),
+		createPrivateMockRecordSet("cname", endpoint.RecordTypeCNAME, "other.com"),
+		createPrivateMockRecordSet("mail", endpoint.RecordTypeMX, "10 other.com"),
+	})
+	zonesClient := newMockPrivateZonesClient([]*privatedns.PrivateZone{createMockPrivateZone("example.com", "/privateDnsZones/example.com")})
+	p := newAzurePrivateDNSProvider(endpoint.NewDomainFilter([]string{"example.com"}), endpoint.NewDomainFilter([]string{}), provider.NewZoneIDFilter([]string{""}), false, "group", &zonesClient, &recordsClient, 3)
+	p.managedRecordTypes = []string{endpoint.RecordTypeA, endpoint.RecordTypeTXT}
+
+	records, err := p.Records(context.Background())
+	require.NoError(t, err)
+	validateAzureEndpoints(t, records, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("txt.example.com", endpoint.RecordTypeTXT, "tag"),
+	})
+
+	err = p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeA, "5.6.7.8"),
+			endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeTXT, "tag"),
+			endpoint.NewEndpoint("newcname.example.com", endpoint.RecordTypeCNAME, "other.com"),
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("cname.example.com", endpoint.RecordTypeCNAME, "other.com"),
+			endpoint.NewEndpoint("mail.example.com", endpoint.RecordTypeMX, "10 other.com"),
+		},
+	})
+	require.NoError(t, err)
+
+	validateAzureEndpoints(t, recordsClient.deletedEndpoints, []*endpoint.Endpoint{})
+	validateAzureEndpoints(t, recordsClient.updatedEndpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("new.example.com", endpoint.RecordTypeA, provider.DefaultTTL, "5.6.7.8"),
+		endpoint.NewEndpointWithTTL("new.example.com", endpoint.RecordTypeTXT, provider.DefaultTTL, "tag"),
+	})
+}
+
+func TestAzurePrivateDNSMapChangesOverlappingZones(t *testing.T) {
+	zonesClient := newMockPrivateZonesClient([]*privatedns.PrivateZone{
+		createMockPrivateZone("example.com", "/privateDnsZones/example.com"),
+		createMockPrivateZone("sub.example.com", "/privateDnsZones/sub.example.com"),
+	})
+	recordsClient := mockPrivateRecordSetsClient{}
+	p := newAzurePrivateDNSProvider(endpoint.NewDomainFilter([]string{"example.com"}), endpoint.NewDomainFilter([]string{}), provider.NewZoneIDFilter([]string{""}), false, "group", &zonesClient, &recordsClient, 3)
+
+	zones, err := p.zones(context.Background())
+	require.NoError(t, err)
+
+	hook := testutils.LogsUnderTestWithLogLevel(log.WarnLevel, t)
+	_, updated := p.mapChanges(zones, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("www.sub.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+			endpoint.NewEndpoint("www.sub.example.com", endpoint.RecordTypeTXT, "tag"),
+			endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeA, "5.6.7.8"),
+		},
+	})
+
+	assert.Len(t, updated["sub.example.com"], 2)
+	assert.Len(t, updated["example.com"], 1)
+	testutils.TestHelperLogContains("Record 'www.sub.example.com' matches 2 Azure Private DNS zones (sub.example.com, example.com); using 'sub.example.com'.", hook, t)
+	testutils.TestHelperLogNotContains("Record 'www.example.com'", hook, t)
+	assert.Len(t, hook.AllEntries(), 1)
+}
+
 func TestAzurePrivateDNSApplyChanges(t *testing.T) {
 	recordsClient := mockPrivateRecordSetsClient{}
 
@@ -440,6 +619,31 @@ func testAzurePrivateDNSApplyChangesInternal(t *testing.T, dryRun bool, client P
 	}
 }
 
+func TestAzurePrivateDNSMinTTL(t *testing.T) {
+	p := &AzurePrivateDNSProvider{}
+
+	recordSet, err := p.newRecordSet(endpoint.NewEndpointWithTTL("low.example.com", endpoint.RecordTypeA, 1, "1.2.3.4"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), *recordSet.Properties.TTL)
+
+	p.minTTL = 10
+	recordSet, err = p.newRecordSet(endpoint.NewEndpointWithTTL("low.example.com", endpoint.RecordTypeA, 5, "1.2.3.4"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), *recordSet.Properties.TTL)
+
+	recordSet, err = p.newRecordSet(endpoint.NewEndpointWithTTL("high.example.com", endpoint.RecordTypeA, 60, "1.2.3.4"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(60), *recordSet.Properties.TTL)
+
+	endpoints, err := p.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("low.example.com", endpoint.RecordTypeA, 5, "1.2.3.4"),
+		endpoint.NewEndpoint("default.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, endpoint.TTL(10), endpoints[0].RecordTTL)
+	assert.False(t, endpoints[1].RecordTTL.IsConfigured())
+}
+
 func TestAzurePrivateDNSNameFilter(t *testing.T) {
 	provider, err := newMockedAzurePrivateDNSProvider(endpoint.NewDomainFilter([]string{"nginx.example.com"}), endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), true, "k8s",
 		[]*privatedns.PrivateZone{
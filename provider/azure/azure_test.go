@@ -18,12 +18,18 @@ package azure
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	azcoreruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	dns "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/internal/testutils"
@@ -35,6 +41,8 @@ import (
 // and returns static results which are defined per test
 type mockZonesClient struct {
 	pagingHandler azcoreruntime.PagingHandler[dns.ZonesClientListByResourceGroupResponse]
+	zones         []*dns.Zone
+	listCalls     int
 }
 
 func newMockZonesClient(zones []*dns.Zone) mockZonesClient {
@@ -52,19 +60,33 @@ func newMockZonesClient(zones []*dns.Zone) mockZonesClient {
 	}
 	return mockZonesClient{
 		pagingHandler: pagingHandler,
+		zones:         zones,
 	}
 }
 
 func (client *mockZonesClient) NewListByResourceGroupPager(resourceGroupName string, options *dns.ZonesClientListByResourceGroupOptions) *azcoreruntime.Pager[dns.ZonesClientListByResourceGroupResponse] {
+	client.listCalls++
 	return azcoreruntime.NewPager(client.pagingHandler)
 }
 
+func (client *mockZonesClient) Get(ctx context.Context, resourceGroupName string, zoneName string, options *dns.ZonesClientGetOptions) (dns.ZonesClientGetResponse, error) {
+	for _, zone := range client.zones {
+		if *zone.Name == zoneName {
+			return dns.ZonesClientGetResponse{Zone: *zone}, nil
+		}
+	}
+	return dns.ZonesClientGetResponse{}, fmt.Errorf("zone %s not found", zoneName)
+}
+
 // mockZonesClient implements the methods of the Azure DNS RecordSet Client which are used in the Azure Provider
 // and returns static results which are defined per test
 type mockRecordSetsClient struct {
 	pagingHandler    azcoreruntime.PagingHandler[dns.RecordSetsClientListAllByDNSZoneResponse]
 	deletedEndpoints []*endpoint.Endpoint
 	updatedEndpoints []*endpoint.Endpoint
+	// preconditionFailures is the number of conditional writes rejected before one succeeds
+	preconditionFailures  int
+	createOrUpdateOptions []*dns.RecordSetsClientCreateOrUpdateOptions
 }
 
 func newMockRecordSetsClient(recordSets []*dns.RecordSet) mockRecordSetsClient {
@@ -102,6 +124,11 @@ func (client *mockRecordSetsClient) Delete(ctx context.Context, resourceGroupNam
 }
 
 func (client *mockRecordSetsClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, zoneName string, relativeRecordSetName string, recordType dns.RecordType, parameters dns.RecordSet, options *dns.RecordSetsClientCreateOrUpdateOptions) (dns.RecordSetsClientCreateOrUpdateResponse, error) {
+	client.createOrUpdateOptions = append(client.createOrUpdateOptions, options)
+	if client.preconditionFailures > 0 {
+		client.preconditionFailures--
+		return dns.RecordSetsClientCreateOrUpdateResponse{}, &azcore.ResponseError{StatusCode: http.StatusPreconditionFailed}
+	}
 	var ttl endpoint.TTL
 	if parameters.Properties.TTL != nil {
 		ttl = endpoint.TTL(*parameters.Properties.TTL)
@@ -253,7 +280,7 @@ func newAzureProvider(domainFilter *endpoint.DomainFilter, zoneNameFilter *endpo
 		userAssignedIdentityClientID: userAssignedIdentityClientID,
 		activeDirectoryAuthorityHost: activeDirectoryAuthorityHost,
 		zonesClient:                  zonesClient,
-		zonesCache:                   &zonesCache[dns.Zone]{duration: 0},
+		zonesCache:                   newZonesCache[dns.Zone](0),
 		recordSetsClient:             recordsClient,
 		maxRetriesCount:              maxRetriesCount,
 	}
@@ -353,6 +380,62 @@ func TestAzureMultiRecord(t *testing.T) {
 	validateAzureEndpoints(t, actual, expected)
 }
 
+func TestAzureMXTargetsOrder(t *testing.T) {
+	expected := []string{"10 a.example.com", "10 b.example.com", "20 a.example.com"}
+	for _, values := range [][]string{
+		{"20 a.example.com", "10 b.example.com", "10 a.example.com"},
+		{"10 b.example.com", "20 a.example.com", "10 a.example.com"},
+		{"10 a.example.com", "10 b.example.com", "20 a.example.com"},
+	} {
+		recordSet := dns.RecordSet{Properties: mxRecordSetPropertiesGetter(values, recordTTL)}
+		assert.Equal(t, expected, extractAzureTargets(&recordSet))
+		// the stored records must not be reordered
+		assert.Equal(t, values[0], fmt.Sprintf("%d %s", *recordSet.Properties.MxRecords[0].Preference, *recordSet.Properties.MxRecords[0].Exchange))
+	}
+}
+
+func TestAzureRecordMixedCase(t *testing.T) {
+	provider, err := newMockedAzureProvider(endpoint.NewDomainFilter([]string{"example.com"}), endpoint.NewDomainFilter([]string{}), provider.NewZoneIDFilter([]string{""}), true, "k8s", "", "",
+		[]*dns.Zone{
+			createMockZone("Example.COM", "/dnszones/Example.COM"),
+		},
+		[]*dns.RecordSet{
+			createMockRecordSet("@", endpoint.RecordTypeA, "123.123.123.122"),
+			createMockRecordSetWithTTL("NGINX", endpoint.RecordTypeA, "123.123.123.123", 3600),
+			createMockRecordSetWithTTL("Hack", endpoint.RecordTypeCNAME, "hack.azurewebsites.net", 10),
+		}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	actual, err := provider.Records(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeA, "123.123.123.122"),
+		endpoint.NewEndpointWithTTL("nginx.example.com", endpoint.RecordTypeA, 3600, "123.123.123.123"),
+		endpoint.NewEndpointWithTTL("hack.example.com", endpoint.RecordTypeCNAME, 10, "hack.azurewebsites.net"),
+	}
+
+	validateAzureEndpoints(t, actual, expected)
+
+	zones, err := provider.zones(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, updated := provider.mapChanges(zones, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("NGINX.Example.com", endpoint.RecordTypeA, "123.123.123.123"),
+			endpoint.NewEndpoint("example.COM", endpoint.RecordTypeA, "123.123.123.122"),
+		},
+	})
+	assert.Len(t, updated["Example.COM"], 2)
+	assert.Equal(t, "nginx", provider.recordSetNameForZone("Example.COM", updated["Example.COM"][0]))
+	assert.Equal(t, "@", provider.recordSetNameForZone("Example.COM", updated["Example.COM"][1]))
+}
+
 func TestAzureApplyChanges(t *testing.T) {
 	recordsClient := mockRecordSetsClient{}
 
@@ -399,6 +482,367 @@ func TestAzureApplyChangesDryRun(t *testing.T) {
 	validateAzureEndpoints(t, recordsClient.updatedEndpoints, []*endpoint.Endpoint{})
 }
 
+func TestAzureApplyChangesRequireOwnershipMetadata(t *testing.T) {
+	owned := createMockRecordSet("owned", endpoint.RecordTypeA, "1.2.3.4")
+	owned.Properties.Metadata = map[string]*string{ownershipMetadataKey: to.Ptr(ownershipMetadataValue)}
+	recordsClient := newMockRecordSetsClient([]*dns.RecordSet{
+		owned,
+		createMockRecordSet("legacy", endpoint.RecordTypeA, "5.6.7.8"),
+		createMockRecordSet("legacy-gone", endpoint.RecordTypeA, "5.6.7.9"),
+		createMockRecordSet("migrated", endpoint.RecordTypeA, "5.6.7.10"),
+	})
+	zonesClient := newMockZonesClient([]*dns.Zone{createMockZone("example.com", "/dnszones/example.com")})
+	p := newAzureProvider(endpoint.NewDomainFilter([]string{"example.com"}), endpoint.NewDomainFilter([]string{}), provider.NewZoneIDFilter([]string{""}), false, "group", "", "", &zonesClient, &recordsClient, 3)
+	p.requireOwnershipMetadata = true
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeA, "9.9.9.9"),
+		},
+		Update: []*plan.Update{
+			{
+				Old: endpoint.NewEndpoint("owned.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+				New: endpoint.NewEndpoint("owned.example.com", endpoint.RecordTypeA, "1.1.1.1"),
+			},
+			{
+				Old: endpoint.NewEndpoint("legacy.example.com", endpoint.RecordTypeA, "5.6.7.8"),
+				New: endpoint.NewEndpoint("legacy.example.com", endpoint.RecordTypeA, "1.1.1.1"),
+			},
+			{
+				Old: endpoint.NewEndpoint("migrated.example.com", endpoint.RecordTypeA, "5.6.7.10").WithLabel(endpoint.OwnerLabelKey, "default"),
+				New: endpoint.NewEndpoint("migrated.example.com", endpoint.RecordTypeA, "1.1.1.1").WithLabel(endpoint.OwnerLabelKey, "default"),
+			},
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("legacy-gone.example.com", endpoint.RecordTypeA, "5.6.7.9"),
+		},
+	}
+
+	if err := p.ApplyChanges(context.Background(), changes); err != nil {
+		t.Fatal(err)
+	}
+
+	validateAzureEndpoints(t, recordsClient.deletedEndpoints, []*endpoint.Endpoint{})
+	validateAzureEndpoints(t, recordsClient.updatedEndpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("new.example.com", endpoint.RecordTypeA, provider.DefaultTTL, "9.9.9.9"),
+		endpoint.NewEndpointWithTTL("owned.example.com", endpoint.RecordTypeA, provider.DefaultTTL, "1.1.1.1"),
+		endpoint.NewEndpointWithTTL("migrated.example.com", endpoint.RecordTypeA, provider.DefaultTTL, "1.1.1.1"),
+	})
+	assert.True(t, p.listedRecordSets[recordSetKey("example.com", "migrated", endpoint.RecordTypeA)].owned)
+}
+
+func TestAzureManagedRecordTypes(t *testing.T) {
+	recordsClient := newMockRecordSetsClient([]*dns.RecordSet{
+		createMockRecordSet("a", endpoint.RecordTypeA, "1.2.3.4"),
+		createMockRecordSet("txt", endpoint.RecordTypeTXT, "tag"),
+		createMockRecordSet("cname", endpoint.RecordTypeCNAME, "other.com"),
+		createMockRecordSet("ns", endpoint.RecordTypeNS, "ns1.other.com"),
+	})
+	zonesClient := newMockZonesClient([]*dns.Zone{createMockZone("example.com", "/dnszones/example.com")})
+	p := newAzureProvider(endpoint.NewDomainFilter([]string{"example.com"}), endpoint.NewDomainFilter([]string{}), provider.NewZoneIDFilter([]string{""}), false, "group", "", "", &zonesClient, &recordsClient, 3)
+	p.managedRecordTypes = []string{endpoint.RecordTypeA, endpoint.RecordTypeTXT}
+
+	records, err := p.Records(context.Background())
+	require.NoError(t, err)
+	validateAzureEndpoints(t, records, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("txt.example.com", endpoint.RecordTypeTXT, "tag"),
+	})
+
+	err = p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeA, "5.6.7.8"),
+			endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeTXT, "tag"),
+			endpoint.NewEndpoint("newcname.example.com", endpoint.RecordTypeCNAME, "other.com"),
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("cname.example.com", endpoint.RecordTypeCNAME, "other.com"),
+			endpoint.NewEndpoint("ns.example.com", endpoint.RecordTypeNS, "ns1.other.com"),
+		},
+	})
+	require.NoError(t, err)
+
+	validateAzureEndpoints(t, recordsClient.deletedEndpoints, []*endpoint.Endpoint{})
+	validateAzureEndpoints(t, recordsClient.updatedEndpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("new.example.com", endpoint.RecordTypeA, provider.DefaultTTL, "5.6.7.8"),
+		endpoint.NewEndpointWithTTL("new.example.com", endpoint.RecordTypeTXT, provider.DefaultTTL, "tag"),
+	})
+}
+
+func TestAzureNewRecordSetDefaultTTL(t *testing.T) {
+	p := &AzureProvider{defaultTTL: 120}
+
+	recordSet, err := p.newRecordSet(endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.2.3.4"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(120), *recordSet.Properties.TTL)
+
+	recordSet, err = p.newRecordSet(endpoint.NewEndpointWithTTL("foo.example.com", endpoint.RecordTypeA, 60, "1.2.3.4"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(60), *recordSet.Properties.TTL)
+}
+
+func TestAzureApplyChangesEtagMismatch(t *testing.T) {
+	foo := createMockRecordSet("foo", endpoint.RecordTypeA, "1.1.1.1")
+	foo.Etag = to.Ptr("etag-1")
+	recordsClient := newMockRecordSetsClient([]*dns.RecordSet{foo})
+	recordsClient.preconditionFailures = 1
+	zonesClient := newMockZonesClient([]*dns.Zone{createMockZone("example.com", "/dnszones/example.com")})
+	p := newAzureProvider(endpoint.NewDomainFilter([]string{""}), endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), false, "group", "", "", &zonesClient, &recordsClient, 3)
+	update := &plan.Changes{
+		Update: []*plan.Update{{
+			Old: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.1.1.1"),
+			New: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+		}},
+	}
+
+	_, err := p.Records(context.Background())
+	require.NoError(t, err)
+	// another writer changes the record set after it was listed
+	foo.Etag = to.Ptr("etag-2")
+	hook := testutils.LogsUnderTestWithLogLevel(log.ErrorLevel, t)
+	require.NoError(t, p.ApplyChanges(context.Background(), update))
+
+	require.Len(t, recordsClient.createOrUpdateOptions, 1)
+	assert.Equal(t, "etag-1", *recordsClient.createOrUpdateOptions[0].IfMatch, "the etag of the listing should be matched")
+	assert.Empty(t, recordsClient.updatedEndpoints, "the concurrent change should not be overwritten")
+	testutils.TestHelperLogContains("Failed to update A record named 'foo'", hook, t)
+
+	// the next synchronization lists the new etag and retries the change
+	_, err = p.Records(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, p.ApplyChanges(context.Background(), update))
+
+	require.Len(t, recordsClient.createOrUpdateOptions, 2)
+	assert.Equal(t, "etag-2", *recordsClient.createOrUpdateOptions[1].IfMatch)
+	validateAzureEndpoints(t, recordsClient.updatedEndpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("foo.example.com", endpoint.RecordTypeA, provider.DefaultTTL, "1.2.3.4"),
+	})
+}
+
+func TestAzureApplyChangesCreateIfAbsent(t *testing.T) {
+	recordsClient := newMockRecordSetsClient([]*dns.RecordSet{})
+	zonesClient := newMockZonesClient([]*dns.Zone{createMockZone("example.com", "/dnszones/example.com")})
+	p := newAzureProvider(endpoint.NewDomainFilter([]string{""}), endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), false, "group", "", "", &zonesClient, &recordsClient, 3)
+
+	_, err := p.Records(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeA, "5.6.7.8")},
+	}))
+
+	// a record set that was not listed is only created if it is still absent
+	require.Len(t, recordsClient.createOrUpdateOptions, 1)
+	assert.Nil(t, recordsClient.createOrUpdateOptions[0].IfMatch)
+	assert.Equal(t, "*", *recordsClient.createOrUpdateOptions[0].IfNoneMatch)
+}
+
+func TestAzureApplyChangesNSDelegation(t *testing.T) {
+	recordsClient := newMockRecordSetsClient([]*dns.RecordSet{})
+	zonesClient := newMockZonesClient([]*dns.Zone{createMockZone("example.com", "/dnszones/example.com")})
+	p := newAzureProvider(endpoint.NewDomainFilter([]string{"example.com"}), endpoint.NewDomainFilter([]string{}), provider.NewZoneIDFilter([]string{""}), false, "group", "", "", &zonesClient, &recordsClient, 3)
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("sub.example.com", endpoint.RecordTypeNS, "ns1.other.com", "ns2.other.com"),
+		},
+		Update: []*plan.Update{
+			{
+				Old: endpoint.NewEndpoint("example.com", endpoint.RecordTypeNS, "ns1-01.azure-dns.com"),
+				New: endpoint.NewEndpoint("example.com", endpoint.RecordTypeNS, "ns1.other.com"),
+			},
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("example.com", endpoint.RecordTypeNS, "ns1-01.azure-dns.com"),
+		},
+	}
+
+	if err := p.ApplyChanges(context.Background(), changes); err != nil {
+		t.Fatal(err)
+	}
+
+	validateAzureEndpoints(t, recordsClient.deletedEndpoints, []*endpoint.Endpoint{})
+	validateAzureEndpoints(t, recordsClient.updatedEndpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("sub.example.com", endpoint.RecordTypeNS, provider.DefaultTTL, "ns1.other.com", "ns2.other.com"),
+	})
+}
+
+func TestAzureApplyChangesDualStack(t *testing.T) {
+	recordsClient := newMockRecordSetsClient([]*dns.RecordSet{
+		createMockRecordSet("@", endpoint.RecordTypeNS, "ns1-01.azure-dns.com"),
+		createMockRecordSetWithTTL("dual", endpoint.RecordTypeA, "1.2.3.4", int64(provider.DefaultTTL)),
+		createMockRecordSetWithTTL("dual", endpoint.RecordTypeAAAA, "2001::1", int64(provider.DefaultTTL)),
+	})
+	zonesClient := newMockZonesClient([]*dns.Zone{createMockZone("example.com", "/dnszones/example.com")})
+	p := newAzureProvider(endpoint.NewDomainFilter([]string{"example.com"}), endpoint.NewDomainFilter([]string{}), provider.NewZoneIDFilter([]string{""}), false, "group", "", "", &zonesClient, &recordsClient, 3)
+
+	current, err := p.Records(context.Background())
+	assert.NoError(t, err)
+
+	planned := (&plan.Plan{
+		Current: current,
+		Desired: []*endpoint.Endpoint{
+			endpoint.NewEndpointWithTTL("dual.example.com", endpoint.RecordTypeA, provider.DefaultTTL, "1.2.3.4"),
+			endpoint.NewEndpointWithTTL("dual.example.com", endpoint.RecordTypeAAAA, provider.DefaultTTL, "2001::2"),
+		},
+		DomainFilter:   endpoint.MatchAllDomainFilters{endpoint.NewDomainFilter([]string{"example.com"})},
+		ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA},
+	}).Calculate()
+
+	if err := p.ApplyChanges(context.Background(), planned.Changes); err != nil {
+		t.Fatal(err)
+	}
+
+	validateAzureEndpoints(t, recordsClient.deletedEndpoints, []*endpoint.Endpoint{})
+	validateAzureEndpoints(t, recordsClient.updatedEndpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("dual.example.com", endpoint.RecordTypeAAAA, provider.DefaultTTL, "2001::2"),
+	})
+}
+
+func TestAzureApplyChangesLastTargetRemoved(t *testing.T) {
+	recordsClient := newMockRecordSetsClient([]*dns.RecordSet{
+		createMockRecordSetMultiWithTTL("multi", endpoint.RecordTypeA, int64(provider.DefaultTTL), "1.2.3.4", "5.6.7.8"),
+	})
+	zonesClient := newMockZonesClient([]*dns.Zone{createMockZone("example.com", "/dnszones/example.com")})
+	p := newAzureProvider(endpoint.NewDomainFilter([]string{"example.com"}), endpoint.NewDomainFilter([]string{}), provider.NewZoneIDFilter([]string{""}), false, "group", "", "", &zonesClient, &recordsClient, 3)
+
+	if err := p.ApplyChanges(context.Background(), &plan.Changes{
+		Update: []*plan.Update{{
+			Old: endpoint.NewEndpointWithTTL("multi.example.com", endpoint.RecordTypeA, provider.DefaultTTL, "1.2.3.4", "5.6.7.8"),
+			New: endpoint.NewEndpointWithTTL("multi.example.com", endpoint.RecordTypeA, provider.DefaultTTL),
+		}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	validateAzureEndpoints(t, recordsClient.deletedEndpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("multi.example.com", endpoint.RecordTypeA, ""),
+	})
+	validateAzureEndpoints(t, recordsClient.updatedEndpoints, []*endpoint.Endpoint{})
+}
+
+func TestAzureZonesByExplicitIDs(t *testing.T) {
+	const idPrefix = "/subscriptions/sub/resourceGroups/group/providers/Microsoft.Network/dnszones/"
+	zones := []*dns.Zone{
+		createMockZone("example.com", idPrefix+"example.com"),
+		createMockZone("other.com", idPrefix+"other.com"),
+		createMockZone("third.com", idPrefix+"third.com"),
+	}
+
+	for _, tc := range []struct {
+		name          string
+		zoneIDs       []string
+		resourceGroup string
+		expected      []string
+		listCalls     int
+	}{
+		{
+			name:          "full zone IDs",
+			zoneIDs:       []string{idPrefix + "example.com", idPrefix + "third.com"},
+			resourceGroup: "group",
+			expected:      []string{"example.com", "third.com"},
+			listCalls:     0,
+		},
+		{
+			name:          "partial zone IDs",
+			zoneIDs:       []string{idPrefix + "example.com", "third.com"},
+			resourceGroup: "group",
+			expected:      []string{"example.com", "third.com"},
+			listCalls:     1,
+		},
+		{
+			name:          "zone IDs in another resource group",
+			zoneIDs:       []string{idPrefix + "example.com"},
+			resourceGroup: "other-group",
+			expected:      []string{"example.com"},
+			listCalls:     1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			zonesClient := newMockZonesClient(zones)
+			recordsClient := newMockRecordSetsClient(nil)
+			p := newAzureProvider(endpoint.NewDomainFilter([]string{}), endpoint.NewDomainFilter([]string{}), provider.NewZoneIDFilter(tc.zoneIDs), false, tc.resourceGroup, "", "", &zonesClient, &recordsClient, 3)
+
+			result, err := p.zones(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, zone := range result {
+				names = append(names, *zone.Name)
+			}
+			assert.ElementsMatch(t, tc.expected, names)
+			assert.Equal(t, tc.listCalls, zonesClient.listCalls)
+		})
+	}
+}
+
+func TestAzureZoneNameServers(t *testing.T) {
+	withNameServers := createMockZone("example.com", "/dnszones/example.com")
+	withNameServers.Properties = &dns.ZoneProperties{
+		NameServers: []*string{to.Ptr("ns1-01.azure-dns.com."), to.Ptr("ns2-01.azure-dns.net.")},
+	}
+	zones := []*dns.Zone{
+		withNameServers,
+		createMockZone("other.com", "/dnszones/other.com"),
+		createMockZone("filtered.org", "/dnszones/filtered.org"),
+	}
+	provider, err := newMockedAzureProvider(endpoint.NewDomainFilter([]string{"example.com", "other.com"}), endpoint.NewDomainFilter([]string{}), provider.NewZoneIDFilter([]string{""}), false, "k8s", "", "", zones, nil, 3)
+	require.NoError(t, err)
+
+	nameServers, err := provider.ZoneNameServers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"example.com": {"ns1-01.azure-dns.com.", "ns2-01.azure-dns.net."},
+		"other.com":   nil,
+	}, nameServers)
+}
+
+func TestAzureZonesCacheDuration(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		duration     time.Duration
+		listCalls    int
+		expiredCalls int
+	}{
+		{
+			name:         "cache disabled",
+			duration:     0,
+			listCalls:    2,
+			expiredCalls: 3,
+		},
+		{
+			name:         "cache enabled",
+			duration:     time.Minute,
+			listCalls:    1,
+			expiredCalls: 2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewAzureProvider(AzureConfig{ConfigFile: "fixtures/config_test.json", DomainFilter: endpoint.NewDomainFilter(nil), ZoneNameFilter: endpoint.NewDomainFilter(nil), ZoneIDFilter: provider.NewZoneIDFilter(nil), ZonesCacheDuration: tc.duration, MaxRetriesCount: 3})
+			if err != nil {
+				t.Fatal(err)
+			}
+			zonesClient := newMockZonesClient([]*dns.Zone{createMockZone("example.com", "/dnszones/example.com")})
+			p.zonesClient = &zonesClient
+
+			for range 2 {
+				zones, err := p.zones(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+				assert.Len(t, zones, 1)
+			}
+			assert.Equal(t, tc.listCalls, zonesClient.listCalls, "zones should be reused within the cache duration")
+
+			p.zonesCache.age = time.Now().Add(-2 * time.Minute)
+			if _, err := p.zones(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.expiredCalls, zonesClient.listCalls, "zones should be refreshed after the cache expired")
+		})
+	}
+}
+
 func testAzureApplyChangesInternal(t *testing.T, dryRun bool, client RecordSetsClient) {
 	zones := []*dns.Zone{
 		createMockZone("example.com", "/dnszones/example.com"),
@@ -18,6 +18,8 @@ package azure
 
 import (
 	"time"
+
+	log "github.com/sirupsen/logrus"
 )
 
 // zonesCache is a cache for Azure zones(private or public)
@@ -27,6 +29,26 @@ type zonesCache[T any] struct {
 	zones    []T
 }
 
+// newZonesCache returns a cache keeping zones for the given duration, zero disables caching.
+func newZonesCache[T any](duration time.Duration) *zonesCache[T] {
+	return &zonesCache[T]{duration: duration}
+}
+
+// GetOrLoad returns the cached zones unless the cache expired, in which case the zones are
+// loaded anew and cached. The public and the private provider share this behavior.
+func (z *zonesCache[T]) GetOrLoad(load func() ([]T, error)) ([]T, error) {
+	if !z.Expired() {
+		log.Debugf("Using %d cached Azure zone(s).", len(z.zones))
+		return z.Get(), nil
+	}
+	zones, err := load()
+	if err != nil {
+		return nil, err
+	}
+	z.Reset(zones)
+	return zones, nil
+}
+
 // Reset method to reset the zones and update the age. This will be used to update the cache
 // after making a new API call to get the zones.
 func (z *zonesCache[T]) Reset(zones []T) {
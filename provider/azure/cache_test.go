@@ -17,6 +17,7 @@ limitations under the License.
 package azure
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -76,3 +77,30 @@ func TestZonesCache(t *testing.T) {
 		})
 	}
 }
+
+func TestZonesCacheGetOrLoad(t *testing.T) {
+	zoneName := "example.com"
+	loads := 0
+	load := func() ([]dns.Zone, error) {
+		loads++
+		return []dns.Zone{{Name: &zoneName}}, nil
+	}
+
+	z := newZonesCache[dns.Zone](30 * time.Second)
+	for range 2 {
+		zones, err := z.GetOrLoad(load)
+		assert.NoError(t, err)
+		assert.Len(t, zones, 1)
+	}
+	assert.Equal(t, 1, loads, "zones should be loaded once within the cache duration")
+
+	z.age = time.Now().Add(-time.Minute)
+	_, err := z.GetOrLoad(load)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, loads, "zones should be loaded again after the cache expired")
+
+	z.age = time.Now().Add(-time.Minute)
+	_, err = z.GetOrLoad(func() ([]dns.Zone, error) { return nil, errors.New("boom") })
+	assert.Error(t, err)
+	assert.Len(t, z.Get(), 1, "a failed load should keep the cached zones")
+}
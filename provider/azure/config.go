@@ -105,7 +105,8 @@ func (p *customHeaderPolicy) Do(req *policy.Request) (*http.Response, error) {
 func CustomHeaderPolicynew() policy.Policy { return &customHeaderPolicy{} }
 
 // getCredentials retrieves Azure API credentials.
-func getCredentials(cfg config, maxRetries int) (azcore.TokenCredential, *arm.ClientOptions, error) {
+// A non-empty userAgent is sent as the application ID of the user agent of all Azure API calls.
+func getCredentials(cfg config, maxRetries int, userAgent string) (azcore.TokenCredential, *arm.ClientOptions, error) {
 	cloudCfg, err := getCloudConfiguration(cfg.Cloud)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get cloud configuration: %w", err)
@@ -125,6 +126,9 @@ func getCredentials(cfg config, maxRetries int) (azcore.TokenCredential, *arm.Cl
 		PerCallPolicies: []policy.Policy{
 			CustomHeaderPolicynew(),
 		},
+		Telemetry: policy.TelemetryOptions{
+			ApplicationID: userAgent,
+		},
 	}
 	log.Debugf("Configured Azure client with maxRetries: %d", clientOpts.Retry.MaxRetries)
 	armClientOpts := &arm.ClientOptions{
@@ -31,6 +31,10 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	azruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/provider"
 )
 
 func TestGetCloudConfiguration(t *testing.T) {
@@ -68,6 +72,26 @@ func TestOverrideConfiguration(t *testing.T) {
 	assert.Equal(t, "aad-endpoint-override", cfg.ActiveDirectoryAuthorityHost)
 }
 
+func TestUserAgent(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	configFile := path.Join(path.Dir(filename), "fixtures/config_test.json")
+	cfg, err := getConfig(configFile, "", "", "", "")
+	require.NoError(t, err)
+
+	_, clientOpts, err := getCredentials(*cfg, 3, "external-dns-test")
+	require.NoError(t, err)
+	assert.Equal(t, "external-dns-test", clientOpts.Telemetry.ApplicationID)
+
+	_, clientOpts, err = getCredentials(*cfg, 3, "")
+	require.NoError(t, err)
+	assert.Empty(t, clientOpts.Telemetry.ApplicationID)
+
+	_, err = NewAzureProvider(AzureConfig{ConfigFile: configFile, DomainFilter: endpoint.NewDomainFilter(nil), ZoneNameFilter: endpoint.NewDomainFilter(nil), ZoneIDFilter: provider.NewZoneIDFilter(nil), MaxRetriesCount: 3, UserAgent: "external-dns-test"})
+	require.NoError(t, err)
+	_, err = NewAzurePrivateDNSProvider(AzureConfig{ConfigFile: configFile, DomainFilter: endpoint.NewDomainFilter(nil), ZoneNameFilter: endpoint.NewDomainFilter(nil), ZoneIDFilter: provider.NewZoneIDFilter(nil), MaxRetriesCount: 3, UserAgent: "external-dns-test"})
+	require.NoError(t, err)
+}
+
 // Test for custom header policy
 type transportFunc func(*http.Request) (*http.Response, error)
 
@@ -0,0 +1,43 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+// BatchBySize splits changes into consecutive batches whose total size, as estimated by size,
+// does not exceed maxSize, preserving their order. A change larger than maxSize on its own is
+// put into a batch by itself. A maxSize of zero or less puts all changes into a single batch.
+func BatchBySize[T any](changes []T, maxSize int, size func(T) int) [][]T {
+	if len(changes) == 0 {
+		return nil
+	}
+	if maxSize <= 0 {
+		return [][]T{changes}
+	}
+
+	var batches [][]T
+	var batch []T
+	batchSize := 0
+	for _, change := range changes {
+		s := size(change)
+		if len(batch) > 0 && batchSize+s > maxSize {
+			batches = append(batches, batch)
+			batch, batchSize = nil, 0
+		}
+		batch = append(batch, change)
+		batchSize += s
+	}
+	return append(batches, batch)
+}
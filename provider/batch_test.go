@@ -0,0 +1,69 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchBySize(t *testing.T) {
+	size := func(s string) int { return len(s) }
+	large := strings.Repeat("x", 300)
+	for _, tc := range []struct {
+		name     string
+		changes  []string
+		maxSize  int
+		expected [][]string
+	}{
+		{
+			name:     "no changes",
+			maxSize:  100,
+			expected: nil,
+		},
+		{
+			name:     "fits into one batch",
+			changes:  []string{"a", "bb", "ccc"},
+			maxSize:  6,
+			expected: [][]string{{"a", "bb", "ccc"}},
+		},
+		{
+			name:     "split preserving order",
+			changes:  []string{"aaaa", "bb", "cccc", "d"},
+			maxSize:  6,
+			expected: [][]string{{"aaaa", "bb"}, {"cccc", "d"}},
+		},
+		{
+			name:     "oversized change on its own",
+			changes:  []string{"a", large, "b"},
+			maxSize:  100,
+			expected: [][]string{{"a"}, {large}, {"b"}},
+		},
+		{
+			name:     "splitting disabled",
+			changes:  []string{"a", large, "b"},
+			maxSize:  0,
+			expected: [][]string{{"a", large, "b"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, BatchBySize(tc.changes, tc.maxSize, size))
+		})
+	}
+}
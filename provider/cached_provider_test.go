@@ -55,6 +55,10 @@ func (p *testProviderFunc) GetDomainFilter() endpoint.DomainFilterInterface {
 	return p.getDomainFilter()
 }
 
+func (p *testProviderFunc) SupportedRecordTypes() []string {
+	return nil
+}
+
 func recordsNotCalled(t *testing.T) func(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	return func(ctx context.Context) ([]*endpoint.Endpoint, error) {
 		t.Errorf("unexpected call to Records")
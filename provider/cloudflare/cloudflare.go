@@ -697,7 +697,7 @@ func (p *CloudFlareProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]
 		if proxied {
 			e.RecordTTL = 0
 		}
-		e.SetProviderSpecificProperty(annotations.CloudflareProxiedKey, strconv.FormatBool(proxied))
+		e.SetBoolProviderSpecificProperty(annotations.CloudflareProxiedKey, proxied)
 
 		if p.CustomHostnamesConfig.Enabled {
 			// sort custom hostnames in annotation to properly detect changes
@@ -907,16 +907,10 @@ func getCustomHostnamesSSLOptions(customHostnamesConfig CustomHostnamesConfig) *
 func shouldBeProxied(ep *endpoint.Endpoint, proxiedByDefault bool) bool {
 	proxied := proxiedByDefault
 
-	for _, v := range ep.ProviderSpecific {
-		if v.Name == annotations.CloudflareProxiedKey {
-			b, err := strconv.ParseBool(v.Value)
-			if err != nil {
-				log.Errorf("Failed to parse annotation [%q]: %v", annotations.CloudflareProxiedKey, err)
-			} else {
-				proxied = b
-			}
-			break
-		}
+	if b, ok, err := ep.GetBoolProviderSpecificProperty(annotations.CloudflareProxiedKey); err != nil {
+		log.Errorf("Failed to parse annotation [%q]: %v", annotations.CloudflareProxiedKey, err)
+	} else if ok {
+		proxied = b
 	}
 
 	if recordTypeProxyNotSupported[ep.RecordType] {
@@ -926,11 +920,8 @@ func shouldBeProxied(ep *endpoint.Endpoint, proxiedByDefault bool) bool {
 }
 
 func getEndpointCustomHostnames(ep *endpoint.Endpoint) []string {
-	for _, v := range ep.ProviderSpecific {
-		if v.Name == annotations.CloudflareCustomHostnameKey {
-			customHostnames := strings.Split(v.Value, ",")
-			return customHostnames
-		}
+	if v, ok := ep.GetProviderSpecificProperty(annotations.CloudflareCustomHostnameKey); ok {
+		return strings.Split(v, ",")
 	}
 	return []string{}
 }
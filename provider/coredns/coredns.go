@@ -24,6 +24,8 @@ import (
 	"math/rand"
 	"net"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -42,6 +44,17 @@ const (
 	etcdTimeout = 5 * time.Second
 
 	randomPrefixLabel = "prefix"
+
+	// providerSpecificGroup is the provider specific property holding the group of a service,
+	// set through the external-dns.alpha.kubernetes.io/coredns-group annotation.
+	providerSpecificGroup = "coredns/group"
+	// providerSpecificPriority and providerSpecificWeight are the provider specific properties holding
+	// the priority and weight of a service, set through the coredns-priority and coredns-weight annotations.
+	providerSpecificPriority = "coredns/priority"
+	providerSpecificWeight   = "coredns/weight"
+	// providerSpecificTargetWeightPrefix prefixes the provider specific properties holding the weight
+	// of a single target, e.g. coredns/weight/1.2.3.4, which overrides the weight of the endpoint.
+	providerSpecificTargetWeightPrefix = providerSpecificWeight + "/"
 )
 
 // coreDNSClient is an interface to work with CoreDNS service records in etcd
@@ -51,12 +64,37 @@ type coreDNSClient interface {
 	DeleteService(key string) error
 }
 
+// batchCoreDNSClient is implemented by clients able to apply several service changes in a single request.
+type batchCoreDNSClient interface {
+	coreDNSClient
+	// BatchSize returns the maximum number of etcd operations applied in a single request.
+	BatchSize() int
+	ApplyServices(ops []serviceOp) error
+}
+
+// serviceOp is a change of a single etcd key. The service is saved if set, otherwise
+// the key is deleted along with all keys below it.
+type serviceOp struct {
+	service *Service
+	key     string
+}
+
+// txnOps returns the number of etcd transaction operations of the change: deletions
+// remove the key and the keys below it in two operations.
+func (op serviceOp) txnOps() int {
+	if op.service == nil {
+		return 2
+	}
+	return 1
+}
+
 type coreDNSProvider struct {
 	provider.BaseProvider
-	dryRun        bool
-	coreDNSPrefix string
-	domainFilter  *endpoint.DomainFilter
-	client        coreDNSClient
+	dryRun          bool
+	coreDNSPrefix   string
+	defaultPriority int
+	domainFilter    *endpoint.DomainFilter
+	client          coreDNSClient
 }
 
 // Service represents CoreDNS etcd record
@@ -81,6 +119,10 @@ type Service struct {
 	// answer.
 	Group string `json:"group,omitempty"`
 
+	// RecordType is the type of the record the service was written for. It is not used by
+	// CoreDNS; services written without it are typed by guessing from their host.
+	RecordType string `json:"recordtype,omitempty"`
+
 	// Etcd key where we found this service and ignored from json un-/marshaling
 	Key string `json:"-"`
 }
@@ -88,9 +130,11 @@ type Service struct {
 type etcdClient struct {
 	client *etcdcv3.Client
 	ctx    context.Context
+	// maximum number of changes applied in a single transaction, 0 or 1 disables batching
+	batchSize int
 }
 
-var _ coreDNSClient = etcdClient{}
+var _ batchCoreDNSClient = etcdClient{}
 
 // GetServices GetService return all Service records stored in etcd stored anywhere under the given key (recursively)
 func (c etcdClient) GetServices(prefix string) ([]*Service, error) {
@@ -143,12 +187,48 @@ func (c etcdClient) SaveService(service *Service) error {
 	return nil
 }
 
-// DeleteService deletes service record from etcd
+// DeleteService deletes the service record of the key and those below it from etcd
 func (c etcdClient) DeleteService(key string) error {
 	ctx, cancel := context.WithTimeout(c.ctx, etcdTimeout)
 	defer cancel()
 
-	_, err := c.client.Delete(ctx, key, etcdcv3.WithPrefix())
+	if _, err := c.client.Delete(ctx, key); err != nil {
+		return err
+	}
+	_, err := c.client.Delete(ctx, childKeyPrefix(key), etcdcv3.WithPrefix())
+	return err
+}
+
+// childKeyPrefix returns the prefix of the keys below the key. Unlike the key itself, it does not
+// match the keys of sibling labels sharing the key as a prefix, e.g. /skydns/com/example/ab for
+// /skydns/com/example/a.
+func childKeyPrefix(key string) string {
+	return strings.TrimSuffix(key, "/") + "/"
+}
+
+// BatchSize returns the maximum number of operations applied in a single transaction
+func (c etcdClient) BatchSize() int {
+	return c.batchSize
+}
+
+// ApplyServices saves and deletes services in a single etcd transaction
+func (c etcdClient) ApplyServices(ops []serviceOp) error {
+	ctx, cancel := context.WithTimeout(c.ctx, etcdTimeout)
+	defer cancel()
+
+	txnOps := make([]etcdcv3.Op, 0, 2*len(ops))
+	for _, op := range ops {
+		if op.service == nil {
+			txnOps = append(txnOps, etcdcv3.OpDelete(op.key), etcdcv3.OpDelete(childKeyPrefix(op.key), etcdcv3.WithPrefix()))
+			continue
+		}
+		value, err := json.Marshal(op.service)
+		if err != nil {
+			return err
+		}
+		txnOps = append(txnOps, etcdcv3.OpPut(op.service.Key, string(value)))
+	}
+	_, err := c.client.Txn(ctx).Then(txnOps...).Commit()
 	return err
 }
 
@@ -162,14 +242,21 @@ func getETCDConfig() (*etcdcv3.Config, error) {
 	firstURL := strings.ToLower(etcdURLs[0])
 	etcdUsername := os.Getenv("ETCD_USERNAME")
 	etcdPassword := os.Getenv("ETCD_PASSWORD")
-	if strings.HasPrefix(firstURL, "http://") {
+	// ETCD_FORCE_TLS applies the TLS configuration even when the URLs use the http:// scheme, which
+	// the etcd client would otherwise connect to without TLS
+	forceTLSStr := strings.ToLower(os.Getenv("ETCD_FORCE_TLS"))
+	forceTLS := forceTLSStr == "true" || forceTLSStr == "yes" || forceTLSStr == "1"
+	if strings.HasPrefix(firstURL, "http://") && !forceTLS {
 		return &etcdcv3.Config{Endpoints: etcdURLs, Username: etcdUsername, Password: etcdPassword}, nil
-	} else if strings.HasPrefix(firstURL, "https://") {
+	} else if strings.HasPrefix(firstURL, "https://") || strings.HasPrefix(firstURL, "http://") {
 		tlsConfig, err := tlsutils.CreateTLSConfig("ETCD")
 		if err != nil {
 			return nil, err
 		}
 		log.Debug("using TLS for etcd")
+		if forceTLS {
+			etcdURLs = httpsURLs(etcdURLs)
+		}
 		return &etcdcv3.Config{
 			Endpoints: etcdURLs,
 			TLS:       tlsConfig,
@@ -181,39 +268,73 @@ func getETCDConfig() (*etcdcv3.Config, error) {
 	}
 }
 
+// httpsURLs returns the URLs with the http:// scheme replaced by https://.
+func httpsURLs(urls []string) []string {
+	rewritten := make([]string, len(urls))
+	for i, url := range urls {
+		if strings.HasPrefix(strings.ToLower(url), "http://") {
+			url = "https://" + url[len("http://"):]
+		}
+		rewritten[i] = url
+	}
+	return rewritten
+}
+
+// getETCDBatchSize returns the maximum number of operations applied in a single transaction,
+// configured by ETCD_BATCH_SIZE. Deletions take two operations, so the batch size must not
+// exceed the --max-txn-ops limit of the etcd server (128 by default). Batching is disabled by default.
+func getETCDBatchSize() (int, error) {
+	batchSizeStr := os.Getenv("ETCD_BATCH_SIZE")
+	if batchSizeStr == "" {
+		return 0, nil
+	}
+	batchSize, err := strconv.Atoi(batchSizeStr)
+	if err != nil || batchSize < 0 {
+		return 0, fmt.Errorf("invalid ETCD_BATCH_SIZE %q, must be a non-negative integer", batchSizeStr)
+	}
+	return batchSize, nil
+}
+
 // the newETCDClient is an etcd client constructor
 func newETCDClient() (coreDNSClient, error) {
 	cfg, err := getETCDConfig()
 	if err != nil {
 		return nil, err
 	}
+	batchSize, err := getETCDBatchSize()
+	if err != nil {
+		return nil, err
+	}
 	c, err := etcdcv3.New(*cfg)
 	if err != nil {
 		return nil, err
 	}
-	return etcdClient{c, context.Background()}, nil
+	return etcdClient{client: c, ctx: context.Background(), batchSize: batchSize}, nil
 }
 
-// NewCoreDNSProvider is a CoreDNS provider constructor
-func NewCoreDNSProvider(domainFilter *endpoint.DomainFilter, prefix string, dryRun bool) (provider.Provider, error) {
+// NewCoreDNSProvider is a CoreDNS provider constructor. The default priority is applied to services
+// whose endpoint does not carry a priority, a value of 0 selects the CoreDNS default of 10.
+func NewCoreDNSProvider(domainFilter *endpoint.DomainFilter, prefix string, defaultPriority int, dryRun bool) (provider.Provider, error) {
 	client, err := newETCDClient()
 	if err != nil {
 		return nil, err
 	}
 
 	return coreDNSProvider{
-		client:        client,
-		dryRun:        dryRun,
-		coreDNSPrefix: prefix,
-		domainFilter:  domainFilter,
+		client:          client,
+		dryRun:          dryRun,
+		coreDNSPrefix:   prefix,
+		defaultPriority: defaultPriority,
+		// wildcard entries like *.svc.cluster.local manage the records below the wildcard
+		domainFilter: domainFilter.WithWildcardSubdomains(),
 	}, nil
 }
 
-// findEp takes an Endpoint slice and looks for an element in it. If found it will
-// return Endpoint, otherwise it will return nil and a bool of false.
-func findEp(slice []*endpoint.Endpoint, dnsName string) (*endpoint.Endpoint, bool) {
+// findEp takes an Endpoint slice and looks for an element of the given name and record type in it.
+// If found it will return Endpoint, otherwise it will return nil and a bool of false.
+func findEp(slice []*endpoint.Endpoint, dnsName, recordType string) (*endpoint.Endpoint, bool) {
 	for _, item := range slice {
-		if item.DNSName == dnsName {
+		if item.DNSName == dnsName && item.RecordType == recordType {
 			return item, true
 		}
 	}
@@ -235,36 +356,46 @@ func findLabelInTargets(targets []string, label string) (string, bool) {
 // it may be mapped to one or two records of type A, CNAME, TXT, A+TXT, CNAME+TXT
 func (p coreDNSProvider) Records(_ context.Context) ([]*endpoint.Endpoint, error) {
 	var result []*endpoint.Endpoint
+	weights := map[*endpoint.Endpoint]map[string]int{}
 	services, err := p.client.GetServices(p.coreDNSPrefix)
 	if err != nil {
 		return nil, err
 	}
 	for _, service := range services {
-		domains := strings.Split(strings.TrimPrefix(service.Key, p.coreDNSPrefix), "/")
-		reverse(domains)
-		dnsName := strings.Join(domains[service.TargetStrip:], ".")
+		dnsName, prefix, ok := p.dnsNameForKey(service)
+		if !ok {
+			log.Warnf("Skipping service with key %s which does not map to a DNS name", service.Key)
+			continue
+		}
 		if !p.domainFilter.Match(dnsName) {
 			continue
 		}
 		log.Debugf("Getting service (%v) with service host (%s)", service, service.Host)
-		prefix := strings.Join(domains[:service.TargetStrip], ".")
 		if service.Host != "" {
-			ep, found := findEp(result, dnsName)
+			ep, found := findEp(result, dnsName, serviceRecordType(service))
 			if found {
 				ep.Targets = append(ep.Targets, service.Host)
 				log.Debugf("Extending ep (%s) with new service host (%s)", ep, service.Host)
 			} else {
 				ep = endpoint.NewEndpointWithTTL(
 					dnsName,
-					guessRecordType(service.Host),
+					serviceRecordType(service),
 					endpoint.TTL(service.TTL),
 					service.Host,
 				)
+				if service.Group != "" {
+					ep.WithProviderSpecific(providerSpecificGroup, service.Group)
+				}
+				if service.Priority != 0 && service.Priority != p.defaultServicePriority() {
+					ep.WithProviderSpecific(providerSpecificPriority, strconv.Itoa(service.Priority))
+				}
+				weights[ep] = map[string]int{}
 				log.Debugf("Creating new ep (%s) with new service host (%s)", ep, service.Host)
 			}
 			ep.Labels["originalText"] = service.Text
 			ep.Labels[randomPrefixLabel] = prefix
 			ep.Labels[service.Host] = prefix
+			weights[ep][service.Host] = service.Weight
 			result = append(result, ep)
 		}
 		if service.Text != "" {
@@ -273,36 +404,85 @@ func (p coreDNSProvider) Records(_ context.Context) ([]*endpoint.Endpoint, error
 				endpoint.RecordTypeTXT,
 				service.Text,
 			)
+			if service.Group != "" {
+				ep.WithProviderSpecific(providerSpecificGroup, service.Group)
+			}
 			ep.Labels[randomPrefixLabel] = prefix
 			result = append(result, ep)
 		}
 	}
+	for ep, targetWeights := range weights {
+		setTargetWeights(ep, targetWeights)
+	}
 	return result, nil
 }
 
+// dnsNameForKey reconstructs the DNS name of a service and the random prefix
+// stripped from it. Keys with empty labels or which strip the whole name are
+// rejected, as they don't map back to a single DNS name.
+func (p coreDNSProvider) dnsNameForKey(service *Service) (string, string, bool) {
+	domains := strings.Split(strings.TrimPrefix(service.Key, p.coreDNSPrefix), "/")
+	if service.TargetStrip < 0 || service.TargetStrip >= len(domains) || slices.Contains(domains, "") {
+		return "", "", false
+	}
+	reverse(domains)
+	return strings.Join(domains[service.TargetStrip:], "."), strings.Join(domains[:service.TargetStrip], "."), true
+}
+
+// AdjustEndpoints drops priorities equal to the default priority and zero weights, which are
+// not returned by Records, so that they do not cause perpetual updates. Per-target weights are
+// normalized the same way Records returns them.
+func (p coreDNSProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
+	for _, ep := range endpoints {
+		if value, ok := ep.GetProviderSpecificProperty(providerSpecificPriority); ok {
+			if v, err := strconv.Atoi(value); err == nil && v == p.defaultServicePriority() {
+				ep.DeleteProviderSpecificProperty(providerSpecificPriority)
+			}
+		}
+		if hasTargetWeights(ep) {
+			setTargetWeights(ep, p.targetWeights(ep))
+			continue
+		}
+		if value, ok := ep.GetProviderSpecificProperty(providerSpecificWeight); ok {
+			if v, err := strconv.Atoi(value); err == nil && v == 0 {
+				ep.DeleteProviderSpecificProperty(providerSpecificWeight)
+			}
+		}
+	}
+	return endpoints, nil
+}
+
 func (p coreDNSProvider) ApplyChanges(_ context.Context, changes *plan.Changes) error {
+	w := newServiceWriter(p.client, p.dryRun)
 	grouped := p.groupEndpoints(changes)
 
 	for dnsName, group := range grouped {
-		if !p.domainFilter.Match(dnsName) {
-			log.Debugf("Skipping record %q due to domain filter", dnsName)
-			continue
-		}
-		if err := p.applyGroup(dnsName, group); err != nil {
+		if err := p.applyGroup(w, dnsName, group); err != nil {
 			return err
 		}
 	}
 
-	return p.deleteEndpoints(changes.Delete)
+	if err := p.deleteEndpoints(w, changes.Delete); err != nil {
+		return err
+	}
+	return w.flush()
 }
 
+// groupEndpoints groups the created and updated endpoints by DNS name. Endpoints
+// which do not match the domain filter, including excluded domains, are skipped.
 func (p coreDNSProvider) groupEndpoints(changes *plan.Changes) map[string][]*endpoint.Endpoint {
 	grouped := make(map[string][]*endpoint.Endpoint)
 	for _, ep := range changes.Create {
+		if !p.matchDomainFilter(ep.DNSName) {
+			continue
+		}
 		grouped[ep.DNSName] = append(grouped[ep.DNSName], ep)
 	}
 	updateOld := changes.UpdateOld()
 	for i, ep := range changes.UpdateNew() {
+		if !p.matchDomainFilter(ep.DNSName) {
+			continue
+		}
 		ep.Labels = updateOld[i].Labels
 		log.Debugf("Updating labels (%s) with old labels(%s)", ep.Labels, updateOld[i].Labels)
 		grouped[ep.DNSName] = append(grouped[ep.DNSName], ep)
@@ -310,12 +490,37 @@ func (p coreDNSProvider) groupEndpoints(changes *plan.Changes) map[string][]*end
 	return grouped
 }
 
-func (p coreDNSProvider) applyGroup(dnsName string, group []*endpoint.Endpoint) error {
+// matchDomainFilter reports whether dnsName is managed by this provider, i.e. it is
+// included by the domain filter, not part of its exclusion list and has a valid etcd key.
+func (p coreDNSProvider) matchDomainFilter(dnsName string) bool {
+	if !p.domainFilter.Match(dnsName) {
+		log.Debugf("Skipping record %q due to domain filter", dnsName)
+		return false
+	}
+	if !validEtcdName(dnsName) {
+		log.Warnf("Skipping record %q which cannot be stored unambiguously in etcd", dnsName)
+		return false
+	}
+	return true
+}
+
+// validEtcdName reports whether dnsName maps to its own etcd key. Empty labels
+// and labels containing a slash would alias the key of another DNS name.
+func validEtcdName(dnsName string) bool {
+	for _, label := range strings.Split(dnsName, ".") {
+		if label == "" || strings.Contains(label, "/") {
+			return false
+		}
+	}
+	return true
+}
+
+func (p coreDNSProvider) applyGroup(w *serviceWriter, dnsName string, group []*endpoint.Endpoint) error {
 	var services []*Service
 
 	for _, ep := range group {
 		if ep.RecordType != endpoint.RecordTypeTXT {
-			srvs, err := p.createServicesForEndpoint(dnsName, ep)
+			srvs, err := p.createServicesForEndpoint(w, dnsName, ep)
 			if err != nil {
 				return err
 			}
@@ -326,11 +531,7 @@ func (p coreDNSProvider) applyGroup(dnsName string, group []*endpoint.Endpoint)
 	services = p.updateTXTRecords(dnsName, group, services)
 
 	for _, service := range services {
-		log.Infof("Add/set key %s to Host=%s, Text=%s, TTL=%d", service.Key, service.Host, service.Text, service.TTL)
-		if p.dryRun {
-			continue
-		}
-		if err := p.client.SaveService(service); err != nil {
+		if err := w.save(service); err != nil {
 			return err
 		}
 	}
@@ -338,9 +539,12 @@ func (p coreDNSProvider) applyGroup(dnsName string, group []*endpoint.Endpoint)
 	return nil
 }
 
-func (p coreDNSProvider) createServicesForEndpoint(dnsName string, ep *endpoint.Endpoint) ([]*Service, error) {
+func (p coreDNSProvider) createServicesForEndpoint(w *serviceWriter, dnsName string, ep *endpoint.Endpoint) ([]*Service, error) {
 	var services []*Service
 
+	group, _ := ep.GetProviderSpecificProperty(providerSpecificGroup)
+	servicePriority := p.intProperty(ep, providerSpecificPriority, p.defaultServicePriority())
+	weights := p.targetWeights(ep)
 	for _, target := range ep.Targets {
 		prefix := ep.Labels[target]
 		if prefix == "" {
@@ -353,6 +557,10 @@ func (p coreDNSProvider) createServicesForEndpoint(dnsName string, ep *endpoint.
 			Key:         p.etcdKeyFor(prefix + "." + dnsName),
 			TargetStrip: strings.Count(prefix, ".") + 1,
 			TTL:         uint32(ep.RecordTTL),
+			Group:       group,
+			Priority:    servicePriority,
+			Weight:      weights[target],
+			RecordType:  ep.RecordType,
 		}
 		services = append(services, &service)
 		ep.Labels[target] = prefix
@@ -365,11 +573,7 @@ func (p coreDNSProvider) createServicesForEndpoint(dnsName string, ep *endpoint.
 		}
 		if _, ok := findLabelInTargets(ep.Targets, label); !ok {
 			key := p.etcdKeyFor(labelPrefix + "." + dnsName)
-			log.Infof("Delete key %s", key)
-			if p.dryRun {
-				continue
-			}
-			if err := p.client.DeleteService(key); err != nil {
+			if err := w.delete(key); err != nil {
 				return nil, err
 			}
 		}
@@ -377,6 +581,71 @@ func (p coreDNSProvider) createServicesForEndpoint(dnsName string, ep *endpoint.
 	return services, nil
 }
 
+// targetWeights returns the weight of each target of the endpoint, taken from its per-target
+// property if set and from the weight of the endpoint otherwise.
+func (p coreDNSProvider) targetWeights(ep *endpoint.Endpoint) map[string]int {
+	weight := p.intProperty(ep, providerSpecificWeight, 0)
+	weights := make(map[string]int, len(ep.Targets))
+	for _, target := range ep.Targets {
+		weights[target] = p.intProperty(ep, providerSpecificTargetWeightPrefix+target, weight)
+	}
+	return weights
+}
+
+// hasTargetWeights reports whether the endpoint carries a weight for any single target.
+func hasTargetWeights(ep *endpoint.Endpoint) bool {
+	for _, property := range ep.ProviderSpecific {
+		if strings.HasPrefix(property.Name, providerSpecificTargetWeightPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// setTargetWeights replaces the weight properties of the endpoint with the given target weights:
+// a single endpoint weight if all targets share it, or one property per target otherwise.
+func setTargetWeights(ep *endpoint.Endpoint, weights map[string]int) {
+	ep.ProviderSpecific = slices.DeleteFunc(ep.ProviderSpecific, func(property endpoint.ProviderSpecificProperty) bool {
+		return property.Name == providerSpecificWeight || strings.HasPrefix(property.Name, providerSpecificTargetWeightPrefix)
+	})
+	shared := true
+	for _, target := range ep.Targets {
+		shared = shared && weights[target] == weights[ep.Targets[0]]
+	}
+	if shared {
+		if len(ep.Targets) > 0 && weights[ep.Targets[0]] != 0 {
+			ep.WithProviderSpecific(providerSpecificWeight, strconv.Itoa(weights[ep.Targets[0]]))
+		}
+		return
+	}
+	for _, target := range ep.Targets {
+		ep.WithProviderSpecific(providerSpecificTargetWeightPrefix+target, strconv.Itoa(weights[target]))
+	}
+}
+
+// defaultServicePriority returns the priority of services whose endpoint does not carry one.
+func (p coreDNSProvider) defaultServicePriority() int {
+	if p.defaultPriority == 0 {
+		return priority
+	}
+	return p.defaultPriority
+}
+
+// intProperty returns the non-negative integer value of the given provider specific property
+// of the endpoint, or defaultValue if it is not set or invalid.
+func (p coreDNSProvider) intProperty(ep *endpoint.Endpoint, name string, defaultValue int) int {
+	value, ok := ep.GetProviderSpecificProperty(name)
+	if !ok {
+		return defaultValue
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil || v < 0 {
+		log.Warnf("Ignoring invalid %s %q of endpoint %s", name, value, ep.DNSName)
+		return defaultValue
+	}
+	return v
+}
+
 func shouldSkipLabel(label string) bool {
 	skip := []string{"originalText", "prefix", "resource"}
 	_, ok := findLabelInTargets(skip, label)
@@ -395,10 +664,12 @@ func (p coreDNSProvider) updateTXTRecords(dnsName string, group []*endpoint.Endp
 			if prefix == "" {
 				prefix = fmt.Sprintf("%08x", rand.Int31())
 			}
+			group, _ := ep.GetProviderSpecificProperty(providerSpecificGroup)
 			services = append(services, &Service{
 				Key:         p.etcdKeyFor(prefix + "." + dnsName),
 				TargetStrip: strings.Count(prefix, ".") + 1,
 				TTL:         uint32(ep.RecordTTL),
+				Group:       group,
 			})
 		}
 		services[index].Text = ep.Targets[0]
@@ -411,30 +682,115 @@ func (p coreDNSProvider) updateTXTRecords(dnsName string, group []*endpoint.Endp
 	return services
 }
 
-func (p coreDNSProvider) deleteEndpoints(endpoints []*endpoint.Endpoint) error {
+func (p coreDNSProvider) deleteEndpoints(w *serviceWriter, endpoints []*endpoint.Endpoint) error {
 	for _, ep := range endpoints {
+		if !p.matchDomainFilter(ep.DNSName) {
+			continue
+		}
 		dnsName := ep.DNSName
 		if ep.Labels[randomPrefixLabel] != "" {
 			dnsName = ep.Labels[randomPrefixLabel] + "." + dnsName
 		}
 		key := p.etcdKeyFor(dnsName)
-		log.Infof("Delete key %s", key)
-		if p.dryRun {
-			continue
+		if err := w.delete(key); err != nil {
+			return err
 		}
-		if err := p.client.DeleteService(key); err != nil {
+	}
+	return nil
+}
+
+// serviceWriter saves and deletes services through the client. Clients supporting it are sent
+// the changes in batches, keeping changes of overlapping keys in separate batches so that
+// they are applied in order. In dry-run mode the intended changes are only logged.
+type serviceWriter struct {
+	client  coreDNSClient
+	batch   batchCoreDNSClient
+	pending []serviceOp
+	// pendingOps is the number of etcd transaction operations of the pending changes
+	pendingOps int
+	dryRun     bool
+}
+
+func newServiceWriter(client coreDNSClient, dryRun bool) *serviceWriter {
+	w := &serviceWriter{client: client, dryRun: dryRun}
+	if batch, ok := client.(batchCoreDNSClient); ok && batch.BatchSize() > 1 {
+		w.batch = batch
+	}
+	return w
+}
+
+func (w *serviceWriter) save(service *Service) error {
+	if w.dryRun {
+		value, err := json.Marshal(service)
+		if err != nil {
 			return err
 		}
+		log.Infof("Dry run: would put key %s with value %s", service.Key, value)
+		return nil
+	}
+	log.Infof("Add/set key %s to Host=%s, Text=%s, TTL=%d", service.Key, service.Host, service.Text, service.TTL)
+	if w.batch == nil {
+		return w.client.SaveService(service)
+	}
+	return w.add(serviceOp{service: service, key: service.Key})
+}
+
+func (w *serviceWriter) delete(key string) error {
+	if w.dryRun {
+		log.Infof("Dry run: would delete key %s and the keys below it", key)
+		return nil
+	}
+	log.Infof("Delete key %s and the keys below it", key)
+	if w.batch == nil {
+		return w.client.DeleteService(key)
+	}
+	return w.add(serviceOp{key: key})
+}
+
+func (w *serviceWriter) add(op serviceOp) error {
+	overlaps := slices.ContainsFunc(w.pending, func(pending serviceOp) bool {
+		return strings.HasPrefix(pending.key, op.key) || strings.HasPrefix(op.key, pending.key)
+	})
+	if overlaps || w.pendingOps+op.txnOps() > w.batch.BatchSize() {
+		if err := w.flush(); err != nil {
+			return err
+		}
+	}
+	w.pending = append(w.pending, op)
+	w.pendingOps += op.txnOps()
+	if w.pendingOps >= w.batch.BatchSize() {
+		return w.flush()
 	}
 	return nil
 }
 
+// flush applies the pending changes.
+func (w *serviceWriter) flush() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	log.Debugf("Applying batch of %d etcd changes", len(w.pending))
+	err := w.batch.ApplyServices(w.pending)
+	w.pending = nil
+	w.pendingOps = 0
+	return err
+}
+
 func (p coreDNSProvider) etcdKeyFor(dnsName string) string {
 	domains := strings.Split(dnsName, ".")
 	reverse(domains)
 	return p.coreDNSPrefix + strings.Join(domains, "/")
 }
 
+// serviceRecordType returns the record type stored in the service, falling back to
+// guessing it from the host for services written without one.
+func serviceRecordType(service *Service) string {
+	if service.RecordType != "" {
+		return service.RecordType
+	}
+	return guessRecordType(service.Host)
+}
+
 func guessRecordType(target string) string {
 	if net.ParseIP(target) != nil {
 		return endpoint.RecordTypeA
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
 	"strings"
 	"testing"
@@ -61,7 +62,38 @@ func (c fakeETCDClient) SaveService(service *Service) error {
 }
 
 func (c fakeETCDClient) DeleteService(key string) error {
-	delete(c.services, key)
+	for k := range c.services {
+		if k == key || strings.HasPrefix(k, childKeyPrefix(key)) {
+			delete(c.services, k)
+		}
+	}
+	return nil
+}
+
+// fakeBatchETCDClient records the batches of changes it is sent.
+type fakeBatchETCDClient struct {
+	fakeETCDClient
+	batchSize int
+	batches   *[][]serviceOp
+}
+
+func (c fakeBatchETCDClient) BatchSize() int {
+	return c.batchSize
+}
+
+func (c fakeBatchETCDClient) ApplyServices(ops []serviceOp) error {
+	*c.batches = append(*c.batches, ops)
+	for _, op := range ops {
+		if op.service == nil {
+			if err := c.DeleteService(op.key); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := c.SaveService(op.service); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -81,7 +113,7 @@ func (m *MockEtcdKV) Get(ctx context.Context, key string, _ ...etcdcv3.OpOption)
 }
 
 func (m *MockEtcdKV) Delete(ctx context.Context, key string, opts ...etcdcv3.OpOption) (*etcdcv3.DeleteResponse, error) {
-	args := m.Called(ctx, key, opts[0])
+	args := m.Called(ctx, key, etcdcv3.OpDelete(key, opts...).IsOptsWithPrefix())
 	return args.Get(0).(*etcdcv3.DeleteResponse), args.Error(1)
 }
 
@@ -134,6 +166,53 @@ func TestEtcdHttpsProtocol(t *testing.T) {
 	assert.NotNil(t, cfg)
 }
 
+func TestEtcdForceTLS(t *testing.T) {
+	envs := map[string]string{
+		"ETCD_URLS":            "http://example.com:2379,HTTP://other.example.com:2379,https://secure.example.com:2379",
+		"ETCD_FORCE_TLS":       "true",
+		"ETCD_TLS_INSECURE":    "true",
+		"ETCD_TLS_SERVER_NAME": "etcd.example.com",
+	}
+	testutils.TestHelperEnvSetter(t, envs)
+
+	cfg, err := getETCDConfig()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.TLS)
+	// the etcd client drops the TLS configuration for http:// endpoints
+	assert.Equal(t, []string{
+		"https://example.com:2379",
+		"https://other.example.com:2379",
+		"https://secure.example.com:2379",
+	}, cfg.Endpoints)
+	assert.Equal(t, "etcd.example.com", cfg.TLS.ServerName)
+	assert.True(t, cfg.TLS.InsecureSkipVerify)
+}
+
+func TestEtcdForceTLSDisabled(t *testing.T) {
+	envs := map[string]string{
+		"ETCD_URLS":      "http://example.com:2379",
+		"ETCD_FORCE_TLS": "false",
+		"ETCD_KEY_FILE":  "incorrect-path-to-etcd-tls-key",
+	}
+	testutils.TestHelperEnvSetter(t, envs)
+
+	cfg, err := getETCDConfig()
+	require.NoError(t, err)
+	assert.Nil(t, cfg.TLS)
+}
+
+func TestEtcdForceTLSIncorrectConfigError(t *testing.T) {
+	envs := map[string]string{
+		"ETCD_URLS":      "http://example.com:2379",
+		"ETCD_FORCE_TLS": "1",
+		"ETCD_KEY_FILE":  "incorrect-path-to-etcd-tls-key",
+	}
+	testutils.TestHelperEnvSetter(t, envs)
+
+	_, err := getETCDConfig()
+	assert.Error(t, err)
+}
+
 func TestEtcdHttpsIncorrectConfigError(t *testing.T) {
 	envs := map[string]string{
 		"ETCD_URLS":     "https://example.com:2379",
@@ -215,6 +294,73 @@ func TestCNAMEServiceTranslation(t *testing.T) {
 	}
 }
 
+func TestServiceRecordTypeTranslation(t *testing.T) {
+	client := fakeETCDClient{
+		map[string]Service{
+			"/skydns/com/example/alias":  {Host: "lb.example.net", RecordType: endpoint.RecordTypeA},
+			"/skydns/com/example/ipv6":   {Host: "2001:db8::1", RecordType: endpoint.RecordTypeAAAA},
+			"/skydns/com/example/legacy": {Host: "example.net"},
+			"/skydns/com/example/ip":     {Host: "1.2.3.4"},
+		},
+	}
+	provider := coreDNSProvider{
+		client:        client,
+		coreDNSPrefix: defaultCoreDNSPrefix,
+	}
+	endpoints, err := provider.Records(context.Background())
+	require.NoError(t, err)
+
+	recordTypes := map[string]string{}
+	for _, ep := range endpoints {
+		recordTypes[ep.DNSName] = ep.RecordType
+	}
+	assert.Equal(t, map[string]string{
+		"alias.example.com":  endpoint.RecordTypeA,
+		"ipv6.example.com":   endpoint.RecordTypeAAAA,
+		"legacy.example.com": endpoint.RecordTypeCNAME,
+		"ip.example.com":     endpoint.RecordTypeA,
+	}, recordTypes)
+}
+
+func TestCoreDNSRecordTypeRoundTrip(t *testing.T) {
+	client := fakeETCDClient{
+		map[string]Service{},
+	}
+	coredns := coreDNSProvider{
+		client:        client,
+		coreDNSPrefix: defaultCoreDNSPrefix,
+		domainFilter:  endpoint.NewDomainFilter([]string{}),
+	}
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("alias.example.com", endpoint.RecordTypeA, "lb.example.net"),
+			endpoint.NewEndpoint("cname.example.com", endpoint.RecordTypeCNAME, "example.net"),
+		},
+	}
+	require.NoError(t, coredns.ApplyChanges(context.Background(), changes))
+
+	for key, service := range client.services {
+		switch {
+		case strings.Contains(key, "/com/example/alias/"):
+			assert.Equal(t, endpoint.RecordTypeA, service.RecordType)
+		case strings.Contains(key, "/com/example/cname/"):
+			assert.Equal(t, endpoint.RecordTypeCNAME, service.RecordType)
+		}
+	}
+
+	records, err := coredns.Records(context.Background())
+	require.NoError(t, err)
+	recordTypes := map[string]string{}
+	for _, ep := range records {
+		recordTypes[ep.DNSName] = ep.RecordType
+	}
+	assert.Equal(t, map[string]string{
+		"alias.example.com": endpoint.RecordTypeA,
+		"cname.example.com": endpoint.RecordTypeCNAME,
+	}, recordTypes)
+}
+
 func TestTXTServiceTranslation(t *testing.T) {
 	expectedTarget := "string"
 	expectedDNSName := "example.com"
@@ -414,6 +560,64 @@ func TestCoreDNSApplyChanges(t *testing.T) {
 	validateServices(client.services, expectedServices4, t, 4)
 }
 
+func TestCoreDNSApplyChangesBatched(t *testing.T) {
+	var batches [][]serviceOp
+	client := fakeBatchETCDClient{
+		fakeETCDClient: fakeETCDClient{map[string]Service{
+			"/skydns/local/domain4": {Host: "8.8.8.8"},
+		}},
+		batchSize: 2,
+		batches:   &batches,
+	}
+	coredns := coreDNSProvider{
+		client:        client,
+		coreDNSPrefix: defaultCoreDNSPrefix,
+		domainFilter:  endpoint.NewDomainFilter([]string{}),
+	}
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("domain1.local", endpoint.RecordTypeA, "5.5.5.5"),
+			endpoint.NewEndpoint("domain2.local", endpoint.RecordTypeA, "6.6.6.6"),
+			endpoint.NewEndpoint("domain3.local", endpoint.RecordTypeCNAME, "site.local"),
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("domain4.local", endpoint.RecordTypeA, "8.8.8.8"),
+		},
+	}
+	require.NoError(t, coredns.ApplyChanges(context.Background(), changes))
+
+	// deletions take two transaction operations and are not batched with the last creation
+	require.Len(t, batches, 3)
+	assert.Len(t, batches[0], 2)
+	assert.Len(t, batches[1], 1)
+	assert.Len(t, batches[2], 1)
+	assert.Nil(t, batches[2][0].service)
+	validateServices(client.services, map[string][]*Service{
+		"/skydns/local/domain1": {{Host: "5.5.5.5"}},
+		"/skydns/local/domain2": {{Host: "6.6.6.6"}},
+		"/skydns/local/domain3": {{Host: "site.local"}},
+	}, t, 1)
+
+	// changes of overlapping keys are sent in separate batches
+	batches = nil
+	client.batchSize = 10
+	coredns.client = client
+	changes = &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("domain5.local", endpoint.RecordTypeA, "7.7.7.7"),
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("domain5.local", endpoint.RecordTypeA, "7.7.7.7"),
+		},
+	}
+	require.NoError(t, coredns.ApplyChanges(context.Background(), changes))
+	require.Len(t, batches, 2)
+	assert.NotNil(t, batches[0][0].service)
+	assert.Nil(t, batches[1][0].service)
+	assert.Equal(t, "/skydns/local/domain5", batches[1][0].key)
+}
+
 func TestCoreDNSApplyChanges_DomainDoNotMatch(t *testing.T) {
 	client := fakeETCDClient{
 		map[string]Service{},
@@ -439,6 +643,349 @@ func TestCoreDNSApplyChanges_DomainDoNotMatch(t *testing.T) {
 	testutils.TestHelperLogContains("Skipping record \"domain2.local\" due to domain filter", hook, t)
 }
 
+func TestCoreDNSRecords_DomainExcluded(t *testing.T) {
+	client := fakeETCDClient{
+		map[string]Service{
+			"/skydns/com/example/www":          {Host: "1.2.3.4"},
+			"/skydns/com/example/internal":     {Host: "5.6.7.8"},
+			"/skydns/com/example/internal/api": {Host: "9.9.9.9"},
+		},
+	}
+	coredns := coreDNSProvider{
+		client:        client,
+		coreDNSPrefix: defaultCoreDNSPrefix,
+		domainFilter:  endpoint.NewDomainFilterWithExclusions([]string{"example.com"}, []string{"internal.example.com"}),
+	}
+
+	records, err := coredns.Records(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "www.example.com", records[0].DNSName)
+	assert.Equal(t, endpoint.Targets{"1.2.3.4"}, records[0].Targets)
+}
+
+func TestCoreDNSApplyChanges_DomainExcluded(t *testing.T) {
+	client := fakeETCDClient{
+		map[string]Service{
+			"/skydns/com/example/internal/old": {Host: "5.6.7.8"},
+		},
+	}
+	coredns := coreDNSProvider{
+		client:        client,
+		coreDNSPrefix: defaultCoreDNSPrefix,
+		domainFilter:  endpoint.NewDomainFilterWithExclusions([]string{"example.com"}, []string{"internal.example.com"}),
+	}
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+			endpoint.NewEndpoint("api.internal.example.com", endpoint.RecordTypeA, "9.9.9.9"),
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("old.internal.example.com", endpoint.RecordTypeA, "5.6.7.8"),
+		},
+	}
+	hook := testutils.LogsUnderTestWithLogLevel(log.DebugLevel, t)
+	err := coredns.ApplyChanges(context.Background(), changes)
+	require.NoError(t, err)
+
+	testutils.TestHelperLogContains("Skipping record \"api.internal.example.com\" due to domain filter", hook, t)
+	testutils.TestHelperLogContains("Skipping record \"old.internal.example.com\" due to domain filter", hook, t)
+
+	expectedServices := map[string][]*Service{
+		"/skydns/com/example/www":          {{Host: "1.2.3.4"}},
+		"/skydns/com/example/internal/old": {{Host: "5.6.7.8"}},
+	}
+	validateServices(client.services, expectedServices, t, 1)
+}
+
+func TestCoreDNSRecords_WildcardDomainFilter(t *testing.T) {
+	client := fakeETCDClient{
+		map[string]Service{
+			"/skydns/com/example":          {Host: "1.1.1.1"},
+			"/skydns/com/example/www":      {Host: "1.2.3.4"},
+			"/skydns/com/example/internal": {Host: "5.6.7.8"},
+			"/skydns/com/other/www":        {Host: "9.9.9.9"},
+		},
+	}
+	coredns := coreDNSProvider{
+		client:        client,
+		coreDNSPrefix: defaultCoreDNSPrefix,
+		domainFilter:  endpoint.NewDomainFilterWithExclusions([]string{"*.example.com"}, []string{"*.internal.example.com"}).WithWildcardSubdomains(),
+	}
+
+	records, err := coredns.Records(context.Background())
+	require.NoError(t, err)
+	names := make([]string, 0, len(records))
+	for _, record := range records {
+		names = append(names, record.DNSName)
+	}
+	assert.ElementsMatch(t, []string{"www.example.com", "internal.example.com"}, names)
+}
+
+func TestNewCoreDNSProviderWildcardDomainFilter(t *testing.T) {
+	p, err := NewCoreDNSProvider(endpoint.NewDomainFilter([]string{"*.svc.cluster.local"}), defaultCoreDNSPrefix, 0, false)
+	require.NoError(t, err)
+	domainFilter := p.(coreDNSProvider).domainFilter
+	assert.True(t, domainFilter.Match("web.default.svc.cluster.local"))
+	assert.False(t, domainFilter.Match("svc.cluster.local"))
+}
+
+func TestCoreDNSApplyChanges_WildcardDomainFilter(t *testing.T) {
+	client := fakeETCDClient{
+		map[string]Service{},
+	}
+	coredns := coreDNSProvider{
+		client:        client,
+		coreDNSPrefix: defaultCoreDNSPrefix,
+		domainFilter:  endpoint.NewDomainFilterWithExclusions([]string{"*.example.com"}, []string{"*.internal.example.com"}).WithWildcardSubdomains(),
+	}
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+			endpoint.NewEndpoint("example.com", endpoint.RecordTypeA, "1.1.1.1"),
+			endpoint.NewEndpoint("api.internal.example.com", endpoint.RecordTypeA, "9.9.9.9"),
+		},
+	}
+	hook := testutils.LogsUnderTestWithLogLevel(log.DebugLevel, t)
+	err := coredns.ApplyChanges(context.Background(), changes)
+	require.NoError(t, err)
+
+	testutils.TestHelperLogContains("Skipping record \"example.com\" due to domain filter", hook, t)
+	testutils.TestHelperLogContains("Skipping record \"api.internal.example.com\" due to domain filter", hook, t)
+
+	expectedServices := map[string][]*Service{
+		"/skydns/com/example/www": {{Host: "1.2.3.4"}},
+	}
+	validateServices(client.services, expectedServices, t, 1)
+}
+
+func TestCoreDNSGroupRoundTrip(t *testing.T) {
+	client := fakeETCDClient{
+		map[string]Service{},
+	}
+	coredns := coreDNSProvider{
+		client:        client,
+		coreDNSPrefix: defaultCoreDNSPrefix,
+		domainFilter:  endpoint.NewDomainFilter([]string{}),
+	}
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeA, "1.2.3.4").WithProviderSpecific(providerSpecificGroup, "web"),
+			endpoint.NewEndpoint("txt.example.com", endpoint.RecordTypeTXT, "string").WithProviderSpecific(providerSpecificGroup, "meta"),
+			endpoint.NewEndpoint("other.example.com", endpoint.RecordTypeA, "5.6.7.8"),
+		},
+	}
+	require.NoError(t, coredns.ApplyChanges(context.Background(), changes))
+
+	records, err := coredns.Records(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	groups := map[string]string{}
+	for _, ep := range records {
+		group, ok := ep.GetProviderSpecificProperty(providerSpecificGroup)
+		if ok {
+			groups[ep.DNSName] = group
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"www.example.com": "web",
+		"txt.example.com": "meta",
+	}, groups)
+
+	for key, service := range client.services {
+		switch {
+		case strings.Contains(key, "/com/example/www/"):
+			assert.Equal(t, "web", service.Group)
+		case strings.Contains(key, "/com/example/other/"):
+			assert.Empty(t, service.Group)
+		}
+	}
+}
+
+func TestCoreDNSPriorityAndWeight(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		defaultPriority  int
+		expectedPriority int
+	}{
+		{name: "coredns default", defaultPriority: 0, expectedPriority: 10},
+		{name: "configured default", defaultPriority: 20, expectedPriority: 20},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fakeETCDClient{
+				map[string]Service{},
+			}
+			coredns := coreDNSProvider{
+				client:          client,
+				coreDNSPrefix:   defaultCoreDNSPrefix,
+				defaultPriority: tc.defaultPriority,
+				domainFilter:    endpoint.NewDomainFilter([]string{}),
+			}
+
+			changes := &plan.Changes{
+				Create: []*endpoint.Endpoint{
+					endpoint.NewEndpoint("default.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+					endpoint.NewEndpoint("explicit.example.com", endpoint.RecordTypeA, "5.6.7.8").
+						WithProviderSpecific(providerSpecificPriority, "5").
+						WithProviderSpecific(providerSpecificWeight, "30"),
+					endpoint.NewEndpoint("invalid.example.com", endpoint.RecordTypeA, "9.9.9.9").
+						WithProviderSpecific(providerSpecificPriority, "high"),
+				},
+			}
+			require.NoError(t, coredns.ApplyChanges(context.Background(), changes))
+
+			for key, service := range client.services {
+				switch {
+				case strings.Contains(key, "/com/example/explicit/"):
+					assert.Equal(t, 5, service.Priority)
+					assert.Equal(t, 30, service.Weight)
+				default:
+					assert.Equal(t, tc.expectedPriority, service.Priority, key)
+					assert.Zero(t, service.Weight, key)
+				}
+			}
+
+			records, err := coredns.Records(context.Background())
+			require.NoError(t, err)
+			require.Len(t, records, 3)
+			for _, ep := range records {
+				if ep.DNSName == "explicit.example.com" {
+					assert.Equal(t, endpoint.ProviderSpecific{
+						{Name: providerSpecificPriority, Value: "5"},
+						{Name: providerSpecificWeight, Value: "30"},
+					}, ep.ProviderSpecific)
+				} else {
+					assert.Empty(t, ep.ProviderSpecific, ep.DNSName)
+				}
+			}
+		})
+	}
+}
+
+func TestCoreDNSTargetWeights(t *testing.T) {
+	client := fakeETCDClient{
+		map[string]Service{},
+	}
+	coredns := coreDNSProvider{
+		client:        client,
+		coreDNSPrefix: defaultCoreDNSPrefix,
+		domainFilter:  endpoint.NewDomainFilter([]string{}),
+	}
+
+	desired := endpoint.NewEndpoint("weighted.example.com", endpoint.RecordTypeA, "1.2.3.4", "5.6.7.8").
+		WithProviderSpecific(providerSpecificTargetWeightPrefix+"1.2.3.4", "70").
+		WithProviderSpecific(providerSpecificTargetWeightPrefix+"5.6.7.8", "30")
+	require.NoError(t, coredns.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{desired},
+	}))
+
+	weights := map[string]int{}
+	for _, service := range client.services {
+		weights[service.Host] = service.Weight
+	}
+	assert.Equal(t, map[string]int{"1.2.3.4": 70, "5.6.7.8": 30}, weights)
+
+	records, err := coredns.Records(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, records)
+	assert.ElementsMatch(t, endpoint.ProviderSpecific{
+		{Name: providerSpecificTargetWeightPrefix + "1.2.3.4", Value: "70"},
+		{Name: providerSpecificTargetWeightPrefix + "5.6.7.8", Value: "30"},
+	}, records[0].ProviderSpecific)
+
+	adjusted, err := coredns.AdjustEndpoints([]*endpoint.Endpoint{desired})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, records[0].ProviderSpecific, adjusted[0].ProviderSpecific)
+}
+
+// orderedETCDClient returns its services in the given order.
+type orderedETCDClient struct {
+	fakeETCDClient
+	ordered []*Service
+}
+
+func (c orderedETCDClient) GetServices(_ string) ([]*Service, error) {
+	return c.ordered, nil
+}
+
+func TestCoreDNSRecordsTXTServiceBeforeHost(t *testing.T) {
+	coredns := coreDNSProvider{
+		client: orderedETCDClient{ordered: []*Service{
+			{Key: "/skydns/com/example/www/a1b2c3", Text: "heritage=external-dns", TargetStrip: 1},
+			{Key: "/skydns/com/example/www/d4e5f6", Host: "1.2.3.4", TargetStrip: 1},
+			{Key: "/skydns/com/example/www/g7h8i9", Host: "5.6.7.8", TargetStrip: 1},
+		}},
+		coreDNSPrefix: defaultCoreDNSPrefix,
+		domainFilter:  endpoint.NewDomainFilter([]string{}),
+	}
+
+	records, err := coredns.Records(context.Background())
+	require.NoError(t, err)
+
+	var txt, a *endpoint.Endpoint
+	for _, record := range records {
+		switch record.RecordType {
+		case endpoint.RecordTypeTXT:
+			txt = record
+		case endpoint.RecordTypeA:
+			a = record
+		}
+	}
+	require.NotNil(t, txt)
+	require.NotNil(t, a)
+	assert.Equal(t, endpoint.Targets{"heritage=external-dns"}, txt.Targets)
+	assert.Equal(t, endpoint.Targets{"1.2.3.4", "5.6.7.8"}, a.Targets)
+}
+
+func TestCoreDNSAdjustEndpointsTargetWeights(t *testing.T) {
+	coredns := coreDNSProvider{}
+
+	endpoints, err := coredns.AdjustEndpoints([]*endpoint.Endpoint{
+		// every target has the same weight, so the endpoint weight is used
+		endpoint.NewEndpoint("shared.example.com", endpoint.RecordTypeA, "1.2.3.4", "5.6.7.8").
+			WithProviderSpecific(providerSpecificWeight, "30").
+			WithProviderSpecific(providerSpecificTargetWeightPrefix+"5.6.7.8", "30"),
+		// targets without their own weight fall back to the endpoint weight
+		endpoint.NewEndpoint("mixed.example.com", endpoint.RecordTypeA, "1.2.3.4", "5.6.7.8").
+			WithProviderSpecific(providerSpecificWeight, "30").
+			WithProviderSpecific(providerSpecificTargetWeightPrefix+"5.6.7.8", "70").
+			WithProviderSpecific(providerSpecificTargetWeightPrefix+"9.9.9.9", "10"),
+	})
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+	assert.Equal(t, endpoint.ProviderSpecific{
+		{Name: providerSpecificWeight, Value: "30"},
+	}, endpoints[0].ProviderSpecific)
+	assert.Equal(t, endpoint.ProviderSpecific{
+		{Name: providerSpecificTargetWeightPrefix + "1.2.3.4", Value: "30"},
+		{Name: providerSpecificTargetWeightPrefix + "5.6.7.8", Value: "70"},
+	}, endpoints[1].ProviderSpecific)
+}
+
+func TestCoreDNSAdjustEndpoints(t *testing.T) {
+	coredns := coreDNSProvider{defaultPriority: 20}
+
+	endpoints, err := coredns.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("default.example.com", endpoint.RecordTypeA, "1.2.3.4").
+			WithProviderSpecific(providerSpecificPriority, "20").
+			WithProviderSpecific(providerSpecificWeight, "0"),
+		endpoint.NewEndpoint("explicit.example.com", endpoint.RecordTypeA, "5.6.7.8").
+			WithProviderSpecific(providerSpecificPriority, "5").
+			WithProviderSpecific(providerSpecificWeight, "30"),
+	})
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+	assert.Empty(t, endpoints[0].ProviderSpecific)
+	assert.Equal(t, endpoint.ProviderSpecific{
+		{Name: providerSpecificPriority, Value: "5"},
+		{Name: providerSpecificWeight, Value: "30"},
+	}, endpoints[1].ProviderSpecific)
+}
+
 func applyServiceChanges(provider coreDNSProvider, changes *plan.Changes) error {
 	ctx := context.Background()
 	records, _ := provider.Records(ctx)
@@ -653,8 +1200,13 @@ func TestDeleteService(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockKV := new(MockEtcdKV)
-			mockKV.On("Delete", mock.Anything, mock.Anything, mock.AnythingOfType("clientv3.OpOption")).
-				Return(&etcdcv3.DeleteResponse{}, tt.mockErr)
+			// the key itself, then the keys below it
+			mockKV.On("Delete", mock.Anything, tt.key, false).
+				Return(&etcdcv3.DeleteResponse{}, tt.mockErr).Once()
+			if tt.mockErr == nil {
+				mockKV.On("Delete", mock.Anything, tt.key+"/", true).
+					Return(&etcdcv3.DeleteResponse{}, nil).Once()
+			}
 
 			c := etcdClient{
 				client: &etcdcv3.Client{
@@ -676,6 +1228,76 @@ func TestDeleteService(t *testing.T) {
 	}
 }
 
+func TestCoreDNSKeyDepth(t *testing.T) {
+	client := fakeETCDClient{
+		map[string]Service{
+			// zone example.com, record b.example.com with prefix a
+			"/skydns/com/example/b/a": {Host: "10.0.0.1", TargetStrip: 1},
+			// zone b.example.com, record a.b.example.com whose key is nested below the one above
+			"/skydns/com/example/b/a/x": {Host: "10.0.0.2", TargetStrip: 1},
+			"/skydns/com/example//y":    {Host: "10.0.0.3", TargetStrip: 1},
+			"/skydns/com/example":       {Host: "10.0.0.4", TargetStrip: 2},
+		},
+	}
+	coredns := coreDNSProvider{
+		client:        client,
+		coreDNSPrefix: defaultCoreDNSPrefix,
+		domainFilter:  endpoint.NewDomainFilter([]string{}),
+	}
+
+	endpoints, err := coredns.Records(context.Background())
+	require.NoError(t, err)
+	targets := make(map[string]endpoint.Targets)
+	for _, ep := range endpoints {
+		targets[ep.DNSName] = ep.Targets
+	}
+	assert.Equal(t, map[string]endpoint.Targets{
+		"b.example.com":   {"10.0.0.1"},
+		"a.b.example.com": {"10.0.0.2"},
+	}, targets)
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("c/d.example.com", endpoint.RecordTypeA, "10.0.0.5"),
+			endpoint.NewEndpoint("e..example.com", endpoint.RecordTypeA, "10.0.0.6"),
+		},
+	}
+	require.NoError(t, coredns.ApplyChanges(context.Background(), changes))
+	assert.Len(t, client.services, 4)
+}
+
+func TestCoreDNSApplyChangesDeleteKeepsSiblingKeys(t *testing.T) {
+	for _, batchSize := range []int{0, 10} {
+		t.Run(fmt.Sprintf("batch size %d", batchSize), func(t *testing.T) {
+			services := map[string]Service{
+				"/skydns/com/example/b/a":          {Host: "10.0.0.1"},
+				"/skydns/com/example/b/a/1a2b3c4d": {Host: "10.0.0.2", TargetStrip: 1},
+				"/skydns/com/example/b/ab":         {Host: "10.0.0.3"},
+				"/skydns/com/example/b/ab/x":       {Host: "10.0.0.4"},
+			}
+			var client coreDNSClient = fakeETCDClient{services}
+			if batchSize > 0 {
+				client = fakeBatchETCDClient{fakeETCDClient: fakeETCDClient{services}, batchSize: batchSize, batches: &[][]serviceOp{}}
+			}
+			coredns := coreDNSProvider{
+				client:        client,
+				coreDNSPrefix: defaultCoreDNSPrefix,
+			}
+
+			changes := &plan.Changes{
+				Delete: []*endpoint.Endpoint{
+					endpoint.NewEndpoint("a.b.example.com", endpoint.RecordTypeA, "10.0.0.1", "10.0.0.2"),
+				},
+			}
+			require.NoError(t, coredns.ApplyChanges(context.Background(), changes))
+			assert.Equal(t, map[string]Service{
+				"/skydns/com/example/b/ab":   {Host: "10.0.0.3"},
+				"/skydns/com/example/b/ab/x": {Host: "10.0.0.4"},
+			}, services)
+		})
+	}
+}
+
 func TestSaveService(t *testing.T) {
 	type testCase struct {
 		name       string
@@ -753,13 +1375,23 @@ func TestNewCoreDNSProvider(t *testing.T) {
 			wantErr: true,
 			errMsg:  "etcd URLs must start with either http:// or https://",
 		},
+		{
+			name: "config with ETCD_BATCH_SIZE",
+			envs: map[string]string{"ETCD_BATCH_SIZE": "100"},
+		},
+		{
+			name:    "config with invalid ETCD_BATCH_SIZE",
+			envs:    map[string]string{"ETCD_BATCH_SIZE": "many"},
+			wantErr: true,
+			errMsg:  `invalid ETCD_BATCH_SIZE "many", must be a non-negative integer`,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			testutils.TestHelperEnvSetter(t, tt.envs)
 
-			provider, err := NewCoreDNSProvider(&endpoint.DomainFilter{}, "/prefix/", false)
+			provider, err := NewCoreDNSProvider(&endpoint.DomainFilter{}, "/prefix/", 0, false)
 			if tt.wantErr {
 				require.Error(t, err)
 				assert.EqualError(t, err, tt.errMsg)
@@ -773,46 +1405,61 @@ func TestNewCoreDNSProvider(t *testing.T) {
 
 func TestFindEp(t *testing.T) {
 	tests := []struct {
-		name     string
-		slice    []*endpoint.Endpoint
-		dnsName  string
-		want     *endpoint.Endpoint
-		wantBool bool
+		name       string
+		slice      []*endpoint.Endpoint
+		dnsName    string
+		recordType string
+		want       *endpoint.Endpoint
+		wantBool   bool
 	}{
 		{
 			name: "found",
 			slice: []*endpoint.Endpoint{
-				{DNSName: "foo.example.com"},
-				{DNSName: "bar.example.com"},
+				{DNSName: "foo.example.com", RecordType: endpoint.RecordTypeA},
+				{DNSName: "bar.example.com", RecordType: endpoint.RecordTypeA},
 			},
-			dnsName:  "bar.example.com",
-			want:     &endpoint.Endpoint{DNSName: "bar.example.com"},
-			wantBool: true,
+			dnsName:    "bar.example.com",
+			recordType: endpoint.RecordTypeA,
+			want:       &endpoint.Endpoint{DNSName: "bar.example.com", RecordType: endpoint.RecordTypeA},
+			wantBool:   true,
 		},
 		{
 			name: "not found",
 			slice: []*endpoint.Endpoint{
-				{DNSName: "foo.example.com"},
+				{DNSName: "foo.example.com", RecordType: endpoint.RecordTypeA},
 			},
-			dnsName:  "baz.example.com",
-			want:     nil,
-			wantBool: false,
+			dnsName:    "baz.example.com",
+			recordType: endpoint.RecordTypeA,
+			want:       nil,
+			wantBool:   false,
 		},
 		{
-			name:     "empty slice",
-			slice:    []*endpoint.Endpoint{},
-			dnsName:  "foo.example.com",
-			want:     nil,
-			wantBool: false,
+			name: "other record type",
+			slice: []*endpoint.Endpoint{
+				{DNSName: "foo.example.com", RecordType: endpoint.RecordTypeTXT},
+			},
+			dnsName:    "foo.example.com",
+			recordType: endpoint.RecordTypeA,
+			want:       nil,
+			wantBool:   false,
+		},
+		{
+			name:       "empty slice",
+			slice:      []*endpoint.Endpoint{},
+			dnsName:    "foo.example.com",
+			recordType: endpoint.RecordTypeA,
+			want:       nil,
+			wantBool:   false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, ok := findEp(tt.slice, tt.dnsName)
+			got, ok := findEp(tt.slice, tt.dnsName, tt.recordType)
 			assert.Equal(t, tt.wantBool, ok)
 			if ok {
 				assert.Equal(t, tt.dnsName, got.DNSName)
+				assert.Equal(t, tt.recordType, got.RecordType)
 			} else {
 				assert.Nil(t, got)
 			}
@@ -869,3 +1516,35 @@ func TestCoreDNSProvider_updateTXTRecords_ClearsExtraText(t *testing.T) {
 	assert.Equal(t, "txt-value", services[0].Text)
 	assert.Empty(t, services[1].Text)
 }
+
+func TestCoreDNSApplyChangesDryRun(t *testing.T) {
+	client := fakeETCDClient{
+		map[string]Service{
+			"/skydns/local/example/old": {Host: "5.6.7.8"},
+		},
+	}
+	coredns := coreDNSProvider{
+		client:        client,
+		coreDNSPrefix: defaultCoreDNSPrefix,
+		dryRun:        true,
+	}
+
+	create := endpoint.NewEndpoint("www.example.local", endpoint.RecordTypeA, "1.2.3.4")
+	create.Labels["1.2.3.4"] = "abc"
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{create},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("old.example.local", endpoint.RecordTypeA, "5.6.7.8"),
+		},
+	}
+	hook := testutils.LogsUnderTestWithLogLevel(log.InfoLevel, t)
+	require.NoError(t, coredns.ApplyChanges(context.Background(), changes))
+
+	testutils.TestHelperLogContains(`Dry run: would put key /skydns/local/example/www/abc with value {"host":"1.2.3.4","priority":10,"targetstrip":1,"recordtype":"A"}`, hook, t)
+	testutils.TestHelperLogContains("Dry run: would delete key /skydns/local/example/old and the keys below it", hook, t)
+	testutils.TestHelperLogNotContains("Add/set key", hook, t)
+	testutils.TestHelperLogNotContains("Delete key", hook, t)
+	assert.Equal(t, map[string]Service{
+		"/skydns/local/example/old": {Host: "5.6.7.8"},
+	}, client.services)
+}
@@ -20,11 +20,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/digitalocean/godo"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/oauth2"
+	"golang.org/x/sync/errgroup"
 
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/pkg/apis/externaldns"
@@ -32,10 +34,8 @@ import (
 	"sigs.k8s.io/external-dns/provider"
 )
 
-const (
-	// defaultTTL is the default TTL value
-	defaultTTL = 300
-)
+// minTTL is the lowest TTL accepted by the DigitalOcean API
+const minTTL = 30
 
 // DigitalOceanProvider is an implementation of Provider for Digital Ocean's DNS.
 type DigitalOceanProvider struct {
@@ -45,7 +45,11 @@ type DigitalOceanProvider struct {
 	domainFilter *endpoint.DomainFilter
 	// page size when querying paginated APIs
 	apiPageSize int
-	DryRun      bool
+	// number of domains whose records are fetched at once
+	recordsConcurrency int
+	// defaultTTL is used for records without a TTL
+	defaultTTL endpoint.TTL
+	DryRun     bool
 }
 
 type digitalOceanChangeCreate struct {
@@ -76,7 +80,7 @@ func (c *digitalOceanChanges) Empty() bool {
 }
 
 // NewDigitalOceanProvider initializes a new DigitalOcean DNS based Provider.
-func NewDigitalOceanProvider(ctx context.Context, domainFilter *endpoint.DomainFilter, dryRun bool, apiPageSize int) (*DigitalOceanProvider, error) {
+func NewDigitalOceanProvider(ctx context.Context, domainFilter *endpoint.DomainFilter, dryRun bool, apiPageSize int, recordsConcurrency int, defaultTTL endpoint.TTL) (*DigitalOceanProvider, error) {
 	token, ok := os.LookupEnv("DO_TOKEN")
 	if !ok {
 		return nil, fmt.Errorf("no token found")
@@ -90,10 +94,12 @@ func NewDigitalOceanProvider(ctx context.Context, domainFilter *endpoint.DomainF
 	}
 
 	p := &DigitalOceanProvider{
-		Client:       client.Domains,
-		domainFilter: domainFilter,
-		apiPageSize:  apiPageSize,
-		DryRun:       dryRun,
+		Client:             client.Domains,
+		domainFilter:       domainFilter,
+		apiPageSize:        apiPageSize,
+		recordsConcurrency: recordsConcurrency,
+		defaultTTL:         defaultTTL,
+		DryRun:             dryRun,
 	}
 	return p, nil
 }
@@ -116,38 +122,6 @@ func (p *DigitalOceanProvider) Zones(ctx context.Context) ([]godo.Domain, error)
 	return result, nil
 }
 
-// Merge Endpoints with the same Name and Type into a single endpoint with multiple Targets.
-func mergeEndpointsByNameType(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
-	endpointsByNameType := map[string][]*endpoint.Endpoint{}
-
-	for _, e := range endpoints {
-		key := fmt.Sprintf("%s-%s", e.DNSName, e.RecordType)
-		endpointsByNameType[key] = append(endpointsByNameType[key], e)
-	}
-
-	// If no merge occurred, just return the existing endpoints.
-	if len(endpointsByNameType) == len(endpoints) {
-		return endpoints
-	}
-
-	// Otherwise, construct a new list of endpoints with the endpoints merged.
-	var result []*endpoint.Endpoint
-	for _, endpoints := range endpointsByNameType {
-		dnsName := endpoints[0].DNSName
-		recordType := endpoints[0].RecordType
-
-		targets := make([]string, len(endpoints))
-		for i, e := range endpoints {
-			targets[i] = e.Targets[0]
-		}
-
-		e := endpoint.NewEndpoint(dnsName, recordType, targets...)
-		result = append(result, e)
-	}
-
-	return result
-}
-
 // Records returns the list of records in a given zone.
 func (p *DigitalOceanProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	zones, err := p.Zones(ctx)
@@ -155,18 +129,30 @@ func (p *DigitalOceanProvider) Records(ctx context.Context) ([]*endpoint.Endpoin
 		return nil, err
 	}
 
-	endpoints := []*endpoint.Endpoint{}
-	for _, zone := range zones {
-		records, err := p.fetchRecords(ctx, zone.Name)
-
-		if err != nil {
-			return nil, err
-		}
+	// Records of the zones are fetched concurrently, each zone into its own slot.
+	zoneRecords := make([][]godo.DomainRecord, len(zones))
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(max(p.recordsConcurrency, 1))
+	for i, zone := range zones {
+		eg.Go(func() error {
+			records, err := p.fetchRecords(egCtx, zone.Name)
+			if err != nil {
+				return err
+			}
+			zoneRecords[i] = records
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
 
-		for _, r := range records {
+	endpoints := []*endpoint.Endpoint{}
+	for i, zone := range zones {
+		for _, r := range zoneRecords[i] {
 			if p.SupportedRecordType(r.Type) {
 				name := r.Name + "." + zone.Name
-				data := r.Data
+				data := recordData(r)
 
 				// root name is identified by @ and should be
 				// translated to zone name for the endpoint entry.
@@ -175,7 +161,7 @@ func (p *DigitalOceanProvider) Records(ctx context.Context) ([]*endpoint.Endpoin
 				}
 
 				if r.Type == endpoint.RecordTypeMX {
-					data = fmt.Sprintf("%d %s", r.Priority, r.Data)
+					data = endpoint.FormatMXTarget(uint16(r.Priority), r.Data)
 				}
 
 				ep := endpoint.NewEndpointWithTTL(name, r.Type, endpoint.TTL(r.TTL), data)
@@ -187,7 +173,7 @@ func (p *DigitalOceanProvider) Records(ctx context.Context) ([]*endpoint.Endpoin
 
 	// Merge endpoints with the same name and type (e.g., multiple A records for a single
 	// DNS name) into one endpoint with multiple targets.
-	endpoints = mergeEndpointsByNameType(endpoints)
+	endpoints = provider.MergeEndpointsByNameType(endpoints)
 
 	// Log the endpoints that were found.
 	log.WithFields(log.Fields{
@@ -277,22 +263,32 @@ func (p *DigitalOceanProvider) getRecordsByDomain(ctx context.Context) (map[stri
 
 // Make a DomainRecordEditRequest that conforms to DigitalOcean API requirements:
 // - Records at root of the zone have `@` as the name
-// - CNAME records must end in a `.`
+// - CNAME and NS records must end in a `.`
+// - TXT records containing whitespace, semicolons or quotes are quoted
+// - TTLs are at least 30 seconds
 func makeDomainEditRequest(domain, name, recordType, data string, ttl int) *godo.DomainRecordEditRequest {
-	// Trim the domain off the name if present.
-	adjustedName := strings.TrimSuffix(name, "."+domain)
+	adjustedName := domainRecordName(domain, name)
 
-	// Record at the root should be defined as @ instead of the full domain name.
-	if adjustedName == domain {
-		adjustedName = "@"
+	if ttl < minTTL {
+		log.WithFields(log.Fields{
+			"domain":     domain,
+			"dnsName":    name,
+			"recordType": recordType,
+			"ttl":        ttl,
+		}).Warnf("TTL is below the DigitalOcean minimum, using %d", minTTL)
+		ttl = minTTL
 	}
 
-	// For some reason the DO API requires the '.' at the end of "data" in case of CNAME request.
+	// For some reason the DO API requires the '.' at the end of "data" in case of CNAME and NS requests.
 	// Example: {"type":"CNAME","name":"hello","data":"www.example.com."}
-	if (recordType == endpoint.RecordTypeCNAME || recordType == endpoint.RecordTypeMX) && !strings.HasSuffix(data, ".") {
+	if (recordType == endpoint.RecordTypeCNAME || recordType == endpoint.RecordTypeNS) && !strings.HasSuffix(data, ".") {
 		data += "."
 	}
 
+	if recordType == endpoint.RecordTypeTXT {
+		data = encodeTXTData(data)
+	}
+
 	request := &godo.DomainRecordEditRequest{
 		Name: adjustedName,
 		Type: recordType,
@@ -301,7 +297,7 @@ func makeDomainEditRequest(domain, name, recordType, data string, ttl int) *godo
 	}
 
 	if recordType == endpoint.RecordTypeMX {
-		mxRecord, err := endpoint.NewMXRecord(data)
+		priority, host, err := endpoint.ParseMXTarget(data)
 		if err != nil {
 			log.WithFields(log.Fields{
 				"domain":     domain,
@@ -311,8 +307,8 @@ func makeDomainEditRequest(domain, name, recordType, data string, ttl int) *godo
 			}).Warn("Unable to parse MX target")
 			return request
 		}
-		request.Priority = int(*mxRecord.GetPriority())
-		request.Data = provider.EnsureTrailingDot(*mxRecord.GetHost())
+		request.Priority = int(priority)
+		request.Data = host
 	}
 	return request
 }
@@ -391,11 +387,69 @@ func (p *DigitalOceanProvider) submitChanges(ctx context.Context, changes *digit
 	return nil
 }
 
-func getTTLFromEndpoint(ep *endpoint.Endpoint) int {
-	if ep.RecordTTL.IsConfigured() {
-		return int(ep.RecordTTL)
+// encodeTXTData quotes TXT data containing whitespace, semicolons or quotes, escaping
+// embedded quotes and backslashes, so that DigitalOcean stores it as a single string.
+// Data that is already quoted is passed through unmodified.
+func encodeTXTData(data string) string {
+	if isQuotedTXTData(data) || !strings.ContainsAny(data, " \t;\"") {
+		return data
 	}
-	return defaultTTL
+	return `"` + txtEscaper.Replace(data) + `"`
+}
+
+// decodeTXTData reverses encodeTXTData. Data split into several quoted strings,
+// as DigitalOcean does for values longer than 255 characters, is concatenated.
+// Data that is not quoted or cannot be parsed is returned unmodified.
+func decodeTXTData(data string) string {
+	if !isQuotedTXTData(data) {
+		return data
+	}
+
+	var sb strings.Builder
+	rest := data
+	for rest != "" {
+		if rest[0] != '"' {
+			return data
+		}
+		end := -1
+		for i := 1; i < len(rest); i++ {
+			if rest[i] == '\\' {
+				i++
+				continue
+			}
+			if rest[i] == '"' {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return data
+		}
+		sb.WriteString(txtUnescaper.Replace(rest[1:end]))
+		rest = strings.TrimLeft(rest[end+1:], " \t")
+	}
+	return sb.String()
+}
+
+func isQuotedTXTData(data string) bool {
+	return len(data) >= 2 && strings.HasPrefix(data, `"`) && strings.HasSuffix(data, `"`)
+}
+
+var (
+	txtEscaper   = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	txtUnescaper = strings.NewReplacer(`\\`, `\`, `\"`, `"`)
+)
+
+// recordData returns the data of a record as it is represented in an endpoint target.
+func recordData(r godo.DomainRecord) string {
+	if r.Type == endpoint.RecordTypeTXT {
+		return decodeTXTData(r.Data)
+	}
+	return r.Data
+}
+
+func getTTLFromEndpoint(ep *endpoint.Endpoint, defaultTTL endpoint.TTL) int {
+	return int(provider.TTLOrDefault(ep, defaultTTL))
 }
 
 func endpointsByZone(zoneNameIDMapper provider.ZoneIDName, endpoints []*endpoint.Endpoint) map[string][]*endpoint.Endpoint {
@@ -407,19 +461,29 @@ func endpointsByZone(zoneNameIDMapper provider.ZoneIDName, endpoints []*endpoint
 			log.Debugf("Skipping record %s because no hosted zone matching record DNS Name was detected", ep.DNSName)
 			continue
 		}
+		// The NS records at the root of the domain are managed by DigitalOcean, only delegations may be changed
+		if ep.RecordType == endpoint.RecordTypeNS && domainRecordName(zoneID, ep.DNSName) == "@" {
+			log.Infof("Skipping NS record %s because it is the root of the domain", ep.DNSName)
+			continue
+		}
 		endpointsByZone[zoneID] = append(endpointsByZone[zoneID], ep)
 	}
 
 	return endpointsByZone
 }
 
-func getMatchingDomainRecords(records []godo.DomainRecord, domain string, ep *endpoint.Endpoint) []godo.DomainRecord {
-	var name string
-	if ep.DNSName != domain {
-		name = strings.TrimSuffix(ep.DNSName, "."+domain)
-	} else {
-		name = "@"
+// domainRecordName returns the name of a record relative to its domain, as used by the
+// DigitalOcean API. Records at the root of the domain are named `@`.
+func domainRecordName(domain, dnsName string) string {
+	dnsName = strings.TrimSuffix(dnsName, ".")
+	if dnsName == domain {
+		return "@"
 	}
+	return strings.TrimSuffix(dnsName, "."+domain)
+}
+
+func getMatchingDomainRecords(records []godo.DomainRecord, domain string, ep *endpoint.Endpoint) []godo.DomainRecord {
+	name := domainRecordName(domain, ep.DNSName)
 
 	var result []godo.DomainRecord
 	for _, r := range records {
@@ -433,6 +497,7 @@ func getMatchingDomainRecords(records []godo.DomainRecord, domain string, ep *en
 func processCreateActions(
 	recordsByDomain map[string][]godo.DomainRecord,
 	createsByDomain map[string][]*endpoint.Endpoint,
+	defaultTTL endpoint.TTL,
 	changes *digitalOceanChanges,
 ) error {
 	// Process endpoints that need to be created.
@@ -457,7 +522,7 @@ func processCreateActions(
 				}).Warn("Preexisting records exist which should not exist for creation actions.")
 			}
 
-			ttl := getTTLFromEndpoint(ep)
+			ttl := getTTLFromEndpoint(ep, defaultTTL)
 
 			for _, target := range ep.Targets {
 				changes.Creates = append(changes.Creates, &digitalOceanChangeCreate{
@@ -474,6 +539,7 @@ func processCreateActions(
 func processUpdateActions(
 	recordsByDomain map[string][]godo.DomainRecord,
 	updatesByDomain map[string][]*endpoint.Endpoint,
+	defaultTTL endpoint.TTL,
 	changes *digitalOceanChanges,
 ) error {
 	// Generate creates and updates based on existing
@@ -509,10 +575,10 @@ func processUpdateActions(
 
 			matchingRecordsByTarget := map[string]godo.DomainRecord{}
 			for _, r := range matchingRecords {
-				matchingRecordsByTarget[r.Data] = r
+				matchingRecordsByTarget[recordData(r)] = r
 			}
 
-			ttl := getTTLFromEndpoint(ep)
+			ttl := getTTLFromEndpoint(ep, defaultTTL)
 
 			// Generate create and delete actions based on existence of a record for each target.
 			for _, target := range ep.Targets {
@@ -598,7 +664,7 @@ func processDeleteActions(
 				doDelete := false
 				for _, t := range ep.Targets {
 					v1 := t
-					v2 := record.Data
+					v2 := recordData(record)
 					if ep.RecordType == endpoint.RecordTypeCNAME {
 						v1 = strings.TrimSuffix(t, ".")
 						v2 = strings.TrimSuffix(t, ".")
@@ -621,14 +687,27 @@ func processDeleteActions(
 	return nil
 }
 
+// SupportedRecordTypes returns the record types managed by the provider
+func (p *DigitalOceanProvider) SupportedRecordTypes() []string {
+	return append(p.BaseProvider.SupportedRecordTypes(), endpoint.RecordTypeMX)
+}
+
 // SupportedRecordType returns true if the record type is supported by the provider
 func (p *DigitalOceanProvider) SupportedRecordType(recordType string) bool {
-	switch recordType {
-	case "MX":
-		return true
-	default:
-		return provider.SupportedRecordType(recordType)
+	return slices.Contains(p.SupportedRecordTypes(), recordType)
+}
+
+// AdjustEndpoints drops endpoints whose targets are inconsistent with their record type,
+// as they would otherwise be rejected by the DigitalOcean API, and raises TTLs below the
+// DigitalOcean minimum so that they match the records that are created.
+func (p *DigitalOceanProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
+	valid, _ := provider.ValidateEndpoints(endpoints)
+	for _, ep := range valid {
+		if ep.RecordTTL.IsConfigured() && ep.RecordTTL < minTTL {
+			ep.RecordTTL = minTTL
+		}
 	}
+	return valid, nil
 }
 
 // ApplyChanges applies the given set of generic changes to the provider.
@@ -645,11 +724,11 @@ func (p *DigitalOceanProvider) ApplyChanges(ctx context.Context, planChanges *pl
 
 	var changes digitalOceanChanges
 
-	if err := processCreateActions(recordsByDomain, createsByDomain, &changes); err != nil {
+	if err := processCreateActions(recordsByDomain, createsByDomain, p.defaultTTL, &changes); err != nil {
 		return err
 	}
 
-	if err := processUpdateActions(recordsByDomain, updatesByDomain, &changes); err != nil {
+	if err := processUpdateActions(recordsByDomain, updatesByDomain, p.defaultTTL, &changes); err != nil {
 		return err
 	}
 
@@ -21,8 +21,11 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"slices"
 	"sort"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/digitalocean/godo"
 	"github.com/google/go-cmp/cmp"
@@ -31,6 +34,7 @@ import (
 
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
 )
 
 type mockDigitalOceanClient struct{}
@@ -97,20 +101,20 @@ func (m *mockDigitalOceanClient) Records(ctx context.Context, domain string, opt
 	case "foo.com":
 		if opt == nil || opt.Page == 0 {
 			return []godo.DomainRecord{
-					{ID: 1, Name: "foo.ext-dns-test", Type: "CNAME"},
-					{ID: 2, Name: "bar.ext-dns-test", Type: "CNAME"},
-					{ID: 3, Name: "@", Type: endpoint.RecordTypeCNAME},
-					{ID: 4, Name: "@", Type: endpoint.RecordTypeMX, Priority: 10, Data: "mx1.foo.com."},
-					{ID: 5, Name: "@", Type: endpoint.RecordTypeMX, Priority: 10, Data: "mx2.foo.com."},
-					{ID: 6, Name: "@", Type: endpoint.RecordTypeTXT, Data: "SOME-TXT-TEXT"},
-				}, &godo.Response{
-					Links: &godo.Links{
-						Pages: &godo.Pages{
-							Next: "http://example.com/v2/domains/?page=2",
-							Last: "1234",
-						},
+				{ID: 1, Name: "foo.ext-dns-test", Type: "CNAME"},
+				{ID: 2, Name: "bar.ext-dns-test", Type: "CNAME"},
+				{ID: 3, Name: "@", Type: endpoint.RecordTypeCNAME},
+				{ID: 4, Name: "@", Type: endpoint.RecordTypeMX, Priority: 10, Data: "mx1.foo.com."},
+				{ID: 5, Name: "@", Type: endpoint.RecordTypeMX, Priority: 10, Data: "mx2.foo.com."},
+				{ID: 6, Name: "@", Type: endpoint.RecordTypeTXT, Data: "SOME-TXT-TEXT"},
+			}, &godo.Response{
+				Links: &godo.Links{
+					Pages: &godo.Pages{
+						Next: "http://example.com/v2/domains/?page=2",
+						Last: "1234",
 					},
-				}, nil
+				},
+			}, nil
 		}
 		return []godo.DomainRecord{{ID: 3, Name: "baz.ext-dns-test", Type: "A"}}, nil, nil
 	case "example.com":
@@ -306,35 +310,58 @@ func TestDigitalOceanZones(t *testing.T) {
 	})
 }
 
+func TestDigitalOceanZonesExclusions(t *testing.T) {
+	provider := &DigitalOceanProvider{
+		Client:       &mockDigitalOceanClient{},
+		domainFilter: endpoint.NewDomainFilterWithExclusions([]string{"com"}, []string{"example.com"}),
+	}
+
+	zones, err := provider.Zones(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validateDigitalOceanZones(t, zones, []godo.Domain{
+		{Name: "foo.com"}, {Name: "bar.com"},
+	})
+}
+
+func TestDigitalOceanSupportedRecordTypes(t *testing.T) {
+	p := &DigitalOceanProvider{}
+	assert.ElementsMatch(t, []string{"A", "AAAA", "CNAME", "SRV", "TXT", "NS", "MX"}, p.SupportedRecordTypes())
+	assert.True(t, p.SupportedRecordType(endpoint.RecordTypeMX))
+	assert.False(t, p.SupportedRecordType(endpoint.RecordTypePTR))
+}
+
 func TestDigitalOceanMakeDomainEditRequest(t *testing.T) {
 	// Ensure that records at the root of the zone get `@` as the name.
 	r1 := makeDomainEditRequest("example.com", "example.com", endpoint.RecordTypeA,
-		"1.2.3.4", defaultTTL)
+		"1.2.3.4", int(provider.DefaultTTL))
 	assert.Equal(t, &godo.DomainRecordEditRequest{
 		Type: endpoint.RecordTypeA,
 		Name: "@",
 		Data: "1.2.3.4",
-		TTL:  defaultTTL,
+		TTL:  int(provider.DefaultTTL),
 	}, r1)
 
 	// Ensure the CNAME records have a `.` appended.
 	r2 := makeDomainEditRequest("example.com", "foo.example.com", endpoint.RecordTypeCNAME,
-		"bar.example.com", defaultTTL)
+		"bar.example.com", int(provider.DefaultTTL))
 	assert.Equal(t, &godo.DomainRecordEditRequest{
 		Type: endpoint.RecordTypeCNAME,
 		Name: "foo",
 		Data: "bar.example.com.",
-		TTL:  defaultTTL,
+		TTL:  int(provider.DefaultTTL),
 	}, r2)
 
 	// Ensure that CNAME records do not have an extra `.` appended if they already have a `.`
 	r3 := makeDomainEditRequest("example.com", "foo.example.com", endpoint.RecordTypeCNAME,
-		"bar.example.com.", defaultTTL)
+		"bar.example.com.", int(provider.DefaultTTL))
 	assert.Equal(t, &godo.DomainRecordEditRequest{
 		Type: endpoint.RecordTypeCNAME,
 		Name: "foo",
 		Data: "bar.example.com.",
-		TTL:  defaultTTL,
+		TTL:  int(provider.DefaultTTL),
 	}, r3)
 
 	// Ensure that custom TTLs can be set
@@ -350,25 +377,148 @@ func TestDigitalOceanMakeDomainEditRequest(t *testing.T) {
 
 	// Ensure that MX records have `.` appended.
 	r5 := makeDomainEditRequest("example.com", "foo.example.com", endpoint.RecordTypeMX,
-		"10 mx.example.com", defaultTTL)
+		"10 mx.example.com", int(provider.DefaultTTL))
 	assert.Equal(t, &godo.DomainRecordEditRequest{
 		Type:     endpoint.RecordTypeMX,
 		Name:     "foo",
 		Data:     "mx.example.com.",
 		Priority: 10,
-		TTL:      defaultTTL,
+		TTL:      int(provider.DefaultTTL),
 	}, r5)
 
 	// Ensure that MX records do not have an extra `.` appended if they already have a `.`
 	r6 := makeDomainEditRequest("example.com", "foo.example.com", endpoint.RecordTypeMX,
-		"10 mx.example.com.", defaultTTL)
+		"10 mx.example.com.", int(provider.DefaultTTL))
 	assert.Equal(t, &godo.DomainRecordEditRequest{
 		Type:     endpoint.RecordTypeMX,
 		Name:     "foo",
 		Data:     "mx.example.com.",
 		Priority: 10,
-		TTL:      defaultTTL,
+		TTL:      int(provider.DefaultTTL),
 	}, r6)
+
+	// Ensure that multi-digit MX preferences are parsed
+	r7 := makeDomainEditRequest("example.com", "foo.example.com", endpoint.RecordTypeMX,
+		"1000 mx.example.com", int(provider.DefaultTTL))
+	assert.Equal(t, &godo.DomainRecordEditRequest{
+		Type:     endpoint.RecordTypeMX,
+		Name:     "foo",
+		Data:     "mx.example.com.",
+		Priority: 1000,
+		TTL:      int(provider.DefaultTTL),
+	}, r7)
+
+	// Ensure that TTLs below the DigitalOcean minimum are raised to it
+	r8 := makeDomainEditRequest("example.com", "foo.example.com", endpoint.RecordTypeA,
+		"1.2.3.4", 5)
+	assert.Equal(t, &godo.DomainRecordEditRequest{
+		Type: endpoint.RecordTypeA,
+		Name: "foo",
+		Data: "1.2.3.4",
+		TTL:  minTTL,
+	}, r8)
+
+	// Ensure the NS records have a `.` appended.
+	r9 := makeDomainEditRequest("example.com", "sub.example.com", endpoint.RecordTypeNS,
+		"ns1.other.com", int(provider.DefaultTTL))
+	assert.Equal(t, &godo.DomainRecordEditRequest{
+		Type: endpoint.RecordTypeNS,
+		Name: "sub",
+		Data: "ns1.other.com.",
+		TTL:  int(provider.DefaultTTL),
+	}, r9)
+}
+
+func TestDigitalOceanTXTRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		value    string
+		encoded  string
+		readBack string
+	}{
+		{
+			name:    "ownership record",
+			value:   "heritage=external-dns,external-dns/owner=default,external-dns/resource=service/default/foo",
+			encoded: "heritage=external-dns,external-dns/owner=default,external-dns/resource=service/default/foo",
+		},
+		{
+			name:    "dkim record",
+			value:   "v=DKIM1; k=rsa; p=MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQC",
+			encoded: `"v=DKIM1; k=rsa; p=MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQC"`,
+		},
+		{
+			name:    "embedded quotes and backslashes",
+			value:   `say "hello"; c:\path`,
+			encoded: `"say \"hello\"; c:\\path"`,
+		},
+		{
+			name:     "already quoted",
+			value:    `"v=spf1 include:example.com ~all"`,
+			encoded:  `"v=spf1 include:example.com ~all"`,
+			readBack: "v=spf1 include:example.com ~all",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := makeDomainEditRequest("example.com", "foo.example.com", endpoint.RecordTypeTXT, tc.value, int(provider.DefaultTTL))
+			assert.Equal(t, tc.encoded, req.Data)
+
+			readBack := tc.readBack
+			if readBack == "" {
+				readBack = tc.value
+			}
+			record := godo.DomainRecord{Name: req.Name, Type: req.Type, Data: req.Data}
+			assert.Equal(t, readBack, recordData(record))
+		})
+	}
+
+	// DigitalOcean splits long values into several quoted strings.
+	assert.Equal(t, "v=DKIM1; k=rsa; p=abc", decodeTXTData(`"v=DKIM1; k=rsa; " "p=abc"`))
+	// malformed values are returned unmodified
+	assert.Equal(t, `"foo" bar"`, decodeTXTData(`"foo" bar"`))
+}
+
+func TestDigitalOceanAdjustEndpoints(t *testing.T) {
+	p := &DigitalOceanProvider{}
+	valid := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("mx.example.com", endpoint.RecordTypeMX, "10 mail.example.com"),
+	}
+	invalid := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("b.example.com", endpoint.RecordTypeA, "2001:db8::1"),
+		endpoint.NewEndpoint("mx2.example.com", endpoint.RecordTypeMX, "mail.example.com"),
+	}
+
+	adjusted, err := p.AdjustEndpoints(append(slices.Clone(valid), invalid...))
+	require.NoError(t, err)
+	assert.Equal(t, valid, adjusted)
+}
+
+func TestDigitalOceanAdjustEndpointsMinTTL(t *testing.T) {
+	p := &DigitalOceanProvider{}
+	adjusted, err := p.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("low.example.com", endpoint.RecordTypeA, 5, "1.2.3.4"),
+		endpoint.NewEndpointWithTTL("high.example.com", endpoint.RecordTypeA, 600, "1.2.3.4"),
+		endpoint.NewEndpoint("unset.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+	})
+	require.NoError(t, err)
+	require.Len(t, adjusted, 3)
+	assert.Equal(t, endpoint.TTL(minTTL), adjusted[0].RecordTTL)
+	assert.Equal(t, endpoint.TTL(600), adjusted[1].RecordTTL)
+	assert.False(t, adjusted[2].RecordTTL.IsConfigured())
+}
+
+func TestDigitalOceanProcessCreateActionsDefaultTTL(t *testing.T) {
+	var changes digitalOceanChanges
+	err := processCreateActions(map[string][]godo.DomainRecord{"example.com": nil}, map[string][]*endpoint.Endpoint{
+		"example.com": {
+			endpoint.NewEndpoint("unset.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+			endpoint.NewEndpointWithTTL("set.example.com", endpoint.RecordTypeA, 600, "1.2.3.4"),
+		},
+	}, 120, &changes)
+	require.NoError(t, err)
+	require.Len(t, changes.Creates, 2)
+	assert.Equal(t, 120, changes.Creates[0].Options.TTL)
+	assert.Equal(t, 600, changes.Creates[1].Options.TTL)
 }
 
 func TestDigitalOceanApplyChanges(t *testing.T) {
@@ -410,7 +560,7 @@ func TestDigitalOceanProcessCreateActions(t *testing.T) {
 	}
 
 	var changes digitalOceanChanges
-	err := processCreateActions(recordsByDomain, createsByDomain, &changes)
+	err := processCreateActions(recordsByDomain, createsByDomain, 0, &changes)
 	require.NoError(t, err)
 
 	assert.Len(t, changes.Creates, 4)
@@ -424,7 +574,7 @@ func TestDigitalOceanProcessCreateActions(t *testing.T) {
 				Name: "foo",
 				Type: endpoint.RecordTypeA,
 				Data: "1.2.3.4",
-				TTL:  defaultTTL,
+				TTL:  int(provider.DefaultTTL),
 			},
 		},
 		{
@@ -433,7 +583,7 @@ func TestDigitalOceanProcessCreateActions(t *testing.T) {
 				Name: "@",
 				Type: endpoint.RecordTypeCNAME,
 				Data: "foo.example.com.",
-				TTL:  defaultTTL,
+				TTL:  int(provider.DefaultTTL),
 			},
 		},
 		{
@@ -443,7 +593,7 @@ func TestDigitalOceanProcessCreateActions(t *testing.T) {
 				Type:     endpoint.RecordTypeMX,
 				Priority: 10,
 				Data:     "mx.example.com.",
-				TTL:      defaultTTL,
+				TTL:      int(provider.DefaultTTL),
 			},
 		},
 		{
@@ -452,7 +602,46 @@ func TestDigitalOceanProcessCreateActions(t *testing.T) {
 				Name: "@",
 				Type: endpoint.RecordTypeTXT,
 				Data: "SOME-TXT-TEXT",
-				TTL:  defaultTTL,
+				TTL:  int(provider.DefaultTTL),
+			},
+		},
+	}
+
+	if !elementsMatch(t, expectedCreates, changes.Creates) {
+		assert.Failf(t, "diff: %s", cmp.Diff(expectedCreates, changes.Creates))
+	}
+}
+
+func TestDigitalOceanNSDelegation(t *testing.T) {
+	zoneNameIDMapper := provider.ZoneIDName{}
+	zoneNameIDMapper.Add("example.com", "example.com")
+
+	createsByDomain := endpointsByZone(zoneNameIDMapper, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("sub.example.com", endpoint.RecordTypeNS, "ns1.other.com", "ns2.other.com."),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeNS, "ns1.other.com"),
+	})
+
+	var changes digitalOceanChanges
+	err := processCreateActions(map[string][]godo.DomainRecord{"example.com": nil}, createsByDomain, 0, &changes)
+	require.NoError(t, err)
+
+	expectedCreates := []*digitalOceanChangeCreate{
+		{
+			Domain: "example.com",
+			Options: &godo.DomainRecordEditRequest{
+				Name: "sub",
+				Type: endpoint.RecordTypeNS,
+				Data: "ns1.other.com.",
+				TTL:  int(provider.DefaultTTL),
+			},
+		},
+		{
+			Domain: "example.com",
+			Options: &godo.DomainRecordEditRequest{
+				Name: "sub",
+				Type: endpoint.RecordTypeNS,
+				Data: "ns2.other.com.",
+				TTL:  int(provider.DefaultTTL),
 			},
 		},
 	}
@@ -470,21 +659,21 @@ func TestDigitalOceanProcessUpdateActions(t *testing.T) {
 				Name: "foo",
 				Type: endpoint.RecordTypeA,
 				Data: "1.2.3.4",
-				TTL:  defaultTTL,
+				TTL:  int(provider.DefaultTTL),
 			},
 			{
 				ID:   2,
 				Name: "foo",
 				Type: endpoint.RecordTypeA,
 				Data: "5.6.7.8",
-				TTL:  defaultTTL,
+				TTL:  int(provider.DefaultTTL),
 			},
 			{
 				ID:   3,
 				Name: "@",
 				Type: endpoint.RecordTypeCNAME,
 				Data: "foo.example.com.",
-				TTL:  defaultTTL,
+				TTL:  int(provider.DefaultTTL),
 			},
 			{
 				ID:       4,
@@ -492,7 +681,7 @@ func TestDigitalOceanProcessUpdateActions(t *testing.T) {
 				Type:     endpoint.RecordTypeMX,
 				Data:     "mx1.example.com.",
 				Priority: 10,
-				TTL:      defaultTTL,
+				TTL:      int(provider.DefaultTTL),
 			},
 			{
 				ID:       5,
@@ -500,14 +689,14 @@ func TestDigitalOceanProcessUpdateActions(t *testing.T) {
 				Type:     endpoint.RecordTypeMX,
 				Data:     "mx2.example.com.",
 				Priority: 10,
-				TTL:      defaultTTL,
+				TTL:      int(provider.DefaultTTL),
 			},
 			{
 				ID:   6,
 				Name: "@",
 				Type: endpoint.RecordTypeTXT,
 				Data: "SOME_TXTX_TEXT",
-				TTL:  defaultTTL,
+				TTL:  int(provider.DefaultTTL),
 			},
 		},
 	}
@@ -522,7 +711,7 @@ func TestDigitalOceanProcessUpdateActions(t *testing.T) {
 	}
 
 	var changes digitalOceanChanges
-	err := processUpdateActions(recordsByDomain, updatesByDomain, &changes)
+	err := processUpdateActions(recordsByDomain, updatesByDomain, 0, &changes)
 	require.NoError(t, err)
 
 	assert.Len(t, changes.Creates, 4)
@@ -536,7 +725,7 @@ func TestDigitalOceanProcessUpdateActions(t *testing.T) {
 				Name: "foo",
 				Type: endpoint.RecordTypeA,
 				Data: "10.11.12.13",
-				TTL:  defaultTTL,
+				TTL:  int(provider.DefaultTTL),
 			},
 		},
 		{
@@ -545,7 +734,7 @@ func TestDigitalOceanProcessUpdateActions(t *testing.T) {
 				Name: "@",
 				Type: endpoint.RecordTypeCNAME,
 				Data: "bar.example.com.",
-				TTL:  defaultTTL,
+				TTL:  int(provider.DefaultTTL),
 			},
 		},
 		{
@@ -555,7 +744,7 @@ func TestDigitalOceanProcessUpdateActions(t *testing.T) {
 				Type:     endpoint.RecordTypeMX,
 				Data:     "mx3.example.com.",
 				Priority: 10,
-				TTL:      defaultTTL,
+				TTL:      int(provider.DefaultTTL),
 			},
 		},
 		{
@@ -564,7 +753,7 @@ func TestDigitalOceanProcessUpdateActions(t *testing.T) {
 				Name: "@",
 				Type: endpoint.RecordTypeTXT,
 				Data: "ANOTHER-TXT",
-				TTL:  defaultTTL,
+				TTL:  int(provider.DefaultTTL),
 			},
 		},
 	}
@@ -613,7 +802,7 @@ func TestDigitalOceanProcessDeleteActions(t *testing.T) {
 				Name: "foo",
 				Type: endpoint.RecordTypeA,
 				Data: "1.2.3.4",
-				TTL:  defaultTTL,
+				TTL:  int(provider.DefaultTTL),
 			},
 			// This record will not be deleted because it represents a target not specified to be deleted.
 			{
@@ -621,14 +810,14 @@ func TestDigitalOceanProcessDeleteActions(t *testing.T) {
 				Name: "foo",
 				Type: endpoint.RecordTypeA,
 				Data: "5.6.7.8",
-				TTL:  defaultTTL,
+				TTL:  int(provider.DefaultTTL),
 			},
 			{
 				ID:   3,
 				Name: "@",
 				Type: endpoint.RecordTypeCNAME,
 				Data: "foo.example.com.",
-				TTL:  defaultTTL,
+				TTL:  int(provider.DefaultTTL),
 			},
 		},
 	}
@@ -666,12 +855,12 @@ func TestDigitalOceanProcessDeleteActions(t *testing.T) {
 
 func TestNewDigitalOceanProvider(t *testing.T) {
 	_ = os.Setenv("DO_TOKEN", "xxxxxxxxxxxxxxxxx")
-	_, err := NewDigitalOceanProvider(context.Background(), endpoint.NewDomainFilter([]string{"ext-dns-test.zalando.to."}), true, 50)
+	_, err := NewDigitalOceanProvider(context.Background(), endpoint.NewDomainFilter([]string{"ext-dns-test.zalando.to."}), true, 50, 1, 0)
 	if err != nil {
 		t.Errorf("should not fail, %s", err)
 	}
 	_ = os.Unsetenv("DO_TOKEN")
-	_, err = NewDigitalOceanProvider(context.Background(), endpoint.NewDomainFilter([]string{"ext-dns-test.zalando.to."}), true, 50)
+	_, err = NewDigitalOceanProvider(context.Background(), endpoint.NewDomainFilter([]string{"ext-dns-test.zalando.to."}), true, 50, 1, 0)
 	if err == nil {
 		t.Errorf("expected to fail")
 	}
@@ -809,6 +998,66 @@ func TestDigitalOceanAllRecords(t *testing.T) {
 	}
 }
 
+// mockDigitalOceanConcurrentRecords lists several domains with one record each and records the
+// maximum number of Records calls in flight.
+type mockDigitalOceanConcurrentRecords struct {
+	mockDigitalOceanClient
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (m *mockDigitalOceanConcurrentRecords) List(context.Context, *godo.ListOptions) ([]godo.Domain, *godo.Response, error) {
+	return []godo.Domain{{Name: "a.com"}, {Name: "b.com"}, {Name: "c.com"}, {Name: "d.com"}}, nil, nil
+}
+
+func (m *mockDigitalOceanConcurrentRecords) Records(ctx context.Context, domain string, opt *godo.ListOptions) ([]godo.DomainRecord, *godo.Response, error) {
+	m.mu.Lock()
+	m.inFlight++
+	m.maxInFlight = max(m.maxInFlight, m.inFlight)
+	m.mu.Unlock()
+
+	// give the other domains the chance to be fetched at the same time
+	time.Sleep(20 * time.Millisecond)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight--
+	return []godo.DomainRecord{{ID: 1, Name: "www", Type: endpoint.RecordTypeA, Data: "1.2.3.4", TTL: 300}}, nil, nil
+}
+
+func TestDigitalOceanRecordsConcurrency(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		concurrency int
+		maxInFlight int
+	}{
+		{name: "unset", concurrency: 0, maxInFlight: 1},
+		{name: "serial", concurrency: 1, maxInFlight: 1},
+		{name: "bounded", concurrency: 2, maxInFlight: 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &mockDigitalOceanConcurrentRecords{}
+			provider := &DigitalOceanProvider{
+				Client:             client,
+				domainFilter:       endpoint.NewDomainFilter([]string{}),
+				recordsConcurrency: tc.concurrency,
+			}
+
+			records, err := provider.Records(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.maxInFlight, client.maxInFlight)
+			assert.Equal(t, []*endpoint.Endpoint{
+				endpoint.NewEndpointWithTTL("www.a.com", endpoint.RecordTypeA, 300, "1.2.3.4"),
+				endpoint.NewEndpointWithTTL("www.b.com", endpoint.RecordTypeA, 300, "1.2.3.4"),
+				endpoint.NewEndpointWithTTL("www.c.com", endpoint.RecordTypeA, 300, "1.2.3.4"),
+				endpoint.NewEndpointWithTTL("www.d.com", endpoint.RecordTypeA, 300, "1.2.3.4"),
+			}, records)
+		})
+	}
+}
+
 func TestDigitalOceanMergeRecordsByNameType(t *testing.T) {
 	xs := []*endpoint.Endpoint{
 		endpoint.NewEndpoint("foo.example.com", "A", "1.2.3.4"),
@@ -821,7 +1070,7 @@ func TestDigitalOceanMergeRecordsByNameType(t *testing.T) {
 		endpoint.NewEndpoint("foo.example.com", "TXT", "txttwo"),
 	}
 
-	merged := mergeEndpointsByNameType(xs)
+	merged := provider.MergeEndpointsByNameType(xs)
 
 	assert.Len(t, merged, 5)
 	sort.SliceStable(merged, func(i, j int) bool {
@@ -853,3 +1102,67 @@ func TestDigitalOceanMergeRecordsByNameType(t *testing.T) {
 	assert.Len(t, merged[4].Targets, 2)
 	assert.ElementsMatch(t, []string{"txtone", "txttwo"}, merged[4].Targets)
 }
+
+func TestDigitalOceanMergeAndApplyApexRecords(t *testing.T) {
+	records := []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("example.com", endpoint.RecordTypeA, 300, "1.2.3.4"),
+		endpoint.NewEndpointWithTTL("example.com", endpoint.RecordTypeA, 300, "5.6.7.8"),
+		endpoint.NewEndpointWithTTL("example.com", endpoint.RecordTypeAAAA, 300, "2001:db8::1"),
+		endpoint.NewEndpointWithTTL("example.com", endpoint.RecordTypeAAAA, 300, "2001:db8::2"),
+	}
+
+	merged := provider.MergeEndpointsByNameType(records)
+	require.Len(t, merged, 2)
+	assert.Equal(t, "example.com", merged[0].DNSName)
+	assert.Equal(t, endpoint.RecordTypeA, merged[0].RecordType)
+	assert.Equal(t, endpoint.TTL(300), merged[0].RecordTTL)
+	assert.Equal(t, endpoint.Targets{"1.2.3.4", "5.6.7.8"}, merged[0].Targets)
+	assert.Equal(t, endpoint.RecordTypeAAAA, merged[1].RecordType)
+	assert.Equal(t, endpoint.Targets{"2001:db8::1", "2001:db8::2"}, merged[1].Targets)
+
+	recordsByDomain := map[string][]godo.DomainRecord{
+		"example.com": {
+			{ID: 1, Name: "@", Type: endpoint.RecordTypeA, Data: "1.2.3.4", TTL: 300},
+			{ID: 2, Name: "@", Type: endpoint.RecordTypeA, Data: "5.6.7.8", TTL: 300},
+		},
+	}
+
+	var changes digitalOceanChanges
+	err := processUpdateActions(recordsByDomain, map[string][]*endpoint.Endpoint{
+		"example.com": {
+			endpoint.NewEndpointWithTTL("example.com", endpoint.RecordTypeA, 600, "1.2.3.4", "9.9.9.9"),
+		},
+	}, 0, &changes)
+	require.NoError(t, err)
+	err = processCreateActions(recordsByDomain, map[string][]*endpoint.Endpoint{"example.com": {merged[1]}}, 0, &changes)
+	require.NoError(t, err)
+
+	expectedCreates := []*digitalOceanChangeCreate{
+		{
+			Domain:  "example.com",
+			Options: &godo.DomainRecordEditRequest{Name: "@", Type: endpoint.RecordTypeA, Data: "9.9.9.9", TTL: 600},
+		},
+		{
+			Domain:  "example.com",
+			Options: &godo.DomainRecordEditRequest{Name: "@", Type: endpoint.RecordTypeAAAA, Data: "2001:db8::1", TTL: 300},
+		},
+		{
+			Domain:  "example.com",
+			Options: &godo.DomainRecordEditRequest{Name: "@", Type: endpoint.RecordTypeAAAA, Data: "2001:db8::2", TTL: 300},
+		},
+	}
+	expectedUpdates := []*digitalOceanChangeUpdate{
+		{
+			Domain:       "example.com",
+			DomainRecord: recordsByDomain["example.com"][0],
+			Options:      &godo.DomainRecordEditRequest{Name: "@", Type: endpoint.RecordTypeA, Data: "1.2.3.4", TTL: 600},
+		},
+	}
+	expectedDeletes := []*digitalOceanChangeDelete{
+		{Domain: "example.com", RecordID: 2},
+	}
+
+	assert.Equal(t, expectedCreates, changes.Creates)
+	assert.Equal(t, expectedUpdates, changes.Updates)
+	assert.Equal(t, expectedDeletes, changes.Deletes)
+}
@@ -19,10 +19,15 @@ package google
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/compute/metadata"
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/oauth2/google"
 	dns "google.golang.org/api/dns/v1"
@@ -30,6 +35,7 @@ import (
 	"google.golang.org/api/option"
 
 	extdnshttp "sigs.k8s.io/external-dns/pkg/http"
+	"sigs.k8s.io/external-dns/pkg/metrics"
 
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/plan"
@@ -38,8 +44,54 @@ import (
 
 const (
 	defaultTTL = 300
+
+	// providerSpecificRoutingPolicy, providerSpecificWeight and providerSpecificLocation describe the
+	// routing policy item an endpoint was read from.
+	providerSpecificRoutingPolicy = "google/routing-policy"
+	providerSpecificWeight        = "google/weight"
+	providerSpecificLocation      = "google/location"
+	// providerSpecificOwnershipTXT marks TXT endpoints whose name follows the TXT registry's naming scheme
+	providerSpecificOwnershipTXT = "google/ownership-txt"
+
+	// txtRecordTypeTemplate is the placeholder for the record type in TXT registry prefixes and suffixes
+	txtRecordTypeTemplate = "%{record_type}"
+
+	routingPolicyWRR = "wrr"
+	routingPolicyGeo = "geo"
 )
 
+var (
+	// googleRecordTypes are the record types supported by the provider.
+	googleRecordTypes = []string{
+		endpoint.RecordTypeA,
+		endpoint.RecordTypeAAAA,
+		endpoint.RecordTypeCNAME,
+		endpoint.RecordTypeSRV,
+		endpoint.RecordTypeTXT,
+		endpoint.RecordTypeNS,
+		endpoint.RecordTypeMX,
+	}
+
+	recordChangesTotal = metrics.NewCounterVecWithOpts(
+		prometheus.CounterOpts{
+			Subsystem: "google_provider",
+			Name:      "record_changes_total",
+			Help:      "Number of record additions and deletions submitted to Google Cloud DNS (vector).",
+		},
+		[]string{"zone", "operation"},
+	)
+)
+
+func init() {
+	metrics.RegisterMetric.MustRegister(recordChangesTotal)
+}
+
+// changeTally holds the number of record additions and deletions of a change.
+type changeTally struct {
+	Additions int
+	Deletions int
+}
+
 type managedZonesCreateCallInterface interface {
 	Do(opts ...googleapi.CallOption) (*dns.ManagedZone, error)
 }
@@ -54,6 +106,8 @@ type managedZonesServiceInterface interface {
 }
 
 type resourceRecordSetsListCallInterface interface {
+	Name(name string) resourceRecordSetsListCallInterface
+	Type(recordType string) resourceRecordSetsListCallInterface
 	Pages(ctx context.Context, f func(*dns.ResourceRecordSetsListResponse) error) error
 }
 
@@ -62,6 +116,7 @@ type resourceRecordSetsClientInterface interface {
 }
 
 type changesCreateCallInterface interface {
+	Context(ctx context.Context) changesCreateCallInterface
 	Do(opts ...googleapi.CallOption) (*dns.Change, error)
 }
 
@@ -74,7 +129,23 @@ type resourceRecordSetsService struct {
 }
 
 func (r resourceRecordSetsService) List(project string, managedZone string) resourceRecordSetsListCallInterface {
-	return r.service.List(project, managedZone)
+	return resourceRecordSetsListCall{r.service.List(project, managedZone)}
+}
+
+type resourceRecordSetsListCall struct {
+	call *dns.ResourceRecordSetsListCall
+}
+
+func (r resourceRecordSetsListCall) Name(name string) resourceRecordSetsListCallInterface {
+	return resourceRecordSetsListCall{r.call.Name(name)}
+}
+
+func (r resourceRecordSetsListCall) Type(recordType string) resourceRecordSetsListCallInterface {
+	return resourceRecordSetsListCall{r.call.Type(recordType)}
+}
+
+func (r resourceRecordSetsListCall) Pages(ctx context.Context, f func(*dns.ResourceRecordSetsListResponse) error) error {
+	return r.call.Pages(ctx, f)
 }
 
 type managedZonesService struct {
@@ -94,7 +165,19 @@ type changesService struct {
 }
 
 func (c changesService) Create(project string, managedZone string, change *dns.Change) changesCreateCallInterface {
-	return c.service.Create(project, managedZone, change)
+	return changesCreateCall{c.service.Create(project, managedZone, change)}
+}
+
+type changesCreateCall struct {
+	call *dns.ChangesCreateCall
+}
+
+func (c changesCreateCall) Context(ctx context.Context) changesCreateCallInterface {
+	return changesCreateCall{c.call.Context(ctx)}
+}
+
+func (c changesCreateCall) Do(opts ...googleapi.CallOption) (*dns.Change, error) {
+	return c.call.Do(opts...)
 }
 
 // GoogleProvider is an implementation of Provider for Google CloudDNS.
@@ -108,12 +191,29 @@ type GoogleProvider struct {
 	batchChangeSize int
 	// Interval between batch updates.
 	batchChangeInterval time.Duration
+	// Timeout for each call to the Google Cloud DNS API, zero means no timeout.
+	requestTimeout time.Duration
 	// only consider hosted zones managing domains ending in this suffix
 	domainFilter *endpoint.DomainFilter
 	// filter for zones based on visibility
 	zoneTypeFilter provider.ZoneTypeFilter
+	// visibility preferred of zones sharing a DNS name, if not filtered by visibility
+	defaultZoneVisibility string
 	// only consider hosted zones ending with this zone id
 	zoneIDFilter provider.ZoneIDFilter
+	// never report or modify records with a name matching this regex
+	recordExclusion *regexp.Regexp
+	// never consider zones carrying this label, given as key or key=value
+	protectedZoneLabel string
+	// report but never modify records of zones whose name starts with one of these prefixes
+	readOnlyZonePrefixes []string
+	// only read the record sets with these fully qualified names, filtered server-side by name and type
+	recordNameFilter []string
+	// the TXT registry prefix and suffix, to also read the registry records of the filtered names
+	txtPrefix string
+	txtSuffix string
+	// tag the TXT records whose name matches this TXT registry naming scheme, if set
+	ownershipTXTName *regexp.Regexp
 	// A client for managing resource record sets
 	resourceRecordSetsClient resourceRecordSetsClientInterface
 	// A client for managing hosted zones
@@ -122,10 +222,40 @@ type GoogleProvider struct {
 	changesClient changesServiceInterface
 	// The context parameter to be passed for gcloud API calls.
 	ctx context.Context
+	// sleep pauses between batches, overridden in tests.
+	sleep func(time.Duration)
+}
+
+// GoogleConfig is comprised of the fields necessary to create a new GoogleProvider
+type GoogleConfig struct {
+	// Project is the Google project of the zones, auto-detected from the metadata server if empty
+	Project             string
+	DomainFilter        *endpoint.DomainFilter
+	ZoneIDFilter        provider.ZoneIDFilter
+	BatchChangeSize     int
+	BatchChangeInterval time.Duration
+	// RequestTimeout bounds each API call, 0 means no limit
+	RequestTimeout time.Duration
+	// ZoneVisibility filters the zones by visibility, public or private
+	ZoneVisibility string
+	// DefaultZoneVisibility is preferred among zones sharing a DNS name, if not filtered by visibility
+	DefaultZoneVisibility string
+	// RecordExclusion never reports or modifies records with a name matching it
+	RecordExclusion *regexp.Regexp
+	// ProtectedZoneLabel never considers zones carrying this label, given as key or key=value
+	ProtectedZoneLabel string
+	// ReadOnlyZonePrefixes reports but never modifies records of zones whose name starts with one of them
+	ReadOnlyZonePrefixes []string
+	// RecordNameFilter only reads the record sets with these fully qualified names and their TXT registry records
+	RecordNameFilter []string
+	// TXTPrefix and TXTSuffix are the TXT registry prefix and suffix
+	TXTPrefix string
+	TXTSuffix string
+	DryRun    bool
 }
 
 // NewGoogleProvider initializes a new Google CloudDNS based Provider.
-func NewGoogleProvider(ctx context.Context, project string, domainFilter *endpoint.DomainFilter, zoneIDFilter provider.ZoneIDFilter, batchChangeSize int, batchChangeInterval time.Duration, zoneVisibility string, dryRun bool) (*GoogleProvider, error) {
+func NewGoogleProvider(ctx context.Context, config GoogleConfig) (*GoogleProvider, error) {
 	gcloud, err := google.DefaultClient(ctx, dns.NdevClouddnsReadwriteScope)
 	if err != nil {
 		return nil, err
@@ -138,6 +268,7 @@ func NewGoogleProvider(ctx context.Context, project string, domainFilter *endpoi
 		return nil, err
 	}
 
+	project := config.Project
 	if project == "" {
 		mProject, mErr := metadata.ProjectIDWithContext(ctx)
 		if mErr != nil {
@@ -147,16 +278,25 @@ func NewGoogleProvider(ctx context.Context, project string, domainFilter *endpoi
 		project = mProject
 	}
 
-	zoneTypeFilter := provider.NewZoneTypeFilter(zoneVisibility)
+	zoneTypeFilter := provider.NewZoneTypeFilter(config.ZoneVisibility)
 
 	return &GoogleProvider{
 		project:                  project,
-		dryRun:                   dryRun,
-		batchChangeSize:          batchChangeSize,
-		batchChangeInterval:      batchChangeInterval,
-		domainFilter:             domainFilter,
+		dryRun:                   config.DryRun,
+		batchChangeSize:          config.BatchChangeSize,
+		batchChangeInterval:      config.BatchChangeInterval,
+		requestTimeout:           config.RequestTimeout,
+		domainFilter:             config.DomainFilter,
 		zoneTypeFilter:           zoneTypeFilter,
-		zoneIDFilter:             zoneIDFilter,
+		defaultZoneVisibility:    config.DefaultZoneVisibility,
+		zoneIDFilter:             config.ZoneIDFilter,
+		recordExclusion:          config.RecordExclusion,
+		protectedZoneLabel:       config.ProtectedZoneLabel,
+		readOnlyZonePrefixes:     config.ReadOnlyZonePrefixes,
+		recordNameFilter:         config.RecordNameFilter,
+		txtPrefix:                config.TXTPrefix,
+		txtSuffix:                config.TXTSuffix,
+		ownershipTXTName:         ownershipTXTNamePattern(config.TXTPrefix, config.TXTSuffix),
 		resourceRecordSetsClient: resourceRecordSetsService{dnsClient.ResourceRecordSets},
 		managedZonesClient:       managedZonesService{dnsClient.ManagedZones},
 		changesClient:            changesService{dnsClient.Changes},
@@ -167,12 +307,17 @@ func NewGoogleProvider(ctx context.Context, project string, domainFilter *endpoi
 // Zones returns the list of hosted zones.
 func (p *GoogleProvider) Zones(ctx context.Context) (map[string]*dns.ManagedZone, error) {
 	zones := make(map[string]*dns.ManagedZone)
+	var matched []*dns.ManagedZone
 
 	f := func(resp *dns.ManagedZonesListResponse) error {
 		for _, zone := range resp.ManagedZones {
+			if p.isZoneProtected(zone) {
+				log.Debugf("Filtered protected zone %s (zone: %s) (visibility: %s)", zone.DnsName, zone.Name, zone.Visibility)
+				continue
+			}
 			if zone.PeeringConfig == nil {
-				if p.domainFilter.Match(zone.DnsName) && p.zoneTypeFilter.Match(zone.Visibility) && (p.zoneIDFilter.Match(fmt.Sprintf("%v", zone.Id)) || p.zoneIDFilter.Match(fmt.Sprintf("%v", zone.Name))) {
-					zones[zone.Name] = zone
+				if p.domainFilter.Match(zone.DnsName) && p.zoneTypeFilter.Match(zone.Visibility) && p.matchZoneIDFilter(zone) {
+					matched = append(matched, zone)
 					log.Debugf("Matched %s (zone: %s) (visibility: %s)", zone.DnsName, zone.Name, zone.Visibility)
 				} else {
 					log.Debugf("Filtered %s (zone: %s) (visibility: %s)", zone.DnsName, zone.Name, zone.Visibility)
@@ -186,10 +331,16 @@ func (p *GoogleProvider) Zones(ctx context.Context) (map[string]*dns.ManagedZone
 	}
 
 	log.Debugf("Matching zones against domain filters: %v", p.domainFilter)
-	if err := p.managedZonesClient.List(p.project).Pages(ctx, f); err != nil {
+	reqCtx, cancel := p.requestContext(ctx)
+	defer cancel()
+	if err := p.managedZonesClient.List(p.project).Pages(reqCtx, f); err != nil {
 		return nil, provider.NewSoftError(fmt.Errorf("failed to list zones: %w", err))
 	}
 
+	for _, zone := range p.preferDefaultZoneVisibility(matched) {
+		zones[zone.Name] = zone
+	}
+
 	if len(zones) == 0 {
 		log.Warnf("No zones in the project, %s, match domain filters: %v", p.project, p.domainFilter)
 	}
@@ -201,6 +352,160 @@ func (p *GoogleProvider) Zones(ctx context.Context) (map[string]*dns.ManagedZone
 	return zones, nil
 }
 
+// preferDefaultZoneVisibility drops the zones sharing their DNS name with a zone of the default
+// visibility, so that split-horizon setups resolve to the public or the private zone as configured.
+func (p *GoogleProvider) preferDefaultZoneVisibility(zones []*dns.ManagedZone) []*dns.ManagedZone {
+	if p.defaultZoneVisibility == "" {
+		return zones
+	}
+	preferred := make(map[string]bool)
+	for _, zone := range zones {
+		if zone.Visibility == p.defaultZoneVisibility {
+			preferred[zone.DnsName] = true
+		}
+	}
+	return slices.DeleteFunc(zones, func(zone *dns.ManagedZone) bool {
+		if preferred[zone.DnsName] && zone.Visibility != p.defaultZoneVisibility {
+			log.Debugf("Filtered %s (zone: %s) (visibility: %s) in favor of a %s zone", zone.DnsName, zone.Name, zone.Visibility, p.defaultZoneVisibility)
+			return true
+		}
+		return false
+	})
+}
+
+// matchZoneIDFilter reports whether the zone ID filter matches the zone's ID, name or DNS name.
+// The DNS name is matched both with and without its trailing dot.
+func (p *GoogleProvider) matchZoneIDFilter(zone *dns.ManagedZone) bool {
+	return p.zoneIDFilter.Match(fmt.Sprintf("%v", zone.Id)) ||
+		p.zoneIDFilter.Match(zone.Name) ||
+		p.zoneIDFilter.Match(zone.DnsName) ||
+		p.zoneIDFilter.Match(strings.TrimSuffix(zone.DnsName, "."))
+}
+
+// isZoneProtected reports whether the zone carries the protected zone label.
+// A label given without a value protects zones carrying the label key with any value.
+func (p *GoogleProvider) isZoneProtected(zone *dns.ManagedZone) bool {
+	if p.protectedZoneLabel == "" {
+		return false
+	}
+	key, value, hasValue := strings.Cut(p.protectedZoneLabel, "=")
+	zoneValue, ok := zone.Labels[key]
+	return ok && (!hasValue || zoneValue == value)
+}
+
+// isZoneReadOnly reports whether the zone name starts with one of the read-only zone prefixes.
+func (p *GoogleProvider) isZoneReadOnly(zone string) bool {
+	for _, prefix := range p.readOnlyZonePrefixes {
+		if prefix != "" && strings.HasPrefix(zone, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestContext derives the context of a single call to the Google Cloud DNS API,
+// bounded by the configured request timeout.
+func (p *GoogleProvider) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.requestTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, p.requestTimeout)
+}
+
+// listRecords lists the record sets of a zone, passing each page to f. With a record name
+// filter, only the record sets matching the filters of the zone are requested.
+func (p *GoogleProvider) listRecords(ctx context.Context, zone *dns.ManagedZone, f func(*dns.ResourceRecordSetsListResponse) error) error {
+	if len(p.recordNameFilter) == 0 {
+		reqCtx, cancel := p.requestContext(ctx)
+		defer cancel()
+		return p.resourceRecordSetsClient.List(p.project, zone.Name).Pages(reqCtx, f)
+	}
+	for _, filter := range p.recordFiltersInZone(zone) {
+		if err := p.listRecordsByNameAndType(ctx, zone.Name, filter.name, filter.recordType, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listRecordsByNameAndType lists the record sets with the given name and type in a zone, passing each page to f.
+// An empty type lists the record sets of every type at the name.
+func (p *GoogleProvider) listRecordsByNameAndType(ctx context.Context, zone, name, recordType string, f func(*dns.ResourceRecordSetsListResponse) error) error {
+	reqCtx, cancel := p.requestContext(ctx)
+	defer cancel()
+	call := p.resourceRecordSetsClient.List(p.project, zone).Name(name)
+	if recordType != "" {
+		call = call.Type(recordType)
+	}
+	return call.Pages(reqCtx, f)
+}
+
+// recordFilter is the name and type of the record sets requested by a single list call,
+// an empty type requests every type.
+type recordFilter struct {
+	name       string
+	recordType string
+}
+
+// recordFiltersInZone returns the list call filters for the names of the record name filter that
+// belong to the zone: one call for all record types at the name, and one per TXT record name a
+// TXT registry may write for the name, so the registry keeps seeing which records it owns.
+func (p *GoogleProvider) recordFiltersInZone(zone *dns.ManagedZone) []recordFilter {
+	var filters []recordFilter
+	seen := map[recordFilter]bool{}
+	add := func(filter recordFilter) {
+		if !seen[filter] {
+			seen[filter] = true
+			filters = append(filters, filter)
+		}
+	}
+	for _, name := range p.recordNameFilter {
+		name = provider.EnsureTrailingDot(strings.ToLower(name))
+		if name != zone.DnsName && !strings.HasSuffix(name, "."+zone.DnsName) {
+			continue
+		}
+		add(recordFilter{name: name})
+		for _, txtName := range ownershipTXTNames(name, p.txtPrefix, p.txtSuffix) {
+			// without an affix the legacy registry record shares the name, already listed above
+			if txtName != name {
+				add(recordFilter{name: txtName, recordType: endpoint.RecordTypeTXT})
+			}
+		}
+	}
+	return filters
+}
+
+// ownershipTXTNames returns the names of the TXT records a TXT registry with the given prefix or
+// suffix may have written for a record name: one per record type, and the legacy name without
+// a record type. The affixes are applied to the first label of the name.
+func ownershipTXTNames(name, prefix, suffix string) []string {
+	label, rest, _ := strings.Cut(name, ".")
+	txtName := func(prefix, label, suffix string) string {
+		return strings.ToLower(prefix + label + suffix + "." + rest)
+	}
+	dropTemplate := func(affix string) string {
+		return strings.ReplaceAll(affix, txtRecordTypeTemplate, "")
+	}
+	names := []string{txtName(dropTemplate(prefix), label, dropTemplate(suffix))}
+	for _, recordType := range googleRecordTypes {
+		recordType = strings.ToLower(recordType)
+		if strings.Contains(prefix, txtRecordTypeTemplate) || strings.Contains(suffix, txtRecordTypeTemplate) {
+			names = append(names, txtName(strings.ReplaceAll(prefix, txtRecordTypeTemplate, recordType), label, strings.ReplaceAll(suffix, txtRecordTypeTemplate, recordType)))
+		} else {
+			names = append(names, txtName(prefix, recordType+"-"+label, suffix))
+		}
+	}
+	return names
+}
+
+// createChange submits a change to a zone.
+func (p *GoogleProvider) createChange(ctx context.Context, zone string, change *dns.Change) error {
+	reqCtx, cancel := p.requestContext(ctx)
+	defer cancel()
+	_, err := p.changesClient.Create(p.project, zone, change).Context(reqCtx).Do()
+	return err
+}
+
 // Records returns the list of records in all relevant zones.
 func (p *GoogleProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	zones, err := p.Zones(ctx)
@@ -210,19 +515,32 @@ func (p *GoogleProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, err
 
 	endpoints := make([]*endpoint.Endpoint, 0)
 
+	// f converts each page as it arrives, so the record sets of a zone are never held in memory at once.
 	f := func(resp *dns.ResourceRecordSetsListResponse) error {
 		for _, r := range resp.Rrsets {
 			if !p.SupportedRecordType(r.Type) {
 				continue
 			}
-			endpoints = append(endpoints, endpoint.NewEndpointWithTTL(r.Name, r.Type, endpoint.TTL(r.Ttl), r.Rrdatas...))
+			if p.isRecordExcluded(r.Name) {
+				log.Debugf("Skipping record %s because it matches the record exclusion", r.Name)
+				continue
+			}
+			if r.RoutingPolicy != nil {
+				endpoints = append(endpoints, routingPolicyEndpoints(r)...)
+				continue
+			}
+			ep := endpoint.NewEndpointWithTTL(r.Name, r.Type, endpoint.TTL(r.Ttl), r.Rrdatas...)
+			if r.Type == endpoint.RecordTypeTXT && p.ownershipTXTName != nil && p.ownershipTXTName.MatchString(r.Name) {
+				ep = ep.WithProviderSpecific(providerSpecificOwnershipTXT, "true")
+			}
+			endpoints = append(endpoints, ep)
 		}
 
 		return nil
 	}
 
 	for _, z := range zones {
-		if err := p.resourceRecordSetsClient.List(p.project, z.Name).Pages(ctx, f); err != nil {
+		if err := p.listRecords(ctx, z, f); err != nil {
 			return nil, provider.NewSoftErrorf("failed to list records in zone %s: %v", z.Name, err)
 		}
 	}
@@ -230,28 +548,190 @@ func (p *GoogleProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, err
 	return endpoints, nil
 }
 
+// ownershipTXTNamePattern returns the pattern of the TXT record names written by a TXT registry
+// with the given prefix or suffix, which the registry applies to the first label of the name.
+// A record type template in the affix matches any record type. It returns nil without an affix
+// or with both, as the registry then uses neither.
+func ownershipTXTNamePattern(prefix, suffix string) *regexp.Regexp {
+	quote := func(affix string) string {
+		return strings.ReplaceAll(regexp.QuoteMeta(strings.ToLower(affix)), regexp.QuoteMeta(txtRecordTypeTemplate), "[a-z]+")
+	}
+	switch {
+	case prefix != "" && suffix == "":
+		return regexp.MustCompile(`(?i)^` + quote(prefix) + `[^.]`)
+	case suffix != "" && prefix == "":
+		return regexp.MustCompile(`(?i)^[^.]+` + quote(suffix) + `(\.|$)`)
+	default:
+		return nil
+	}
+}
+
+// routingPolicyEndpoints returns one set-identified endpoint per item of the routing policy
+// of the record set. Weighted round robin items are identified by their index, geolocation
+// items by their location. Other routing policies are not supported and yield no endpoints.
+// The endpoints are read-only, newFilteredRecords never turns them into changes.
+func routingPolicyEndpoints(r *dns.ResourceRecordSet) []*endpoint.Endpoint {
+	var endpoints []*endpoint.Endpoint
+	switch policy := r.RoutingPolicy; {
+	case policy.Wrr != nil:
+		for i, item := range policy.Wrr.Items {
+			endpoints = append(endpoints, endpoint.NewEndpointWithTTL(r.Name, r.Type, endpoint.TTL(r.Ttl), item.Rrdatas...).
+				WithSetIdentifier(fmt.Sprintf("%s-%d", routingPolicyWRR, i)).
+				WithProviderSpecific(providerSpecificRoutingPolicy, routingPolicyWRR).
+				WithProviderSpecific(providerSpecificWeight, strconv.FormatFloat(item.Weight, 'f', -1, 64)))
+		}
+	case policy.Geo != nil:
+		for _, item := range policy.Geo.Items {
+			endpoints = append(endpoints, endpoint.NewEndpointWithTTL(r.Name, r.Type, endpoint.TTL(r.Ttl), item.Rrdatas...).
+				WithSetIdentifier(item.Location).
+				WithProviderSpecific(providerSpecificRoutingPolicy, routingPolicyGeo).
+				WithProviderSpecific(providerSpecificLocation, item.Location))
+		}
+	default:
+		log.Debugf("Skipping record %s %s because its routing policy is not supported", r.Name, r.Type)
+	}
+	return endpoints
+}
+
 // ApplyChanges applies a given set of changes in a given zone.
 func (p *GoogleProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	change := &dns.Change{}
 
-	change.Additions = append(change.Additions, p.newFilteredRecords(changes.Create)...)
+	creates := p.newFilteredRecords(changes.Create)
+	change.Additions = append(change.Additions, creates...)
 
 	change.Additions = append(change.Additions, p.newFilteredRecords(changes.UpdateNew())...)
 	change.Deletions = append(change.Deletions, p.newFilteredRecords(changes.UpdateOld())...)
 
 	change.Deletions = append(change.Deletions, p.newFilteredRecords(changes.Delete)...)
 
-	return p.submitChange(ctx, change)
+	if len(change.Additions) == 0 && len(change.Deletions) == 0 {
+		log.Info("All records are already up to date")
+		return nil
+	}
+
+	zones, err := p.Zones(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Cloud DNS rejects additions of an existing record set unless the change also deletes it.
+	if len(creates) > 0 {
+		replaced, err := p.replacedRecords(ctx, zones, creates, change.Deletions)
+		if err != nil {
+			return err
+		}
+		change.Deletions = append(change.Deletions, replaced...)
+	}
+
+	return p.submitChange(ctx, zones, dropUnchangedRecords(change))
+}
+
+// dropUnchangedRecords removes the additions which are identical to a deletion of the change
+// together with that deletion, so that record sets already in the desired state are not
+// rewritten.
+func dropUnchangedRecords(change *dns.Change) *dns.Change {
+	key := func(r *dns.ResourceRecordSet) string {
+		rrdatas := slices.Clone(r.Rrdatas)
+		slices.Sort(rrdatas)
+		return fmt.Sprintf("%s/%s/%d/%s", r.Type, provider.EnsureTrailingDot(r.Name), r.Ttl, strings.Join(rrdatas, ","))
+	}
+
+	deleted := make(map[string]int, len(change.Deletions))
+	for _, d := range change.Deletions {
+		if d.RoutingPolicy == nil {
+			deleted[key(d)]++
+		}
+	}
+
+	unchanged := make(map[string]int)
+	var additions []*dns.ResourceRecordSet
+	for _, a := range change.Additions {
+		k := key(a)
+		if a.RoutingPolicy == nil && deleted[k] > unchanged[k] {
+			log.Debugf("Skipping unchanged record set %s %s", a.Name, a.Type)
+			unchanged[k]++
+			continue
+		}
+		additions = append(additions, a)
+	}
+	if len(unchanged) == 0 {
+		return change
+	}
+
+	var deletions []*dns.ResourceRecordSet
+	for _, d := range change.Deletions {
+		if k := key(d); d.RoutingPolicy == nil && unchanged[k] > 0 {
+			unchanged[k]--
+			continue
+		}
+		deletions = append(deletions, d)
+	}
+
+	return &dns.Change{Additions: additions, Deletions: deletions}
+}
+
+// replacedRecords returns the existing record sets overwritten by the given additions
+// which are not already part of the given deletions. Only the record sets with the name
+// and type of such an addition are listed.
+func (p *GoogleProvider) replacedRecords(ctx context.Context, zones map[string]*dns.ManagedZone, additions, deletions []*dns.ResourceRecordSet) ([]*dns.ResourceRecordSet, error) {
+	key := func(r *dns.ResourceRecordSet) recordFilter {
+		return recordFilter{name: provider.EnsureTrailingDot(r.Name), recordType: r.Type}
+	}
+
+	deleted := make(map[recordFilter]bool, len(deletions))
+	for _, d := range deletions {
+		deleted[key(d)] = true
+	}
+	added := make(map[recordFilter]bool, len(additions))
+	var filters []recordFilter
+	for _, a := range additions {
+		if k := key(a); !deleted[k] && !added[k] {
+			added[k] = true
+			filters = append(filters, k)
+		}
+	}
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	zoneNameIDMapper := provider.ZoneIDName{}
+	for _, z := range zones {
+		zoneNameIDMapper[z.Name] = z.DnsName
+	}
+
+	var replaced []*dns.ResourceRecordSet
+	f := func(resp *dns.ResourceRecordSetsListResponse) error {
+		for _, r := range resp.Rrsets {
+			if !added[key(r)] {
+				continue
+			}
+			if r.RoutingPolicy != nil {
+				log.Warnf("Not replacing record set %s %s with a routing policy, the addition will fail", r.Name, r.Type)
+				continue
+			}
+			log.Debugf("Replacing existing record set %s %s", r.Name, r.Type)
+			replaced = append(replaced, r)
+		}
+		return nil
+	}
+
+	for _, filter := range filters {
+		zone, _ := zoneNameIDMapper.FindZone(filter.name)
+		if zone == "" {
+			continue
+		}
+		if err := p.listRecordsByNameAndType(ctx, zone, filter.name, filter.recordType, f); err != nil {
+			return nil, provider.NewSoftErrorf("failed to list records in zone %s: %v", zone, err)
+		}
+	}
+
+	return replaced, nil
 }
 
 // SupportedRecordType returns true if the record type is supported by the provider
 func (p *GoogleProvider) SupportedRecordType(recordType string) bool {
-	switch recordType {
-	case "MX":
-		return true
-	default:
-		return provider.SupportedRecordType(recordType)
-	}
+	return slices.Contains(googleRecordTypes, recordType)
 }
 
 // newFilteredRecords returns a collection of RecordSets based on the given endpoints and domainFilter.
@@ -259,6 +739,18 @@ func (p *GoogleProvider) newFilteredRecords(endpoints []*endpoint.Endpoint) []*d
 	var records []*dns.ResourceRecordSet
 
 	for _, ep := range endpoints {
+		if p.isRecordExcluded(ep.DNSName) {
+			log.Debugf("Skipping change of record %s because it matches the record exclusion", ep.DNSName)
+			continue
+		}
+		if !p.SupportedRecordType(ep.RecordType) {
+			log.Warnf("Skipping change of record %s because its type %s is not supported", ep.DNSName, ep.RecordType)
+			continue
+		}
+		if isRoutingPolicyEndpoint(ep) {
+			log.Warnf("Skipping change of record %s %s with set identifier %q because routing policy record sets are read-only", ep.DNSName, ep.RecordType, ep.SetIdentifier)
+			continue
+		}
 		if p.domainFilter.Match(ep.DNSName) {
 			records = append(records, newRecord(ep))
 		}
@@ -267,21 +759,46 @@ func (p *GoogleProvider) newFilteredRecords(endpoints []*endpoint.Endpoint) []*d
 	return records
 }
 
-// submitChange takes a zone and a Change and sends it to Google.
-func (p *GoogleProvider) submitChange(ctx context.Context, change *dns.Change) error {
+// isRoutingPolicyEndpoint reports whether the endpoint is an item of a routing policy record set.
+// Cloud DNS only accepts changes of such record sets as a whole, including their routing policy.
+func isRoutingPolicyEndpoint(ep *endpoint.Endpoint) bool {
+	if ep.SetIdentifier != "" {
+		return true
+	}
+	_, ok := ep.GetProviderSpecificProperty(providerSpecificRoutingPolicy)
+	return ok
+}
+
+// isRecordExcluded reports whether the record with the given name must not be managed.
+func (p *GoogleProvider) isRecordExcluded(name string) bool {
+	if p.recordExclusion == nil || p.recordExclusion.String() == "" {
+		return false
+	}
+	return p.recordExclusion.MatchString(strings.TrimSuffix(name, "."))
+}
+
+// submitChange separates a Change into the given zones and sends it to Google.
+func (p *GoogleProvider) submitChange(ctx context.Context, zones map[string]*dns.ManagedZone, change *dns.Change) error {
 	if len(change.Additions) == 0 && len(change.Deletions) == 0 {
 		log.Info("All records are already up to date")
 		return nil
 	}
 
-	zones, err := p.Zones(ctx)
-	if err != nil {
-		return err
-	}
-
 	// separate into per-zone change sets to be passed to the API.
 	changes := separateChange(zones, change)
 
+	for zone, c := range changes {
+		if p.isZoneReadOnly(zone) {
+			log.Infof("Skipping %d additions and %d deletions in zone %s because it is read-only", len(c.Additions), len(c.Deletions), zone)
+			delete(changes, zone)
+		}
+	}
+
+	for zone, tally := range tallyChanges(changes) {
+		log.Infof("Change zone: %v additions: %d deletions: %d", zone, tally.Additions, tally.Deletions)
+	}
+
+	submitted := 0
 	for zone, change := range changes {
 		for batch, c := range batchChange(change, p.batchChangeSize) {
 			log.Infof("Change zone: %v batch #%d", zone, batch)
@@ -296,17 +813,43 @@ func (p *GoogleProvider) submitChange(ctx context.Context, change *dns.Change) e
 				continue
 			}
 
-			if _, err := p.changesClient.Create(p.project, zone, c).Do(); err != nil {
+			// pace the batches to stay within the Cloud DNS rate limits
+			if submitted > 0 {
+				p.pause(p.batchChangeInterval)
+			}
+			if err := p.createChange(ctx, zone, c); err != nil {
 				return provider.NewSoftError(fmt.Errorf("failed to create changes: %w", err))
 			}
-
-			time.Sleep(p.batchChangeInterval)
+			submitted++
+			recordChangesTotal.CounterVec.WithLabelValues(zone, "addition").Add(float64(len(c.Additions)))
+			recordChangesTotal.CounterVec.WithLabelValues(zone, "deletion").Add(float64(len(c.Deletions)))
 		}
 	}
 
 	return nil
 }
 
+// pause sleeps for the given duration.
+func (p *GoogleProvider) pause(d time.Duration) {
+	if p.sleep != nil {
+		p.sleep(d)
+		return
+	}
+	time.Sleep(d)
+}
+
+// tallyChanges counts the record additions and deletions of each per-zone change.
+func tallyChanges(changes map[string]*dns.Change) map[string]changeTally {
+	tallies := make(map[string]changeTally, len(changes))
+	for zone, change := range changes {
+		tallies[zone] = changeTally{
+			Additions: len(change.Additions),
+			Deletions: len(change.Deletions),
+		}
+	}
+	return tallies
+}
+
 // batchChange separates a zone in multiple transaction.
 func batchChange(change *dns.Change, batchSize int) []*dns.Change {
 	var changes []*dns.Change
@@ -20,9 +20,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -92,10 +94,39 @@ func (m *mockManagedZonesClient) List(project string) managedZonesListCallInterf
 	return &mockManagedZonesListCall{project: project, zonesListSoftErr: m.zonesErr}
 }
 
+// blockingManagedZonesListCall blocks until its context is done.
+type blockingManagedZonesListCall struct{}
+
+func (m *blockingManagedZonesListCall) Pages(ctx context.Context, f func(*dns.ManagedZonesListResponse) error) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+type blockingManagedZonesClient struct {
+	mockManagedZonesClient
+}
+
+func (m *blockingManagedZonesClient) List(project string) managedZonesListCallInterface {
+	return &blockingManagedZonesListCall{}
+}
+
 type mockResourceRecordSetsListCall struct {
 	project            string
 	managedZone        string
+	name               string
+	recordType         string
 	recordsListSoftErr error
+	client             *mockResourceRecordSetsClient
+}
+
+func (m *mockResourceRecordSetsListCall) Name(name string) resourceRecordSetsListCallInterface {
+	m.name = name
+	return m
+}
+
+func (m *mockResourceRecordSetsListCall) Type(recordType string) resourceRecordSetsListCallInterface {
+	m.recordType = recordType
+	return m
 }
 
 func (m *mockResourceRecordSetsListCall) Pages(ctx context.Context, f func(*dns.ResourceRecordSetsListResponse) error) error {
@@ -105,9 +136,19 @@ func (m *mockResourceRecordSetsListCall) Pages(ctx context.Context, f func(*dns.
 		return &googleapi.Error{Code: http.StatusNotFound}
 	}
 
+	if m.name != "" || m.recordType != "" {
+		m.client.filters = append(m.client.filters, recordFilter{name: m.name, recordType: m.recordType})
+	}
+
 	resp := []*dns.ResourceRecordSet{}
 
 	for _, v := range testRecords[zoneKey] {
+		if m.name != "" && v.Name != m.name {
+			continue
+		}
+		if m.recordType != "" && v.Type != m.recordType {
+			continue
+		}
 		resp = append(resp, v)
 	}
 
@@ -115,15 +156,40 @@ func (m *mockResourceRecordSetsListCall) Pages(ctx context.Context, f func(*dns.
 		return m.recordsListSoftErr
 	}
 
-	return f(&dns.ResourceRecordSetsListResponse{Rrsets: resp})
+	if m.client.pageSize == 0 {
+		m.client.pages++
+		return f(&dns.ResourceRecordSetsListResponse{Rrsets: resp})
+	}
+
+	sort.Slice(resp, func(i, j int) bool {
+		return recordKey(resp[i].Type, resp[i].Name) < recordKey(resp[j].Type, resp[j].Name)
+	})
+	for start := 0; start < len(resp); start += m.client.pageSize {
+		end := min(start+m.client.pageSize, len(resp))
+		page := &dns.ResourceRecordSetsListResponse{Rrsets: resp[start:end]}
+		if end < len(resp) {
+			page.NextPageToken = fmt.Sprintf("page-%d", end)
+		}
+		m.client.pages++
+		if err := f(page); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type mockResourceRecordSetsClient struct {
 	recordsErr error
+	// filters records the name and type filters of the list calls.
+	filters []recordFilter
+	// pageSize splits list responses into pages of at most that many record sets.
+	pageSize int
+	// pages counts the pages passed to list callbacks.
+	pages int
 }
 
 func (m *mockResourceRecordSetsClient) List(project string, managedZone string) resourceRecordSetsListCallInterface {
-	return &mockResourceRecordSetsListCall{project: project, managedZone: managedZone, recordsListSoftErr: m.recordsErr}
+	return &mockResourceRecordSetsListCall{project: project, managedZone: managedZone, recordsListSoftErr: m.recordsErr, client: m}
 }
 
 type mockChangesCreateCall struct {
@@ -132,6 +198,10 @@ type mockChangesCreateCall struct {
 	change      *dns.Change
 }
 
+func (m *mockChangesCreateCall) Context(ctx context.Context) changesCreateCallInterface {
+	return m
+}
+
 func (m *mockChangesCreateCall) Do(opts ...googleapi.CallOption) (*dns.Change, error) {
 	zoneKey := zoneKey(m.project, m.managedZone)
 
@@ -152,6 +222,21 @@ func (m *mockChangesCreateCall) Do(opts ...googleapi.CallOption) (*dns.Change, e
 		}
 	}
 
+	deleted := make(map[string]bool, len(m.change.Deletions))
+	for _, del := range m.change.Deletions {
+		deleted[recordKey(del.Type, del.Name)] = true
+	}
+
+	for _, add := range m.change.Additions {
+		recordKey := recordKey(add.Type, add.Name)
+		if _, ok := testRecords[zoneKey][recordKey]; ok && !deleted[recordKey] {
+			return nil, &googleapi.Error{
+				Code:    http.StatusConflict,
+				Message: fmt.Sprintf("record already exists: %v", add),
+			}
+		}
+	}
+
 	for _, del := range m.change.Deletions {
 		recordKey := recordKey(del.Type, del.Name)
 		delete(testRecords[zoneKey], recordKey)
@@ -198,7 +283,7 @@ func isValidRecordSet(recordSet *dns.ResourceRecordSet) bool {
 			}
 		}
 	default:
-		panic("unhandled record type")
+		return false
 	}
 
 	return true
@@ -230,6 +315,21 @@ func TestGoogleZonesNameFilter(t *testing.T) {
 	})
 }
 
+func TestGoogleZonesDnsNameFilter(t *testing.T) {
+	for _, filter := range []string{"svc.local", "svc.local."} {
+		t.Run(filter, func(t *testing.T) {
+			provider := newGoogleProviderZoneOverlap(t, endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{filter}), provider.NewZoneTypeFilter(""), false, []*endpoint.Endpoint{})
+
+			zones, err := provider.Zones(context.Background())
+			require.NoError(t, err)
+
+			validateZones(t, zones, map[string]*dns.ManagedZone{
+				"svc-local": {Name: "svc-local", DnsName: "svc.local.", Id: 10005, Visibility: "private"},
+			})
+		})
+	}
+}
+
 func TestGoogleZonesVisibilityFilterPublic(t *testing.T) {
 	provider := newGoogleProviderZoneOverlap(t, endpoint.NewDomainFilter([]string{"cluster.local."}), provider.NewZoneIDFilter([]string{"split-horizon-1"}), provider.NewZoneTypeFilter("public"), false, []*endpoint.Endpoint{})
 
@@ -252,6 +352,41 @@ func TestGoogleZonesVisibilityFilterPrivate(t *testing.T) {
 	})
 }
 
+func TestGoogleZonesDefaultVisibility(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		defaultVisibility string
+		expected          map[string]*dns.ManagedZone
+	}{
+		{
+			name:              "public",
+			defaultVisibility: "public",
+			expected: map[string]*dns.ManagedZone{
+				"split-horizon-1": {Name: "split-horizon-1", DnsName: "cluster.local.", Id: 10004, Visibility: "public"},
+			},
+		},
+		{
+			name:              "private",
+			defaultVisibility: "private",
+			expected: map[string]*dns.ManagedZone{
+				"internal-1": {Name: "internal-1", DnsName: "cluster.local.", Id: 10001, Visibility: "private"},
+				"internal-2": {Name: "internal-2", DnsName: "cluster.local.", Id: 10002, Visibility: "private"},
+				"internal-3": {Name: "internal-3", DnsName: "cluster.local.", Id: 10003, Visibility: "private"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := newGoogleProviderZoneOverlap(t, endpoint.NewDomainFilter([]string{"cluster.local."}), provider.NewZoneIDFilter([]string{""}), provider.NewZoneTypeFilter(""), false, []*endpoint.Endpoint{})
+			provider.defaultZoneVisibility = tc.defaultVisibility
+
+			zones, err := provider.Zones(context.Background())
+			require.NoError(t, err)
+
+			validateZones(t, zones, tc.expected)
+		})
+	}
+}
+
 func TestGoogleZonesVisibilityFilterPrivatePeering(t *testing.T) {
 	provider := newGoogleProviderZoneOverlap(t, endpoint.NewDomainFilter([]string{"svc.local."}), provider.NewZoneIDFilter([]string{""}), provider.NewZoneTypeFilter("private"), false, []*endpoint.Endpoint{})
 
@@ -259,10 +394,44 @@ func TestGoogleZonesVisibilityFilterPrivatePeering(t *testing.T) {
 	require.NoError(t, err)
 
 	validateZones(t, zones, map[string]*dns.ManagedZone{
-		"svc-local": {Name: "svc-local", DnsName: "svc.local.", Id: 1005, Visibility: "private"},
+		"svc-local": {Name: "svc-local", DnsName: "svc.local.", Id: 10005, Visibility: "private"},
 	})
 }
 
+func TestGoogleZonesProtectedZoneLabel(t *testing.T) {
+	provider := newGoogleProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-5.gcp.zalan.do."}), provider.NewZoneIDFilter([]string{""}), false, []*endpoint.Endpoint{}, nil, nil)
+
+	createZone(t, provider, &dns.ManagedZone{
+		Name:    "protected-ext-dns-test-5-gcp-zalan-do",
+		DnsName: "protected.ext-dns-test-5.gcp.zalan.do.",
+		Labels:  map[string]string{"external-dns": "protected"},
+	})
+	createZone(t, provider, &dns.ManagedZone{
+		Name:    "unprotected-ext-dns-test-5-gcp-zalan-do",
+		DnsName: "unprotected.ext-dns-test-5.gcp.zalan.do.",
+		Labels:  map[string]string{"external-dns": "managed"},
+	})
+
+	zones, err := provider.Zones(context.Background())
+	require.NoError(t, err)
+	validateZones(t, zones, map[string]*dns.ManagedZone{
+		"protected-ext-dns-test-5-gcp-zalan-do":   {Name: "protected-ext-dns-test-5-gcp-zalan-do", DnsName: "protected.ext-dns-test-5.gcp.zalan.do."},
+		"unprotected-ext-dns-test-5-gcp-zalan-do": {Name: "unprotected-ext-dns-test-5-gcp-zalan-do", DnsName: "unprotected.ext-dns-test-5.gcp.zalan.do."},
+	})
+
+	provider.protectedZoneLabel = "external-dns=protected"
+	zones, err = provider.Zones(context.Background())
+	require.NoError(t, err)
+	validateZones(t, zones, map[string]*dns.ManagedZone{
+		"unprotected-ext-dns-test-5-gcp-zalan-do": {Name: "unprotected-ext-dns-test-5-gcp-zalan-do", DnsName: "unprotected.ext-dns-test-5.gcp.zalan.do."},
+	})
+
+	provider.protectedZoneLabel = "external-dns"
+	zones, err = provider.Zones(context.Background())
+	require.NoError(t, err)
+	validateZones(t, zones, map[string]*dns.ManagedZone{})
+}
+
 func TestGoogleRecords(t *testing.T) {
 	originalEndpoints := []*endpoint.Endpoint{
 		endpoint.NewEndpointWithTTL("list-test.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, endpoint.TTL(1), "1.2.3.4"),
@@ -278,6 +447,246 @@ func TestGoogleRecords(t *testing.T) {
 	validateEndpoints(t, records, originalEndpoints)
 }
 
+func TestGoogleRecordsPaginated(t *testing.T) {
+	var originalEndpoints []*endpoint.Endpoint
+	for i := range 7 {
+		originalEndpoints = append(originalEndpoints, endpoint.NewEndpointWithTTL(fmt.Sprintf("page-test-%d.zone-1.ext-dns-test-2.gcp.zalan.do", i), endpoint.RecordTypeA, endpoint.TTL(300), fmt.Sprintf("10.0.0.%d", i)))
+	}
+
+	provider := newGoogleProvider(t, endpoint.NewDomainFilter([]string{"zone-1.ext-dns-test-2.gcp.zalan.do."}), provider.NewZoneIDFilter([]string{""}), false, originalEndpoints, nil, nil)
+	client := provider.resourceRecordSetsClient.(*mockResourceRecordSetsClient)
+	client.pageSize = 2
+	client.pages = 0
+	recordSets := len(testRecords[zoneKey(provider.project, "zone-1-ext-dns-test-2-gcp-zalan-do")])
+
+	records, err := provider.Records(context.Background())
+	require.NoError(t, err)
+
+	validateEndpoints(t, records, originalEndpoints)
+	assert.GreaterOrEqual(t, recordSets, len(originalEndpoints))
+	assert.Equal(t, (recordSets+1)/2, client.pages, "the record sets should be read in pages of two")
+}
+
+func TestGoogleRecordsNameFilter(t *testing.T) {
+	originalEndpoints := []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("list-test.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, endpoint.TTL(1), "1.2.3.4"),
+		endpoint.NewEndpointWithTTL("list-test.zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, endpoint.TTL(2), "8.8.8.8"),
+		endpoint.NewEndpointWithTTL("txt-a-list-test.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeTXT, endpoint.TTL(300), "\"heritage=external-dns,external-dns/owner=default\""),
+		endpoint.NewEndpointWithTTL("txt-list-test.zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeTXT, endpoint.TTL(300), "\"heritage=external-dns,external-dns/owner=default\""),
+		endpoint.NewEndpointWithTTL("list-test-alias.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeCNAME, endpoint.TTL(3), "foo.elb.amazonaws.com"),
+		endpoint.NewEndpointWithTTL("txt-a-list-test-alias.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeTXT, endpoint.TTL(300), "\"heritage=external-dns,external-dns/owner=default\""),
+	}
+
+	provider := newGoogleProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}), provider.NewZoneIDFilter([]string{""}), false, originalEndpoints, nil, nil)
+	provider.recordNameFilter = []string{"list-test.zone-1.ext-dns-test-2.gcp.zalan.do", "List-Test.zone-2.ext-dns-test-2.gcp.zalan.do."}
+	provider.txtPrefix = "txt-"
+	provider.ownershipTXTName = ownershipTXTNamePattern(provider.txtPrefix, "")
+	t.Cleanup(func() {
+		for _, ep := range originalEndpoints {
+			if ep.RecordType == endpoint.RecordTypeTXT {
+				zone := zoneKey(provider.project, "zone-1-ext-dns-test-2-gcp-zalan-do")
+				if strings.Contains(ep.DNSName, "zone-2") {
+					zone = zoneKey(provider.project, "zone-2-ext-dns-test-2-gcp-zalan-do")
+				}
+				delete(testRecords[zone], recordKey(endpoint.RecordTypeTXT, ep.DNSName+"."))
+			}
+		}
+	})
+
+	// only count the calls of Records, not those setting up the records
+	provider.resourceRecordSetsClient.(*mockResourceRecordSetsClient).filters = nil
+	records, err := provider.Records(context.Background())
+	require.NoError(t, err)
+
+	validateEndpoints(t, records, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("list-test.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, endpoint.TTL(1), "1.2.3.4"),
+		endpoint.NewEndpointWithTTL("list-test.zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, endpoint.TTL(2), "8.8.8.8"),
+		endpoint.NewEndpointWithTTL("txt-a-list-test.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeTXT, endpoint.TTL(300), "\"heritage=external-dns,external-dns/owner=default\"").
+			WithProviderSpecific(providerSpecificOwnershipTXT, "true"),
+		endpoint.NewEndpointWithTTL("txt-list-test.zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeTXT, endpoint.TTL(300), "\"heritage=external-dns,external-dns/owner=default\"").
+			WithProviderSpecific(providerSpecificOwnershipTXT, "true"),
+	})
+
+	filters := provider.resourceRecordSetsClient.(*mockResourceRecordSetsClient).filters
+	var expected []recordFilter
+	for _, name := range []string{"list-test.zone-1.ext-dns-test-2.gcp.zalan.do.", "list-test.zone-2.ext-dns-test-2.gcp.zalan.do."} {
+		expected = append(expected, recordFilter{name: name})
+		for _, txtName := range ownershipTXTNames(name, "txt-", "") {
+			expected = append(expected, recordFilter{name: txtName, recordType: endpoint.RecordTypeTXT})
+		}
+	}
+	assert.ElementsMatch(t, expected, filters, "every filtered name and its registry records should be requested by a single call")
+}
+
+func TestGoogleOwnershipTXTNames(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		prefix   string
+		suffix   string
+		expected []string
+	}{
+		{
+			name:     "no affix",
+			expected: []string{"www.example.org.", "a-www.example.org.", "cname-www.example.org.", "mx-www.example.org."},
+		},
+		{
+			name:     "prefix",
+			prefix:   "TXT-",
+			expected: []string{"txt-www.example.org.", "txt-a-www.example.org.", "txt-aaaa-www.example.org.", "txt-txt-www.example.org."},
+		},
+		{
+			name:     "prefix with record type",
+			prefix:   "%{record_type}-txt.",
+			expected: []string{"-txt.www.example.org.", "a-txt.www.example.org.", "cname-txt.www.example.org.", "ns-txt.www.example.org."},
+		},
+		{
+			name:     "suffix",
+			suffix:   "-txt",
+			expected: []string{"www-txt.example.org.", "a-www-txt.example.org.", "srv-www-txt.example.org."},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			names := ownershipTXTNames("www.example.org.", tc.prefix, tc.suffix)
+			assert.Len(t, names, len(googleRecordTypes)+1)
+			assert.Subset(t, names, tc.expected)
+		})
+	}
+}
+
+func TestGoogleRecordsOwnershipTXT(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		prefix   string
+		suffix   string
+		owned    string
+		notOwned string
+	}{
+		{
+			name:     "prefix",
+			prefix:   "txt-",
+			owned:    "txt-a-owned.zone-1.ext-dns-test-2.gcp.zalan.do",
+			notOwned: "user.zone-1.ext-dns-test-2.gcp.zalan.do",
+		},
+		{
+			name:     "prefix with record type",
+			prefix:   "%{record_type}-txt.",
+			owned:    "cname-txt.owned.zone-1.ext-dns-test-2.gcp.zalan.do",
+			notOwned: "txt.user.zone-1.ext-dns-test-2.gcp.zalan.do",
+		},
+		{
+			name:     "suffix",
+			suffix:   "-txt",
+			owned:    "a-owned-txt.zone-1.ext-dns-test-2.gcp.zalan.do",
+			notOwned: "txt-user.zone-1.ext-dns-test-2.gcp.zalan.do",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			originalEndpoints := []*endpoint.Endpoint{
+				endpoint.NewEndpointWithTTL(tc.owned, endpoint.RecordTypeTXT, endpoint.TTL(300), "\"heritage=external-dns,external-dns/owner=default\""),
+				endpoint.NewEndpointWithTTL(tc.notOwned, endpoint.RecordTypeTXT, endpoint.TTL(300), "\"user text\""),
+			}
+			provider := newGoogleProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}), provider.NewZoneIDFilter([]string{""}), false, originalEndpoints, nil, nil)
+			provider.ownershipTXTName = ownershipTXTNamePattern(tc.prefix, tc.suffix)
+			zone := zoneKey(provider.project, "zone-1-ext-dns-test-2-gcp-zalan-do")
+			t.Cleanup(func() {
+				delete(testRecords[zone], recordKey(endpoint.RecordTypeTXT, tc.owned+"."))
+				delete(testRecords[zone], recordKey(endpoint.RecordTypeTXT, tc.notOwned+"."))
+			})
+
+			records, err := provider.Records(context.Background())
+			require.NoError(t, err)
+
+			tagged := map[string]bool{}
+			for _, r := range records {
+				value, ok := r.GetProviderSpecificProperty(providerSpecificOwnershipTXT)
+				tagged[strings.TrimSuffix(r.DNSName, ".")] = ok && value == "true"
+			}
+			assert.True(t, tagged[tc.owned], "%s should be tagged", tc.owned)
+			assert.Contains(t, tagged, tc.notOwned)
+			assert.False(t, tagged[tc.notOwned], "%s should not be tagged", tc.notOwned)
+		})
+	}
+}
+
+func TestGoogleOwnershipTXTNamePattern(t *testing.T) {
+	assert.Nil(t, ownershipTXTNamePattern("", ""), "no affix")
+	assert.Nil(t, ownershipTXTNamePattern("txt-", "-txt"), "the registry uses neither affix if both are set")
+
+	pattern := ownershipTXTNamePattern("TXT.", "")
+	assert.True(t, pattern.MatchString("txt.a-www.example.org."))
+	assert.True(t, pattern.MatchString("txt.example.org"), "legacy registry records carry no record type")
+	assert.False(t, pattern.MatchString("txt."), "the prefix must be followed by a name")
+	assert.False(t, pattern.MatchString("txtxa-www.example.org."), "the prefix is matched literally")
+
+	pattern = ownershipTXTNamePattern("", "-%{record_type}")
+	assert.True(t, pattern.MatchString("www-aaaa.example.org."))
+	assert.True(t, pattern.MatchString("www-aaaa"))
+	assert.False(t, pattern.MatchString("www.example-aaaa.org."), "the suffix applies to the first label")
+}
+
+func TestGoogleRecordsRoutingPolicy(t *testing.T) {
+	provider := newGoogleProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}), provider.NewZoneIDFilter([]string{""}), false, []*endpoint.Endpoint{}, nil, nil)
+
+	zone := zoneKey(provider.project, "zone-1-ext-dns-test-2-gcp-zalan-do")
+	if testRecords[zone] == nil {
+		testRecords[zone] = make(map[string]*dns.ResourceRecordSet)
+	}
+	t.Cleanup(func() {
+		delete(testRecords[zone], recordKey(endpoint.RecordTypeA, "wrr.zone-1.ext-dns-test-2.gcp.zalan.do."))
+		delete(testRecords[zone], recordKey(endpoint.RecordTypeA, "geo.zone-1.ext-dns-test-2.gcp.zalan.do."))
+	})
+	testRecords[zone][recordKey(endpoint.RecordTypeA, "wrr.zone-1.ext-dns-test-2.gcp.zalan.do.")] = &dns.ResourceRecordSet{
+		Name: "wrr.zone-1.ext-dns-test-2.gcp.zalan.do.",
+		Type: endpoint.RecordTypeA,
+		Ttl:  300,
+		RoutingPolicy: &dns.RRSetRoutingPolicy{
+			Wrr: &dns.RRSetRoutingPolicyWrrPolicy{
+				Items: []*dns.RRSetRoutingPolicyWrrPolicyWrrPolicyItem{
+					{Weight: 0.75, Rrdatas: []string{"1.2.3.4"}},
+					{Weight: 0.25, Rrdatas: []string{"5.6.7.8"}},
+				},
+			},
+		},
+	}
+	testRecords[zone][recordKey(endpoint.RecordTypeA, "geo.zone-1.ext-dns-test-2.gcp.zalan.do.")] = &dns.ResourceRecordSet{
+		Name: "geo.zone-1.ext-dns-test-2.gcp.zalan.do.",
+		Type: endpoint.RecordTypeA,
+		Ttl:  300,
+		RoutingPolicy: &dns.RRSetRoutingPolicy{
+			Geo: &dns.RRSetRoutingPolicyGeoPolicy{
+				Items: []*dns.RRSetRoutingPolicyGeoPolicyGeoPolicyItem{
+					{Location: "europe-west1", Rrdatas: []string{"9.9.9.9"}},
+				},
+			},
+		},
+	}
+
+	records, err := provider.Records(context.Background())
+	require.NoError(t, err)
+
+	validateEndpoints(t, records, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("wrr.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, 300, "1.2.3.4").
+			WithSetIdentifier("wrr-0").
+			WithProviderSpecific(providerSpecificRoutingPolicy, routingPolicyWRR).
+			WithProviderSpecific(providerSpecificWeight, "0.75"),
+		endpoint.NewEndpointWithTTL("wrr.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, 300, "5.6.7.8").
+			WithSetIdentifier("wrr-1").
+			WithProviderSpecific(providerSpecificRoutingPolicy, routingPolicyWRR).
+			WithProviderSpecific(providerSpecificWeight, "0.25"),
+		endpoint.NewEndpointWithTTL("geo.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, 300, "9.9.9.9").
+			WithSetIdentifier("europe-west1").
+			WithProviderSpecific(providerSpecificRoutingPolicy, routingPolicyGeo).
+			WithProviderSpecific(providerSpecificLocation, "europe-west1"),
+	})
+
+	// routing policy record sets are read-only
+	client := &countingChangesClient{}
+	provider.changesClient = client
+	require.NoError(t, provider.ApplyChanges(context.Background(), &plan.Changes{Delete: records}))
+	assert.Zero(t, client.created)
+	assert.Contains(t, testRecords[zone], recordKey(endpoint.RecordTypeA, "wrr.zone-1.ext-dns-test-2.gcp.zalan.do."))
+}
+
 func TestGoogleRecordsFilter(t *testing.T) {
 	originalEndpoints := []*endpoint.Endpoint{
 		endpoint.NewEndpointWithTTL("update-test.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.8.8"),
@@ -326,6 +735,38 @@ func TestGoogleRecordsFilter(t *testing.T) {
 	validateEndpoints(t, records, originalEndpoints)
 }
 
+func TestGoogleRecordExclusion(t *testing.T) {
+	originalEndpoints := []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("managed.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.8.8"),
+		endpoint.NewEndpointWithTTL("legacy-app.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.4.4"),
+		endpoint.NewEndpointWithTTL("legacy-db.zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeCNAME, defaultTTL, "db.example.com"),
+	}
+
+	provider := newGoogleProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}), provider.NewZoneIDFilter([]string{""}), false, originalEndpoints, nil, nil)
+	provider.recordExclusion = regexp.MustCompile("^legacy-.*")
+
+	records, err := provider.Records(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, records, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("managed.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.8.8"),
+	})
+
+	require.NoError(t, provider.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("legacy-new.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "1.2.3.4"),
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpointWithTTL("legacy-app.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.4.4"),
+		},
+	}))
+
+	// the excluded records are left untouched
+	provider.recordExclusion = nil
+	records, err = provider.Records(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, records, originalEndpoints)
+}
+
 func TestGoogleApplyChanges(t *testing.T) {
 	provider := newGoogleProvider(
 		t,
@@ -361,7 +802,7 @@ func TestGoogleApplyChanges(t *testing.T) {
 
 	currentRecords := []*endpoint.Endpoint{
 		endpoint.NewEndpoint("update-test.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "8.8.8.8"),
-		endpoint.NewEndpoint("update-test.zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "8.8.4.4"),
+		endpoint.NewEndpointWithTTL("update-test-ttl.zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, endpoint.TTL(10), "8.8.4.4"),
 		endpoint.NewEndpoint("update-test-cname.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeCNAME, "bar.elb.amazonaws.com"),
 		endpoint.NewEndpoint("filter-update-test.zone-3.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "4.2.2.2"),
 	}
@@ -403,6 +844,180 @@ func TestGoogleApplyChanges(t *testing.T) {
 	})
 }
 
+func TestGoogleApplyChangesCreateOverExisting(t *testing.T) {
+	provider := newGoogleProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}), provider.NewZoneIDFilter([]string{""}), false, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("existing.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.8.8"),
+	}, nil, nil)
+
+	creates := []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("existing.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, endpoint.TTL(60), "1.2.3.4"),
+		endpoint.NewEndpoint("new.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "4.3.2.1"),
+	}
+
+	zones, err := provider.Zones(context.Background())
+	require.NoError(t, err)
+	client := provider.resourceRecordSetsClient.(*mockResourceRecordSetsClient)
+	client.filters = nil
+	replaced, err := provider.replacedRecords(context.Background(), zones, provider.newFilteredRecords(creates), nil)
+	require.NoError(t, err)
+	require.Len(t, replaced, 1)
+	assert.Equal(t, "existing.zone-1.ext-dns-test-2.gcp.zalan.do.", replaced[0].Name)
+	assert.Equal(t, []string{"8.8.8.8"}, replaced[0].Rrdatas)
+	assert.ElementsMatch(t, []recordFilter{
+		{name: "existing.zone-1.ext-dns-test-2.gcp.zalan.do.", recordType: endpoint.RecordTypeA},
+		{name: "new.zone-1.ext-dns-test-2.gcp.zalan.do.", recordType: endpoint.RecordTypeA},
+	}, client.filters, "only the created record sets should be listed")
+
+	require.NoError(t, provider.ApplyChanges(context.Background(), &plan.Changes{Create: creates}))
+
+	records, err := provider.Records(context.Background())
+	require.NoError(t, err)
+
+	validateEndpoints(t, records, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("existing.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, endpoint.TTL(60), "1.2.3.4"),
+		endpoint.NewEndpointWithTTL("new.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "4.3.2.1"),
+	})
+}
+
+func TestGoogleApplyChangesCreateOverRoutingPolicy(t *testing.T) {
+	p := newGoogleProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}), provider.NewZoneIDFilter([]string{""}), false, []*endpoint.Endpoint{}, nil, nil)
+
+	zone := zoneKey(p.project, "zone-1-ext-dns-test-2-gcp-zalan-do")
+	if testRecords[zone] == nil {
+		testRecords[zone] = make(map[string]*dns.ResourceRecordSet)
+	}
+	key := recordKey(endpoint.RecordTypeA, "wrr.zone-1.ext-dns-test-2.gcp.zalan.do.")
+	t.Cleanup(func() {
+		delete(testRecords[zone], key)
+	})
+	wrr := &dns.ResourceRecordSet{
+		Name: "wrr.zone-1.ext-dns-test-2.gcp.zalan.do.",
+		Type: endpoint.RecordTypeA,
+		Ttl:  300,
+		RoutingPolicy: &dns.RRSetRoutingPolicy{
+			Wrr: &dns.RRSetRoutingPolicyWrrPolicy{
+				Items: []*dns.RRSetRoutingPolicyWrrPolicyWrrPolicyItem{
+					{Weight: 1, Rrdatas: []string{"1.2.3.4"}},
+				},
+			},
+		},
+	}
+	testRecords[zone][key] = wrr
+
+	creates := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("wrr.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "5.6.7.8"),
+	}
+	zones, err := p.Zones(context.Background())
+	require.NoError(t, err)
+	replaced, err := p.replacedRecords(context.Background(), zones, p.newFilteredRecords(creates), nil)
+	require.NoError(t, err)
+	assert.Empty(t, replaced, "routing policy record sets must not be replaced")
+
+	// the addition fails instead of overwriting the routing policy record set
+	require.Error(t, p.ApplyChanges(context.Background(), &plan.Changes{Create: creates}))
+	assert.Same(t, wrr, testRecords[zone][key])
+}
+
+// countingChangesClient counts the changes created through it.
+type countingChangesClient struct {
+	mockChangesClient
+
+	created int
+}
+
+func (c *countingChangesClient) Create(project string, managedZone string, change *dns.Change) changesCreateCallInterface {
+	c.created++
+	return c.mockChangesClient.Create(project, managedZone, change)
+}
+
+func TestGoogleApplyChangesUnchanged(t *testing.T) {
+	current := []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("a.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.8.8", "8.8.4.4"),
+		endpoint.NewEndpointWithTTL("b.zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeCNAME, endpoint.TTL(60), "foo.elb.amazonaws.com"),
+	}
+	p := newGoogleProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}), provider.NewZoneIDFilter([]string{""}), false, current, nil, nil)
+	client := &countingChangesClient{}
+	p.changesClient = client
+
+	records, err := p.Records(context.Background())
+	require.NoError(t, err)
+
+	// re-creating and updating the records to their current state are no-ops
+	reordered := endpoint.NewEndpointWithTTL("a.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.4.4", "8.8.8.8")
+	require.NoError(t, p.ApplyChanges(context.Background(), &plan.Changes{Create: records}))
+	require.NoError(t, p.ApplyChanges(context.Background(), &plan.Changes{
+		Update: []*plan.Update{{Old: current[0], New: reordered}},
+	}))
+	assert.Zero(t, client.created)
+
+	// only the changed record set is rewritten
+	change := dropUnchangedRecords(&dns.Change{
+		Additions: p.newFilteredRecords([]*endpoint.Endpoint{
+			reordered,
+			endpoint.NewEndpointWithTTL("b.zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeCNAME, endpoint.TTL(300), "foo.elb.amazonaws.com"),
+		}),
+		Deletions: p.newFilteredRecords(current),
+	})
+	validateChange(t, change, &dns.Change{
+		Additions: []*dns.ResourceRecordSet{
+			{Name: "b.zone-2.ext-dns-test-2.gcp.zalan.do.", Rrdatas: []string{"foo.elb.amazonaws.com."}, Ttl: 300, Type: endpoint.RecordTypeCNAME},
+		},
+		Deletions: []*dns.ResourceRecordSet{
+			{Name: "b.zone-2.ext-dns-test-2.gcp.zalan.do.", Rrdatas: []string{"foo.elb.amazonaws.com."}, Ttl: 60, Type: endpoint.RecordTypeCNAME},
+		},
+	})
+}
+
+func TestGoogleApplyChangesReadOnlyZone(t *testing.T) {
+	existing := []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("existing.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.8.8"),
+		endpoint.NewEndpointWithTTL("existing.zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.4.4"),
+	}
+	provider := newGoogleProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}), provider.NewZoneIDFilter([]string{""}), false, existing, nil, nil)
+	provider.readOnlyZonePrefixes = []string{"zone-2-"}
+
+	records, err := provider.Records(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, records, existing)
+
+	require.NoError(t, provider.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("new.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "1.2.3.4"),
+			endpoint.NewEndpoint("new.zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "4.3.2.1"),
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpointWithTTL("existing.zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.4.4"),
+		},
+	}))
+
+	records, err = provider.Records(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, records, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("existing.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.8.8"),
+		endpoint.NewEndpointWithTTL("existing.zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.4.4"),
+		endpoint.NewEndpointWithTTL("new.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "1.2.3.4"),
+	})
+}
+
+func TestGoogleApplyChangesUnsupportedType(t *testing.T) {
+	provider := newGoogleProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}), provider.NewZoneIDFilter([]string{""}), false, []*endpoint.Endpoint{}, nil, nil)
+
+	require.NotPanics(t, func() {
+		require.NoError(t, provider.ApplyChanges(context.Background(), &plan.Changes{
+			Create: []*endpoint.Endpoint{
+				endpoint.NewEndpoint("new.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "1.2.3.4"),
+				endpoint.NewEndpoint("new.zone-1.ext-dns-test-2.gcp.zalan.do", "SPF", "v=spf1 -all"),
+			},
+		}))
+	})
+
+	records, err := provider.Records(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, records, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("new.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "1.2.3.4"),
+	})
+}
+
 func TestGoogleApplyChangesDryRun(t *testing.T) {
 	originalEndpoints := []*endpoint.Endpoint{
 		endpoint.NewEndpointWithTTL("update-test.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.8.8"),
@@ -538,6 +1153,68 @@ func TestSeparateChanges(t *testing.T) {
 	})
 }
 
+func TestTallyChanges(t *testing.T) {
+	change := &dns.Change{
+		Additions: []*dns.ResourceRecordSet{
+			{Name: "qux.foo.example.org.", Ttl: 1},
+			{Name: "quux.foo.example.org.", Ttl: 1},
+			{Name: "qux.bar.example.org.", Ttl: 2},
+		},
+		Deletions: []*dns.ResourceRecordSet{
+			{Name: "wambo.foo.example.org.", Ttl: 10},
+			{Name: "wambo.bar.example.org.", Ttl: 20},
+			{Name: "wimbo.bar.example.org.", Ttl: 20},
+			{Name: "wumbo.bar.example.org.", Ttl: 20},
+		},
+	}
+
+	zones := map[string]*dns.ManagedZone{
+		"foo-example-org": {
+			Name:    "foo-example-org",
+			DnsName: "foo.example.org.",
+		},
+		"bar-example-org": {
+			Name:    "bar-example-org",
+			DnsName: "bar.example.org.",
+		},
+	}
+
+	tallies := tallyChanges(separateChange(zones, change))
+
+	assert.Equal(t, map[string]changeTally{
+		"foo-example-org": {Additions: 2, Deletions: 1},
+		"bar-example-org": {Additions: 1, Deletions: 3},
+	}, tallies)
+}
+
+func TestGoogleApplyChangesBatchInterval(t *testing.T) {
+	p := newGoogleProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}), provider.NewZoneIDFilter([]string{""}), false, []*endpoint.Endpoint{}, nil, nil)
+	p.batchChangeSize = 1
+	p.batchChangeInterval = 3 * time.Second
+	var sleeps []time.Duration
+	p.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "8.8.8.8"),
+			endpoint.NewEndpoint("b.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "8.8.8.8"),
+			endpoint.NewEndpoint("c.zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "8.8.4.4"),
+		},
+	}
+	require.NoError(t, p.ApplyChanges(context.Background(), changes))
+
+	// three batches are paced by two pauses, none after the last batch
+	assert.Equal(t, []time.Duration{3 * time.Second, 3 * time.Second}, sleeps)
+
+	records, err := p.Records(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, records, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("a.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.8.8"),
+		endpoint.NewEndpointWithTTL("b.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.8.8"),
+		endpoint.NewEndpointWithTTL("c.zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, defaultTTL, "8.8.4.4"),
+	})
+}
+
 func TestGoogleBatchChangeSet(t *testing.T) {
 	cs := &dns.Change{}
 
@@ -634,6 +1311,29 @@ func TestSoftErrListRecordsConflict(t *testing.T) {
 	require.Empty(t, records)
 }
 
+func TestGoogleRequestTimeout(t *testing.T) {
+	p := &GoogleProvider{
+		project:            "zalando-external-dns-test",
+		requestTimeout:     10 * time.Millisecond,
+		managedZonesClient: &blockingManagedZonesClient{},
+	}
+
+	done := make(chan struct{})
+	var err error
+	go func() {
+		defer close(done)
+		_, err = p.Zones(context.Background())
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request timeout did not fire")
+	}
+	require.ErrorIs(t, err, provider.SoftError)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
 func sortChangesByName(cs *dns.Change) {
 	sort.SliceStable(cs.Additions, func(i, j int) bool {
 		return cs.Additions[i].Name < cs.Additions[j].Name
@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// MergeEndpointsByNameType merges endpoints with the same DNS name and record type into a single
+// endpoint holding all of their targets, for providers which return one record per target.
+//
+// The merged endpoints keep the order in which their name and type first appeared. If the merged
+// records have different TTLs, the lowest configured one is used. Provider specific properties, labels and the
+// set identifier are kept; a property set on several endpoints keeps its first value.
+// The endpoints are returned unchanged if none of them share a name and type.
+func MergeEndpointsByNameType(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	type nameType struct {
+		dnsName    string
+		recordType string
+	}
+	endpointsByNameType := map[nameType][]*endpoint.Endpoint{}
+	var keys []nameType
+
+	for _, ep := range endpoints {
+		key := nameType{ep.DNSName, ep.RecordType}
+		if _, ok := endpointsByNameType[key]; !ok {
+			keys = append(keys, key)
+		}
+		endpointsByNameType[key] = append(endpointsByNameType[key], ep)
+	}
+
+	// If there were no merges, return endpoints.
+	if len(keys) == len(endpoints) {
+		return endpoints
+	}
+
+	merged := make([]*endpoint.Endpoint, 0, len(keys))
+	for _, key := range keys {
+		group := endpointsByNameType[key]
+		if len(group) == 1 {
+			merged = append(merged, group[0])
+			continue
+		}
+
+		var ttl endpoint.TTL
+		inconsistentTTL := false
+		var targets []string
+		for _, ep := range group {
+			targets = append(targets, ep.Targets...)
+			// an unset TTL does not override a configured one
+			if !ep.RecordTTL.IsConfigured() {
+				continue
+			}
+			if !ttl.IsConfigured() {
+				ttl = ep.RecordTTL
+			} else if ep.RecordTTL != ttl {
+				inconsistentTTL = true
+				ttl = min(ttl, ep.RecordTTL)
+			}
+		}
+		if inconsistentTTL {
+			log.Warnf("Records of %s %s have different TTLs, using the lowest TTL %d", key.recordType, key.dnsName, ttl)
+		}
+
+		ep := endpoint.NewEndpointWithTTL(key.dnsName, key.recordType, ttl, targets...)
+		ep.SetIdentifier = group[0].SetIdentifier
+		for _, e := range group {
+			for name, value := range e.Labels {
+				if _, ok := ep.Labels[name]; !ok {
+					ep.Labels[name] = value
+				}
+			}
+			for _, property := range e.ProviderSpecific {
+				if _, ok := ep.GetProviderSpecificProperty(property.Name); !ok {
+					ep.WithProviderSpecific(property.Name, property.Value)
+				}
+			}
+		}
+		merged = append(merged, ep)
+	}
+
+	return merged
+}
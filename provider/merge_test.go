@@ -0,0 +1,115 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/internal/testutils"
+)
+
+func TestMergeEndpointsByNameType(t *testing.T) {
+	endpoints := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("bar.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "5.6.7.8"),
+		endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeCNAME, "somewhere.out.there.com"),
+		endpoint.NewEndpoint("bar.example.com", endpoint.RecordTypeMX, "10 bar.mx1.com"),
+		endpoint.NewEndpoint("bar.example.com", endpoint.RecordTypeMX, "10 bar.mx2.com"),
+	}
+
+	merged := MergeEndpointsByNameType(endpoints)
+
+	assert.Equal(t, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.2.3.4", "5.6.7.8"),
+		endpoint.NewEndpoint("bar.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeCNAME, "somewhere.out.there.com"),
+		endpoint.NewEndpoint("bar.example.com", endpoint.RecordTypeMX, "10 bar.mx1.com", "10 bar.mx2.com"),
+	}, merged)
+}
+
+func TestMergeEndpointsByNameTypeNoMerge(t *testing.T) {
+	endpoints := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeAAAA, "2001:db8::1"),
+	}
+
+	assert.Equal(t, endpoints, MergeEndpointsByNameType(endpoints))
+}
+
+func TestMergeEndpointsByNameTypeDifferentTTLs(t *testing.T) {
+	hook := testutils.LogsUnderTestWithLogLevel(log.WarnLevel, t)
+
+	merged := MergeEndpointsByNameType([]*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("foo.example.com", endpoint.RecordTypeA, 600, "1.2.3.4"),
+		endpoint.NewEndpointWithTTL("foo.example.com", endpoint.RecordTypeA, 300, "5.6.7.8"),
+		endpoint.NewEndpointWithTTL("foo.example.com", endpoint.RecordTypeA, 900, "9.9.9.9"),
+		endpoint.NewEndpointWithTTL("bar.example.com", endpoint.RecordTypeA, 60, "1.2.3.4"),
+		endpoint.NewEndpointWithTTL("bar.example.com", endpoint.RecordTypeA, 60, "5.6.7.8"),
+	})
+
+	require.Len(t, merged, 2)
+	assert.Equal(t, endpoint.TTL(300), merged[0].RecordTTL)
+	assert.Equal(t, endpoint.Targets{"1.2.3.4", "5.6.7.8", "9.9.9.9"}, merged[0].Targets)
+	assert.Equal(t, endpoint.TTL(60), merged[1].RecordTTL)
+	testutils.TestHelperLogContains("Records of A foo.example.com have different TTLs, using the lowest TTL 300", hook, t)
+	testutils.TestHelperLogNotContains("Records of A bar.example.com", hook, t)
+}
+
+func TestMergeEndpointsByNameTypeUnsetTTL(t *testing.T) {
+	hook := testutils.LogsUnderTestWithLogLevel(log.WarnLevel, t)
+
+	merged := MergeEndpointsByNameType([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpointWithTTL("foo.example.com", endpoint.RecordTypeA, 600, "5.6.7.8"),
+		endpoint.NewEndpoint("bar.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("bar.example.com", endpoint.RecordTypeA, "5.6.7.8"),
+	})
+
+	require.Len(t, merged, 2)
+	assert.Equal(t, endpoint.TTL(600), merged[0].RecordTTL)
+	assert.False(t, merged[1].RecordTTL.IsConfigured())
+	testutils.TestHelperLogNotContains("different TTLs", hook, t)
+}
+
+func TestMergeEndpointsByNameTypeProviderSpecific(t *testing.T) {
+	first := endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.2.3.4").
+		WithSetIdentifier("primary").
+		WithProviderSpecific("alias", "false").
+		WithLabel(endpoint.OwnerLabelKey, "owner")
+	second := endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "5.6.7.8").
+		WithProviderSpecific("alias", "true").
+		WithProviderSpecific("weight", "10")
+
+	merged := MergeEndpointsByNameType([]*endpoint.Endpoint{first, second})
+
+	require.Len(t, merged, 1)
+	assert.Equal(t, "primary", merged[0].SetIdentifier)
+	assert.Equal(t, endpoint.ProviderSpecific{
+		{Name: "alias", Value: "false"},
+		{Name: "weight", Value: "10"},
+	}, merged[0].ProviderSpecific)
+	assert.Equal(t, "owner", merged[0].Labels[endpoint.OwnerLabelKey])
+	// the merged endpoints are left untouched
+	assert.Equal(t, endpoint.Targets{"1.2.3.4"}, first.Targets)
+	assert.Len(t, second.ProviderSpecific, 2)
+}
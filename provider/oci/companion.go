@@ -0,0 +1,115 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/dns"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+// companionLabelKey marks an ownership TXT companion record with the type of the record it accompanies.
+const companionLabelKey = "companion"
+
+// hasCompanion reports whether records of the given type get an ownership TXT companion. CNAME
+// records are left out, as no other record may exist at the name of a CNAME record.
+func hasCompanion(recordType string) bool {
+	return recordType == endpoint.RecordTypeA || recordType == endpoint.RecordTypeAAAA
+}
+
+// companionRdata returns the rdata of the TXT companion of the endpoint, encoding its owner.
+func companionRdata(ep *endpoint.Endpoint) string {
+	labels := endpoint.Labels{companionLabelKey: strings.ToLower(ep.RecordType)}
+	if owner := ep.Labels[endpoint.OwnerLabelKey]; owner != "" {
+		labels[endpoint.OwnerLabelKey] = owner
+	}
+	return provider.EncodeOwnershipTXT(labels)
+}
+
+// isCompanionRdata reports whether the TXT rdata is that of an ownership TXT companion.
+func isCompanionRdata(rdata string) bool {
+	labels, err := provider.DecodeOwnershipTXT(rdata)
+	return err == nil && labels[companionLabelKey] != ""
+}
+
+// newCompanionOperation returns the operation for the TXT companion of the endpoint.
+func (p *OCIProvider) newCompanionOperation(ep *endpoint.Endpoint, opType dns.RecordOperationOperationEnum) dns.RecordOperation {
+	domain := ep.DNSName
+	rdata := companionRdata(ep)
+	rtype := endpoint.RecordTypeTXT
+	ttl := int(provider.TTLOrDefault(ep, p.cfg.DefaultTTL))
+	return dns.RecordOperation{
+		Domain:    &domain,
+		Rdata:     &rdata,
+		Ttl:       &ttl,
+		Rtype:     &rtype,
+		Operation: opType,
+	}
+}
+
+// newCompanionOperations returns the operations creating, replacing and removing the ownership
+// TXT companions of the changed endpoints, if enabled. Removals precede the additions.
+func (p *OCIProvider) newCompanionOperations(changes *plan.Changes) []dns.RecordOperation {
+	if !p.cfg.OwnershipTXT {
+		return nil
+	}
+	accompanied := func(ep *endpoint.Endpoint) bool {
+		return ep != nil && hasCompanion(ep.RecordType) && p.domainFilter.Match(ep.DNSName)
+	}
+	// A companion whose TTL alone changed is added again, which updates the TTL of the existing one.
+	sameRdata := func(a, b *endpoint.Endpoint) bool {
+		return a.DNSName == b.DNSName && companionRdata(a) == companionRdata(b)
+	}
+	sameTTL := func(a, b *endpoint.Endpoint) bool {
+		return provider.TTLOrDefault(a, p.cfg.DefaultTTL) == provider.TTLOrDefault(b, p.cfg.DefaultTTL)
+	}
+
+	var removes, adds []dns.RecordOperation
+	for _, ep := range changes.Create {
+		if accompanied(ep) {
+			adds = append(adds, p.newCompanionOperation(ep, dns.RecordOperationOperationAdd))
+		}
+	}
+	for _, update := range changes.Update {
+		if update == nil {
+			continue
+		}
+		oldAccompanied, newAccompanied := accompanied(update.Old), accompanied(update.New)
+		if oldAccompanied && newAccompanied && sameRdata(update.Old, update.New) {
+			if !sameTTL(update.Old, update.New) {
+				adds = append(adds, p.newCompanionOperation(update.New, dns.RecordOperationOperationAdd))
+			}
+			continue
+		}
+		if oldAccompanied {
+			removes = append(removes, p.newCompanionOperation(update.Old, dns.RecordOperationOperationRemove))
+		}
+		if newAccompanied {
+			adds = append(adds, p.newCompanionOperation(update.New, dns.RecordOperationOperationAdd))
+		}
+	}
+	for _, ep := range changes.Delete {
+		if accompanied(ep) {
+			removes = append(removes, p.newCompanionOperation(ep, dns.RecordOperationOperationRemove))
+		}
+	}
+	return append(removes, adds...)
+}
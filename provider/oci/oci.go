@@ -20,22 +20,49 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/goccy/go-yaml"
 	"github.com/oracle/oci-go-sdk/v65/common"
 	"github.com/oracle/oci-go-sdk/v65/common/auth"
 	"github.com/oracle/oci-go-sdk/v65/dns"
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 
 	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/metrics"
 	"sigs.k8s.io/external-dns/plan"
 	"sigs.k8s.io/external-dns/provider"
 )
 
-const defaultTTL = 300
+var (
+	recordChangesTotal = metrics.NewCounterVecWithOpts(
+		prometheus.CounterOpts{
+			Subsystem: "oci_provider",
+			Name:      "record_changes_total",
+			Help:      "Number of records created, updated and deleted in OCI DNS zones (vector).",
+		},
+		[]string{"zone", "operation"},
+	)
+)
+
+func init() {
+	metrics.RegisterMetric.MustRegister(recordChangesTotal)
+}
+
+// changeSummary holds the number of records created, updated and deleted in a zone.
+type changeSummary struct {
+	Created int
+	Updated int
+	Deleted int
+}
 
 // OCIAuthConfig holds connection parameters for the OCI API.
 type OCIAuthConfig struct {
@@ -54,8 +81,30 @@ type OCIConfig struct {
 	Auth              OCIAuthConfig `yaml:"auth"`
 	CompartmentID     string        `yaml:"compartment"`
 	ZoneCacheDuration time.Duration
+	// DefaultTTL is used for records without a TTL, defaulting to provider.DefaultTTL
+	DefaultTTL endpoint.TTL `yaml:"defaultTTL"`
+	// ViewID restricts private zones to the given view, e.g. the one attached to a VCN's resolver.
+	// It disambiguates private zones that share a name across views.
+	ViewID string `yaml:"viewID"`
+	// ZoneNameRegex restricts the managed zones to those whose name matches it.
+	ZoneNameRegex string `yaml:"zoneNameRegex"`
+	// ZoneNameExclusionRegex excludes the zones whose name matches it.
+	ZoneNameExclusionRegex string `yaml:"zoneNameExclusionRegex"`
+	// OwnershipTXT creates a TXT companion record encoding the owner at the name of each A and
+	// AAAA record, removed along with the record. Companion records are not returned by Records.
+	OwnershipTXT bool `yaml:"ownershipTXT"`
+	// ApplyConcurrency bounds the number of zones patched at once, defaulting to one zone at a time.
+	// The operations of a zone are always sent in a single request.
+	ApplyConcurrency int `yaml:"applyConcurrency"`
+	// ManagedRecordTypes restricts the record types read and written, defaulting to all supported types.
+	// When few types are managed, records are filtered by type server-side, one request per type.
+	ManagedRecordTypes []string `yaml:"managedRecordTypes"`
 }
 
+// maxRecordTypeFilters is the largest number of managed record types for which the records of
+// a zone are requested once per type rather than all at once.
+const maxRecordTypeFilters = 3
+
 // OCIProvider is an implementation of Provider for Oracle Cloud Infrastructure
 // (OCI) DNS.
 type OCIProvider struct {
@@ -68,6 +117,10 @@ type OCIProvider struct {
 	zoneScope    string
 	zoneCache    *zoneCache
 	dryRun       bool
+
+	// zoneNameRegex and zoneNameExclusionRegex select zones by name, if set
+	zoneNameRegex          *regexp.Regexp
+	zoneNameExclusionRegex *regexp.Regexp
 }
 
 // ociDNSClient is the subset of the OCI DNS API required by the OCI Provider.
@@ -94,12 +147,49 @@ func LoadOCIConfig(path string) (*OCIConfig, error) {
 
 // NewOCIProvider initializes a new OCI DNS based Provider.
 func NewOCIProvider(cfg OCIConfig, domainFilter *endpoint.DomainFilter, zoneIDFilter provider.ZoneIDFilter, zoneScope string, dryRun bool) (*OCIProvider, error) {
-	var client ociDNSClient
-	var err error
-	var configProvider common.ConfigurationProvider
 	if cfg.Auth.UseInstancePrincipal && cfg.Auth.UseWorkloadIdentity {
 		return nil, errors.New("only one of 'useInstancePrincipal' and 'useWorkloadIdentity' may be enabled for Oracle authentication")
 	}
+	zoneNameRegex, err := compileOptionalRegex(cfg.ZoneNameRegex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zone name regex: %w", err)
+	}
+	zoneNameExclusionRegex, err := compileOptionalRegex(cfg.ZoneNameExclusionRegex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zone name exclusion regex: %w", err)
+	}
+
+	client, err := newDNSClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// Instance principal and workload identity credentials are short-lived, rebuild the
+	// client from fresh credentials when they expire.
+	if cfg.Auth.UseInstancePrincipal || cfg.Auth.UseWorkloadIdentity {
+		client = newRefreshingDNSClient(client, func() (ociDNSClient, error) {
+			return newDNSClient(cfg)
+		})
+	}
+
+	return &OCIProvider{
+		client:       client,
+		cfg:          cfg,
+		domainFilter: domainFilter,
+		zoneIDFilter: zoneIDFilter,
+		zoneScope:    zoneScope,
+		zoneCache: &zoneCache{
+			duration: cfg.ZoneCacheDuration,
+		},
+		dryRun:                 dryRun,
+		zoneNameRegex:          zoneNameRegex,
+		zoneNameExclusionRegex: zoneNameExclusionRegex,
+	}, nil
+}
+
+// newDNSClient builds an OCI DNS API client authenticating as configured.
+func newDNSClient(cfg OCIConfig) (ociDNSClient, error) {
+	var configProvider common.ConfigurationProvider
+	var err error
 	if cfg.Auth.UseWorkloadIdentity {
 		// OCI SDK requires specific, dynamic environment variables for workload identity.
 		if err := os.Setenv(auth.ResourcePrincipalVersionEnvVar, auth.ResourcePrincipalVersion2_2); err != nil {
@@ -128,22 +218,18 @@ func NewOCIProvider(cfg OCIConfig, domainFilter *endpoint.DomainFilter, zoneIDFi
 		)
 	}
 
-	client, err = dns.NewDnsClientWithConfigurationProvider(configProvider)
+	client, err := dns.NewDnsClientWithConfigurationProvider(configProvider)
 	if err != nil {
 		return nil, fmt.Errorf("initializing OCI DNS API client: %w", err)
 	}
+	return client, nil
+}
 
-	return &OCIProvider{
-		client:       client,
-		cfg:          cfg,
-		domainFilter: domainFilter,
-		zoneIDFilter: zoneIDFilter,
-		zoneScope:    zoneScope,
-		zoneCache: &zoneCache{
-			duration: cfg.ZoneCacheDuration,
-		},
-		dryRun: dryRun,
-	}, nil
+func compileOptionalRegex(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	return regexp.Compile(expr)
 }
 
 func (p *OCIProvider) zones(ctx context.Context) (map[string]dns.ZoneSummary, error) {
@@ -170,54 +256,29 @@ func (p *OCIProvider) zones(ctx context.Context) (map[string]dns.ZoneSummary, er
 	return zones, nil
 }
 
-// Merge Endpoints with the same Name and Type into a single endpoint with multiple Targets.
-func mergeEndpointsMultiTargets(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
-	endpointsByNameType := map[string][]*endpoint.Endpoint{}
-
-	for _, ep := range endpoints {
-		key := fmt.Sprintf("%s-%s", ep.DNSName, ep.RecordType)
-		endpointsByNameType[key] = append(endpointsByNameType[key], ep)
-	}
-
-	// If there were no merges, return endpoints.
-	if len(endpointsByNameType) == len(endpoints) {
-		return endpoints
-	}
-
-	// Otherwise, create a new list of endpoints with the consolidated targets.
-	var mergedEndpoints []*endpoint.Endpoint
-	for _, ep := range endpointsByNameType {
-		dnsName := ep[0].DNSName
-		recordType := ep[0].RecordType
-		recordTTL := ep[0].RecordTTL
-
-		targets := make([]string, len(ep))
-		for i, e := range ep {
-			targets[i] = e.Targets[0]
-		}
-
-		e := endpoint.NewEndpointWithTTL(dnsName, recordType, recordTTL, targets...)
-		mergedEndpoints = append(mergedEndpoints, e)
-	}
-
-	return mergedEndpoints
-}
-
 func (p *OCIProvider) addPaginatedZones(ctx context.Context, zones map[string]dns.ZoneSummary, scope dns.GetZoneScopeEnum) error {
 	var page *string
 	// Loop until we have listed all zones.
 	for {
-		resp, err := p.client.ListZones(ctx, dns.ListZonesRequest{
+		request := dns.ListZonesRequest{
 			CompartmentId: &p.cfg.CompartmentID,
 			ZoneType:      dns.ListZonesZoneTypePrimary,
 			Scope:         dns.ListZonesScopeEnum(scope),
 			Page:          page,
-		})
+		}
+		if p.restrictView(scope) {
+			request.ViewId = &p.cfg.ViewID
+		}
+		resp, err := p.client.ListZones(ctx, request)
 		if err != nil {
-			return provider.NewSoftError(fmt.Errorf("listing zones in %s: %w", p.cfg.CompartmentID, err))
+			return classifyError(fmt.Errorf("listing zones in %s: %w", p.cfg.CompartmentID, err))
 		}
 		for _, zone := range resp.Items {
-			if p.domainFilter.Match(*zone.Name) && p.zoneIDFilter.Match(*zone.Id) {
+			if p.restrictView(scope) && (zone.ViewId == nil || *zone.ViewId != p.cfg.ViewID) {
+				log.Debugf("Filtered %q (%q) in another view", *zone.Name, *zone.Id)
+				continue
+			}
+			if p.domainFilter.Match(*zone.Name) && p.matchZoneIDFilter(zone) && p.matchZoneNameRegex(*zone.Name) {
 				zones[*zone.Id] = zone
 				log.Debugf("Matched %q (%q)", *zone.Name, *zone.Id)
 			} else {
@@ -231,74 +292,189 @@ func (p *OCIProvider) addPaginatedZones(ctx context.Context, zones map[string]dn
 	return nil
 }
 
+// matchZoneNameRegex reports whether the zone name matches the zone name regex and not the
+// zone name exclusion regex.
+func (p *OCIProvider) matchZoneNameRegex(name string) bool {
+	name = strings.TrimSuffix(name, ".")
+	if p.zoneNameExclusionRegex != nil && p.zoneNameExclusionRegex.MatchString(name) {
+		return false
+	}
+	return p.zoneNameRegex == nil || p.zoneNameRegex.MatchString(name)
+}
+
+// restrictView reports whether zones listed in the given scope must belong to the configured view.
+func (p *OCIProvider) restrictView(scope dns.GetZoneScopeEnum) bool {
+	return p.cfg.ViewID != "" && scope == dns.GetZoneScopePrivate
+}
+
+// matchZoneIDFilter reports whether the zone ID filter matches the zone. Filter entries
+// that are OCIDs are matched against the zone OCID, all others against the zone name.
+func (p *OCIProvider) matchZoneIDFilter(zone dns.ZoneSummary) bool {
+	if !p.zoneIDFilter.IsConfigured() {
+		return true
+	}
+	for _, id := range p.zoneIDFilter.ZoneIDs {
+		if strings.HasPrefix(id, "ocid1.") {
+			if provider.NewZoneIDFilter([]string{id}).Match(*zone.Id) {
+				return true
+			}
+		} else if strings.EqualFold(strings.TrimSuffix(id, "."), strings.TrimSuffix(*zone.Name, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *OCIProvider) newFilteredRecordOperations(endpoints []*endpoint.Endpoint, opType dns.RecordOperationOperationEnum) []dns.RecordOperation {
 	var ops []dns.RecordOperation
 	for _, ep := range endpoints {
 		if ep == nil {
 			continue
 		}
-		if p.domainFilter.Match(ep.DNSName) {
+		if p.domainFilter.Match(ep.DNSName) && p.isManagedRecordType(ep.RecordType) {
 			for _, t := range ep.Targets {
-				singleTargetEp := &endpoint.Endpoint{
-					DNSName:          ep.DNSName,
-					Targets:          []string{t},
-					RecordType:       ep.RecordType,
-					RecordTTL:        ep.RecordTTL,
-					Labels:           ep.Labels,
-					ProviderSpecific: ep.ProviderSpecific,
-				}
-				ops = append(ops, newRecordOperation(singleTargetEp, opType))
+				ops = append(ops, p.newTargetRecordOperation(ep, t, opType))
 			}
 		}
 	}
 	return ops
 }
 
+// newUpdateRecordOperations returns the operations for the given updates. Targets present
+// before and after an update are left alone unless their TTL changed, in which case they are
+// added again with the new TTL: OCI updates the TTL of record data which already exists, so
+// they are not removed first. All removals precede the additions, so that a replaced record
+// is removed before it is added again.
+func (p *OCIProvider) newUpdateRecordOperations(updates []*plan.Update) []dns.RecordOperation {
+	var removes, adds []dns.RecordOperation
+	for _, update := range updates {
+		if update == nil || update.Old == nil || update.New == nil || !p.domainFilter.Match(update.New.DNSName) {
+			continue
+		}
+		if !p.isManagedRecordType(update.Old.RecordType) || !p.isManagedRecordType(update.New.RecordType) {
+			continue
+		}
+		replace := update.Old.DNSName != update.New.DNSName || update.Old.RecordType != update.New.RecordType
+		ttlChanged := provider.TTLOrDefault(update.Old, p.cfg.DefaultTTL) != provider.TTLOrDefault(update.New, p.cfg.DefaultTTL)
+		for _, t := range update.Old.Targets {
+			if replace || !slices.Contains(update.New.Targets, t) {
+				removes = append(removes, p.newTargetRecordOperation(update.Old, t, dns.RecordOperationOperationRemove))
+			}
+		}
+		for _, t := range update.New.Targets {
+			if replace || ttlChanged || !slices.Contains(update.Old.Targets, t) {
+				adds = append(adds, p.newTargetRecordOperation(update.New, t, dns.RecordOperationOperationAdd))
+			}
+		}
+	}
+	return append(removes, adds...)
+}
+
+// newTargetRecordOperation returns the operation for a single target of the endpoint.
+func (p *OCIProvider) newTargetRecordOperation(ep *endpoint.Endpoint, target string, opType dns.RecordOperationOperationEnum) dns.RecordOperation {
+	singleTargetEp := &endpoint.Endpoint{
+		DNSName:          ep.DNSName,
+		Targets:          []string{target},
+		RecordType:       ep.RecordType,
+		RecordTTL:        ep.RecordTTL,
+		Labels:           ep.Labels,
+		ProviderSpecific: ep.ProviderSpecific,
+	}
+	return newRecordOperation(singleTargetEp, opType, p.cfg.DefaultTTL)
+}
+
 // Records returns the list of records in a given hosted zone.
 func (p *OCIProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	zones, err := p.zones(ctx)
 	if err != nil {
-		return nil, provider.NewSoftError(fmt.Errorf("getting zones: %w", err))
+		return nil, fmt.Errorf("getting zones: %w", err)
 	}
 
+	rtypeFilters := p.recordTypeFilters()
 	var endpoints []*endpoint.Endpoint
 	for _, zone := range zones {
-		var page *string
-		for {
-			resp, err := p.client.GetZoneRecords(ctx, dns.GetZoneRecordsRequest{
-				ZoneNameOrId:  zone.Id,
-				Page:          page,
-				CompartmentId: &p.cfg.CompartmentID,
-			})
+		for _, rtype := range rtypeFilters {
+			records, err := p.zoneRecords(ctx, zone, rtype)
 			if err != nil {
-				return nil, provider.NewSoftError(fmt.Errorf("getting records for zone %q: %w", *zone.Id, err))
+				return nil, err
 			}
 
-			for _, record := range resp.Items {
-				if !provider.SupportedRecordType(*record.Rtype) {
+			for _, record := range records {
+				if !p.isManagedRecordType(*record.Rtype) {
+					continue
+				}
+				if *record.Rtype == endpoint.RecordTypeTXT && isCompanionRdata(*record.Rdata) {
 					continue
 				}
+				ttl := provider.TTLOrDefault(&endpoint.Endpoint{}, p.cfg.DefaultTTL)
+				if record.Ttl != nil {
+					ttl = endpoint.TTL(*record.Ttl)
+				}
 				endpoints = append(endpoints,
 					endpoint.NewEndpointWithTTL(
-						*record.Domain,
+						wildcardUnescape(*record.Domain),
 						*record.Rtype,
-						endpoint.TTL(*record.Ttl),
+						ttl,
 						*record.Rdata,
 					),
 				)
 			}
-
-			if page = resp.OpcNextPage; resp.OpcNextPage == nil {
-				break
-			}
 		}
 	}
 
-	endpoints = mergeEndpointsMultiTargets(endpoints)
+	endpoints = provider.MergeEndpointsByNameType(endpoints)
 
 	return endpoints, nil
 }
 
+// zoneRecords returns all pages of the records of the zone, restricted to the record type if it is
+// not nil.
+func (p *OCIProvider) zoneRecords(ctx context.Context, zone dns.ZoneSummary, rtype *string) ([]dns.Record, error) {
+	var records []dns.Record
+	var page *string
+	for {
+		resp, err := p.client.GetZoneRecords(ctx, dns.GetZoneRecordsRequest{
+			ZoneNameOrId:  zone.Id,
+			Page:          page,
+			CompartmentId: &p.cfg.CompartmentID,
+			Scope:         dns.GetZoneRecordsScopeEnum(zone.Scope),
+			ViewId:        zone.ViewId,
+			Rtype:         rtype,
+		})
+		if err != nil {
+			return nil, classifyError(fmt.Errorf("getting records for zone %q: %w", *zone.Id, err))
+		}
+		records = append(records, resp.Items...)
+
+		if page = resp.OpcNextPage; resp.OpcNextPage == nil {
+			return records, nil
+		}
+	}
+}
+
+// isManagedRecordType reports whether records of the type are managed by the provider.
+func (p *OCIProvider) isManagedRecordType(recordType string) bool {
+	if !slices.Contains(p.SupportedRecordTypes(), recordType) {
+		return false
+	}
+	return len(p.cfg.ManagedRecordTypes) == 0 || slices.Contains(p.cfg.ManagedRecordTypes, recordType)
+}
+
+// recordTypeFilters returns the record types to request the records of each zone for. A nil type
+// requests the records of all types, used unless only a few record types are managed.
+func (p *OCIProvider) recordTypeFilters() []*string {
+	var managed []*string
+	for _, recordType := range p.SupportedRecordTypes() {
+		if len(p.cfg.ManagedRecordTypes) > 0 && slices.Contains(p.cfg.ManagedRecordTypes, recordType) {
+			managed = append(managed, common.String(recordType))
+		}
+	}
+	if len(managed) == 0 || len(managed) > maxRecordTypeFilters {
+		return []*string{nil}
+	}
+	return managed
+}
+
 // ApplyChanges applies a given set of changes to a given zone.
 func (p *OCIProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 	log.Debugf("Processing changes: %+v", changes)
@@ -306,11 +482,12 @@ func (p *OCIProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) e
 	var ops []dns.RecordOperation
 	ops = append(ops, p.newFilteredRecordOperations(changes.Create, dns.RecordOperationOperationAdd)...)
 
-	ops = append(ops, p.newFilteredRecordOperations(changes.UpdateNew(), dns.RecordOperationOperationAdd)...)
-	ops = append(ops, p.newFilteredRecordOperations(changes.UpdateOld(), dns.RecordOperationOperationRemove)...)
+	ops = append(ops, p.newUpdateRecordOperations(changes.Update)...)
 
 	ops = append(ops, p.newFilteredRecordOperations(changes.Delete, dns.RecordOperationOperationRemove)...)
 
+	ops = append(ops, p.newCompanionOperations(changes)...)
+
 	if len(ops) == 0 {
 		log.Info("All records are already up to date")
 		return nil
@@ -318,13 +495,15 @@ func (p *OCIProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) e
 
 	zones, err := p.zones(ctx)
 	if err != nil {
-		return provider.NewSoftError(fmt.Errorf("fetching zones: %w", err))
+		return fmt.Errorf("fetching zones: %w", err)
 	}
 
 	// Separate into per-zone change sets to be passed to OCI API.
 	opsByZone := operationsByZone(zones, ops)
+	summaries := p.summarizeChanges(zones, changes)
 	for zoneID, ops := range opsByZone {
-		log.Infof("Change zone: %q", zoneID)
+		summary := summaries[zoneID]
+		log.Infof("Change zone: %q created: %d updated: %d deleted: %d", zoneID, summary.Created, summary.Updated, summary.Deleted)
 		for _, op := range ops {
 			log.Info(op)
 		}
@@ -334,17 +513,99 @@ func (p *OCIProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) e
 		return nil
 	}
 
+	// A failing zone does not cancel the others, its error is collected and returned once all zones were patched.
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	var eg errgroup.Group
+	eg.SetLimit(max(p.cfg.ApplyConcurrency, 1))
 	for zoneID, ops := range opsByZone {
-		if _, err := p.client.PatchZoneRecords(ctx, dns.PatchZoneRecordsRequest{
-			CompartmentId:           &p.cfg.CompartmentID,
-			ZoneNameOrId:            &zoneID,
-			PatchZoneRecordsDetails: dns.PatchZoneRecordsDetails{Items: ops},
-		}); err != nil {
-			return provider.NewSoftError(err)
+		eg.Go(func() error {
+			// Zones of both scopes may be managed at once, so use the scope the zone was listed with.
+			// The patch is applied atomically and a rejected patch does not report the failing
+			// operations, so the operations of the zone are retried together on the next sync.
+			if _, err := p.client.PatchZoneRecords(ctx, dns.PatchZoneRecordsRequest{
+				CompartmentId:           &p.cfg.CompartmentID,
+				ZoneNameOrId:            &zoneID,
+				Scope:                   dns.PatchZoneRecordsScopeEnum(zones[zoneID].Scope),
+				ViewId:                  zones[zoneID].ViewId,
+				PatchZoneRecordsDetails: dns.PatchZoneRecordsDetails{Items: ops},
+			}); err != nil {
+				mu.Lock()
+				errs = append(errs, classifyError(fmt.Errorf("patching records of zone %q: %w", zoneID, err)))
+				mu.Unlock()
+				return nil
+			}
+			summary := summaries[zoneID]
+			recordChangesTotal.CounterVec.WithLabelValues(zoneID, "create").Add(float64(summary.Created))
+			recordChangesTotal.CounterVec.WithLabelValues(zoneID, "update").Add(float64(summary.Updated))
+			recordChangesTotal.CounterVec.WithLabelValues(zoneID, "delete").Add(float64(summary.Deleted))
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	return joinZoneErrors(errs, len(opsByZone))
+}
+
+// joinZoneErrors combines the errors of the zones which failed to be patched. If other zones were
+// patched successfully, the combined error is soft, so that they are not treated as failed as well.
+func joinZoneErrors(errs []error, zoneCount int) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	err := errors.Join(errs...)
+	if len(errs) < zoneCount {
+		return provider.NewSoftError(fmt.Errorf("failed to patch %d of %d zones: %w", len(errs), zoneCount, err))
+	}
+	return err
+}
+
+// summarizeChanges counts the records created, updated and deleted in each zone, by zone ID.
+// Records outside the domain filter or any zone are not counted, nor are companion records.
+func (p *OCIProvider) summarizeChanges(zones map[string]dns.ZoneSummary, changes *plan.Changes) map[string]changeSummary {
+	zoneNameIDMapper := provider.ZoneIDName{}
+	for _, z := range zones {
+		zoneNameIDMapper.Add(*z.Id, *z.Name)
+	}
+	summaries := make(map[string]changeSummary)
+	count := func(ep *endpoint.Endpoint, tally func(*changeSummary)) {
+		if ep == nil || !p.domainFilter.Match(ep.DNSName) {
+			return
+		}
+		if zoneID, _ := zoneNameIDMapper.FindZone(ep.DNSName); zoneID != "" {
+			summary := summaries[zoneID]
+			tally(&summary)
+			summaries[zoneID] = summary
+		}
+	}
+	for _, ep := range changes.Create {
+		count(ep, func(s *changeSummary) { s.Created++ })
+	}
+	for _, update := range changes.Update {
+		if update != nil && update.Old != nil {
+			count(update.New, func(s *changeSummary) { s.Updated++ })
 		}
 	}
+	for _, ep := range changes.Delete {
+		count(ep, func(s *changeSummary) { s.Deleted++ })
+	}
+	return summaries
+}
 
-	return nil
+// classifyError marks transient OCI errors as soft errors so that the controller retries them.
+// Throttling and server-side failures are transient, as are errors without an OCI status such as
+// network failures. All other service errors, e.g. authorization failures, are returned as is.
+func classifyError(err error) error {
+	var serviceErr common.ServiceError
+	if errors.As(err, &serviceErr) {
+		status := serviceErr.GetHTTPStatusCode()
+		if status != http.StatusTooManyRequests && status < http.StatusInternalServerError {
+			return err
+		}
+	}
+	return provider.NewSoftError(err)
 }
 
 // AdjustEndpoints modifies the endpoints as needed by the specific provider
@@ -361,8 +622,9 @@ func (p *OCIProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoi
 	return adjustedEndpoints, nil
 }
 
-// newRecordOperation returns a RecordOperation based on a given endpoint.
-func newRecordOperation(ep *endpoint.Endpoint, opType dns.RecordOperationOperationEnum) dns.RecordOperation {
+// newRecordOperation returns a RecordOperation based on a given endpoint. Endpoints
+// without a TTL get defaultTTL, see provider.TTLOrDefault.
+func newRecordOperation(ep *endpoint.Endpoint, opType dns.RecordOperationOperationEnum, defaultTTL endpoint.TTL) dns.RecordOperation {
 	targets := make([]string, len(ep.Targets))
 	copy(targets, ep.Targets)
 	if ep.RecordType == endpoint.RecordTypeCNAME {
@@ -370,10 +632,7 @@ func newRecordOperation(ep *endpoint.Endpoint, opType dns.RecordOperationOperati
 	}
 	rdata := strings.Join(targets, " ")
 
-	ttl := defaultTTL
-	if ep.RecordTTL.IsConfigured() {
-		ttl = int(ep.RecordTTL)
-	}
+	ttl := int(provider.TTLOrDefault(ep, defaultTTL))
 
 	return dns.RecordOperation{
 		Domain:    &ep.DNSName,
@@ -384,6 +643,12 @@ func newRecordOperation(ep *endpoint.Endpoint, opType dns.RecordOperationOperati
 	}
 }
 
+// wildcardUnescape converts an escaped wildcard label (\\052.abc) back to *.abc, so that
+// wildcard records read back match the endpoints they were created from.
+func wildcardUnescape(domain string) string {
+	return strings.Replace(domain, "\\052", "*", 1)
+}
+
 // operationsByZone segments a slice of RecordOperations by their zone.
 func operationsByZone(zones map[string]dns.ZoneSummary, ops []dns.RecordOperation) map[string][]dns.RecordOperation {
 	changes := make(map[string][]dns.RecordOperation)
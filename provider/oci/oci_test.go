@@ -20,8 +20,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -34,6 +38,8 @@ import (
 	"sigs.k8s.io/external-dns/provider"
 )
 
+const defaultTTL = provider.DefaultTTL
+
 type mockOCIDNSClient struct{}
 
 var (
@@ -96,12 +102,12 @@ func (c *mockOCIDNSClient) GetZoneRecords(ctx context.Context, request dns.GetZo
 				Domain: common.String("foo.foo.com"),
 				Rdata:  common.String("127.0.0.1"),
 				Rtype:  common.String(endpoint.RecordTypeA),
-				Ttl:    common.Int(defaultTTL),
+				Ttl:    common.Int(int(defaultTTL)),
 			}, {
 				Domain: common.String("foo.foo.com"),
 				Rdata:  common.String("heritage=external-dns,external-dns/owner=default,external-dns/resource=service/default/my-svc"),
 				Rtype:  common.String(endpoint.RecordTypeTXT),
-				Ttl:    common.Int(defaultTTL),
+				Ttl:    common.Int(int(defaultTTL)),
 			}}
 			response.OpcNextPage = common.String("1")
 		} else {
@@ -109,7 +115,7 @@ func (c *mockOCIDNSClient) GetZoneRecords(ctx context.Context, request dns.GetZo
 				Domain: common.String("bar.foo.com"),
 				Rdata:  common.String("bar.com."),
 				Rtype:  common.String(endpoint.RecordTypeCNAME),
-				Ttl:    common.Int(defaultTTL),
+				Ttl:    common.Int(int(defaultTTL)),
 			}}
 		}
 	case "ocid1.dns-zone.oc1..502aeddba262b92fd13ed7874f6f1404":
@@ -118,7 +124,7 @@ func (c *mockOCIDNSClient) GetZoneRecords(ctx context.Context, request dns.GetZo
 				Domain: common.String("foo.bar.com"),
 				Rdata:  common.String("127.0.0.1"),
 				Rtype:  common.String(endpoint.RecordTypeA),
-				Ttl:    common.Int(defaultTTL),
+				Ttl:    common.Int(int(defaultTTL)),
 			}}
 		}
 	}
@@ -222,6 +228,12 @@ hKRtDhmSdWBo3tJK12RrAe4t7CUe8gMgTvU7ExlcA3xQkseFPx9K
 			},
 			err: errors.New("only one of 'useInstancePrincipal' and 'useWorkloadIdentity' may be enabled for Oracle authentication"),
 		},
+		"invalid-zone-name-regex": {
+			config: OCIConfig{
+				ZoneNameRegex: "(",
+			},
+			err: errors.New("invalid zone name regex"),
+		},
 	}
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -308,6 +320,28 @@ func TestOCIZones(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:         "ZoneIDFilter_foo.com",
+			domainFilter: endpoint.NewDomainFilter([]string{""}),
+			zoneIDFilter: provider.NewZoneIDFilter([]string{"foo.com"}),
+			zoneScope:    "GLOBAL",
+			expected: map[string]dns.ZoneSummary{
+				fooZoneId: {
+					Id:   common.String(fooZoneId),
+					Name: common.String("foo.com"),
+				},
+			},
+		},
+		{
+			name:         "ZoneIDFilter_name_and_ocid",
+			domainFilter: endpoint.NewDomainFilter([]string{""}),
+			zoneIDFilter: provider.NewZoneIDFilter([]string{"bar.com.", "ocid1.dns-zone.oc1..e1e042ef0bfbb5c251b9713fd7bf8959"}),
+			zoneScope:    "GLOBAL",
+			expected: map[string]dns.ZoneSummary{
+				fooZoneId: testGlobalZoneSummaryFoo,
+				barZoneId: testGlobalZoneSummaryBar,
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -319,6 +353,32 @@ func TestOCIZones(t *testing.T) {
 	}
 }
 
+func TestOCIZonesNameRegex(t *testing.T) {
+	var zones []dns.ZoneSummary
+	for i, name := range []string{"team-a.example.com", "team-b.example.com", "team-a-staging.example.com", "other.example.com"} {
+		zones = append(zones, dns.ZoneSummary{
+			Id:   common.String(fmt.Sprintf("ocid1.dns-zone.oc1..%032d", i)),
+			Name: common.String(name),
+		})
+	}
+	p := newOCIProvider(newMutableMockOCIDNSClient(zones, nil), endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), "", false)
+	p.zoneNameRegex = regexp.MustCompile(`^team-[a-z]+`)
+	p.zoneNameExclusionRegex = regexp.MustCompile(`-staging\.`)
+
+	actual, err := p.zones(context.Background())
+	require.NoError(t, err)
+	var names []string
+	for _, zone := range actual {
+		names = append(names, *zone.Name)
+	}
+	require.ElementsMatch(t, []string{"team-a.example.com", "team-b.example.com"}, names)
+}
+
+func TestOCISupportedRecordTypes(t *testing.T) {
+	p := newOCIProvider(newMutableMockOCIDNSClient(nil, nil), endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), "", false)
+	require.ElementsMatch(t, []string{"A", "AAAA", "CNAME", "SRV", "TXT", "NS"}, p.SupportedRecordTypes())
+}
+
 func TestOCIRecords(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -421,12 +481,92 @@ func TestNewRecordOperation(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			op := newRecordOperation(tc.ep, tc.opType)
+			op := newRecordOperation(tc.ep, tc.opType, 0)
 			require.Equal(t, tc.expected, op)
 		})
 	}
 }
 
+func TestNewFilteredRecordOperationsDefaultTTL(t *testing.T) {
+	p := newOCIProvider(&mockOCIDNSClient{}, endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), "", false)
+	p.cfg.DefaultTTL = 120
+
+	ops := p.newFilteredRecordOperations([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("foo.foo.com", endpoint.RecordTypeA, "127.0.0.1"),
+		endpoint.NewEndpointWithTTL("bar.foo.com", endpoint.RecordTypeA, endpoint.TTL(600), "127.0.0.2"),
+	}, dns.RecordOperationOperationAdd)
+
+	require.Len(t, ops, 2)
+	require.Equal(t, 120, *ops[0].Ttl)
+	require.Equal(t, 600, *ops[1].Ttl)
+}
+
+func TestNewUpdateRecordOperations(t *testing.T) {
+	p := newOCIProvider(&mockOCIDNSClient{}, endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), "", false)
+
+	for _, tc := range []struct {
+		name     string
+		update   *plan.Update
+		expected []string
+	}{
+		{
+			name: "ttl only",
+			update: &plan.Update{
+				Old: endpoint.NewEndpointWithTTL("foo.foo.com", endpoint.RecordTypeA, endpoint.TTL(300), "127.0.0.1"),
+				New: endpoint.NewEndpointWithTTL("foo.foo.com", endpoint.RecordTypeA, endpoint.TTL(600), "127.0.0.1"),
+			},
+			expected: []string{"ADD 127.0.0.1 600"},
+		},
+		{
+			name: "ttl only with several targets",
+			update: &plan.Update{
+				Old: endpoint.NewEndpointWithTTL("foo.foo.com", endpoint.RecordTypeA, endpoint.TTL(300), "127.0.0.1", "127.0.0.2"),
+				New: endpoint.NewEndpointWithTTL("foo.foo.com", endpoint.RecordTypeA, endpoint.TTL(600), "127.0.0.1", "127.0.0.2"),
+			},
+			expected: []string{"ADD 127.0.0.1 600", "ADD 127.0.0.2 600"},
+		},
+		{
+			name: "ttl and targets",
+			update: &plan.Update{
+				Old: endpoint.NewEndpointWithTTL("foo.foo.com", endpoint.RecordTypeA, endpoint.TTL(300), "127.0.0.1", "127.0.0.2"),
+				New: endpoint.NewEndpointWithTTL("foo.foo.com", endpoint.RecordTypeA, endpoint.TTL(600), "127.0.0.1", "127.0.0.3"),
+			},
+			expected: []string{"REMOVE 127.0.0.2 300", "ADD 127.0.0.1 600", "ADD 127.0.0.3 600"},
+		},
+		{
+			name: "renamed",
+			update: &plan.Update{
+				Old: endpoint.NewEndpointWithTTL("foo.foo.com", endpoint.RecordTypeA, endpoint.TTL(300), "127.0.0.1"),
+				New: endpoint.NewEndpointWithTTL("bar.foo.com", endpoint.RecordTypeA, endpoint.TTL(300), "127.0.0.1"),
+			},
+			expected: []string{"REMOVE 127.0.0.1 300", "ADD 127.0.0.1 300"},
+		},
+		{
+			name: "unchanged target is kept",
+			update: &plan.Update{
+				Old: endpoint.NewEndpointWithTTL("foo.foo.com", endpoint.RecordTypeA, endpoint.TTL(300), "127.0.0.1", "127.0.0.2"),
+				New: endpoint.NewEndpointWithTTL("foo.foo.com", endpoint.RecordTypeA, endpoint.TTL(300), "127.0.0.1", "127.0.0.3"),
+			},
+			expected: []string{"REMOVE 127.0.0.2 300", "ADD 127.0.0.3 300"},
+		},
+		{
+			name: "no change",
+			update: &plan.Update{
+				Old: endpoint.NewEndpointWithTTL("foo.foo.com", endpoint.RecordTypeA, endpoint.TTL(300), "127.0.0.1"),
+				New: endpoint.NewEndpointWithTTL("foo.foo.com", endpoint.RecordTypeA, endpoint.TTL(300), "127.0.0.1"),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual []string
+			for _, op := range p.newUpdateRecordOperations([]*plan.Update{tc.update}) {
+				actual = append(actual, fmt.Sprintf("%s %s %d", op.Operation, *op.Rdata, *op.Ttl))
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
 func TestOperationsByZone(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -525,6 +665,44 @@ func TestOperationsByZone(t *testing.T) {
 	}
 }
 
+func TestOCISummarizeChanges(t *testing.T) {
+	zones := map[string]dns.ZoneSummary{
+		"foo": {
+			Id:   common.String("foo"),
+			Name: common.String("foo.com"),
+		},
+		"bar": {
+			Id:   common.String("bar"),
+			Name: common.String("bar.com"),
+		},
+	}
+	p := newOCIProvider(nil, endpoint.NewDomainFilter([]string{"foo.com", "bar.com"}), provider.NewZoneIDFilter([]string{""}), "", false)
+
+	summaries := p.summarizeChanges(zones, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.foo.com", endpoint.RecordTypeA, "127.0.0.1"),
+			endpoint.NewEndpoint("b.foo.com", endpoint.RecordTypeA, "127.0.0.2", "127.0.0.3"),
+			endpoint.NewEndpoint("a.bar.com", endpoint.RecordTypeCNAME, "foo.com"),
+			endpoint.NewEndpoint("a.baz.com", endpoint.RecordTypeA, "127.0.0.1"),
+		},
+		Update: []*plan.Update{
+			{
+				Old: endpoint.NewEndpoint("c.foo.com", endpoint.RecordTypeA, "127.0.0.1"),
+				New: endpoint.NewEndpoint("c.foo.com", endpoint.RecordTypeA, "127.0.0.4"),
+			},
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("b.bar.com", endpoint.RecordTypeA, "127.0.0.1"),
+			endpoint.NewEndpoint("c.bar.com", endpoint.RecordTypeTXT, "text"),
+		},
+	})
+
+	require.Equal(t, map[string]changeSummary{
+		"foo": {Created: 2, Updated: 1},
+		"bar": {Created: 1, Deleted: 2},
+	}, summaries)
+}
+
 type mutableMockOCIDNSClient struct {
 	zones   map[string]dns.ZoneSummary
 	records map[string]map[string]dns.Record
@@ -580,7 +758,7 @@ func (c *mutableMockOCIDNSClient) GetZoneRecords(_ context.Context, request dns.
 
 func ociRecordKey(rType, domain string, ip string) string {
 	rdata := ""
-	if rType == "A" { // adds support for multi-targets with same rtype and domain
+	if rType == "A" || rType == "TXT" { // adds support for multi-targets with same rtype and domain
 		rdata = "_" + ip
 	}
 	return rType + "_" + domain + rdata
@@ -602,6 +780,9 @@ func (c *mutableMockOCIDNSClient) PatchZoneRecords(ctx context.Context, request
 	if !ok {
 		return response, errors.New("zone not found")
 	}
+	if zone := c.zones[*request.ZoneNameOrId]; zone.Scope != "" && string(zone.Scope) != string(request.Scope) {
+		return response, fmt.Errorf("zone not found in scope %q", request.Scope)
+	}
 
 	// Ensure that ADD operations occur after REMOVE.
 	sort.Slice(request.Items, func(i, j int) bool {
@@ -639,12 +820,12 @@ func TestMutableMockOCIDNSClient(t *testing.T) {
 			Domain: common.String("foo.foo.com"),
 			Rdata:  common.String("127.0.0.1"),
 			Rtype:  common.String(endpoint.RecordTypeA),
-			Ttl:    common.Int(defaultTTL),
+			Ttl:    common.Int(int(defaultTTL)),
 		}, {
 			Domain: common.String("foo.foo.com"),
 			Rdata:  common.String("heritage=external-dns,external-dns/owner=default,external-dns/resource=service/default/my-svc"),
 			Rtype:  common.String(endpoint.RecordTypeTXT),
-			Ttl:    common.Int(defaultTTL),
+			Ttl:    common.Int(int(defaultTTL)),
 		}},
 	}
 	client := newMutableMockOCIDNSClient(zones, records)
@@ -710,6 +891,194 @@ func TestMutableMockOCIDNSClient(t *testing.T) {
 	require.ElementsMatch(t, recordsResponse.Items, records["ocid1.dns-zone.oc1..e1e042ef0bfbb5c251b9713fd7bf8959"])
 }
 
+func TestOCIRecordsWildcard(t *testing.T) {
+	zoneID := "ocid1.dns-zone.oc1..e1e042ef0bfbb5c251b9713fd7bf8959"
+	client := newMutableMockOCIDNSClient(
+		[]dns.ZoneSummary{{Id: common.String(zoneID), Name: common.String("foo.com")}},
+		map[string][]dns.Record{
+			zoneID: {{
+				Domain: common.String("*.apps.foo.com"),
+				Rdata:  common.String("127.0.0.1"),
+				Rtype:  common.String(endpoint.RecordTypeA),
+				Ttl:    common.Int(int(defaultTTL)),
+			}, {
+				Domain: common.String("\\052.escaped.foo.com"),
+				Rdata:  common.String("127.0.0.2"),
+				Rtype:  common.String(endpoint.RecordTypeA),
+				Ttl:    common.Int(int(defaultTTL)),
+			}},
+		},
+	)
+	provider := newOCIProvider(client, endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), "", false)
+
+	endpoints, err := provider.Records(context.Background())
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("*.apps.foo.com", endpoint.RecordTypeA, endpoint.TTL(defaultTTL), "127.0.0.1"),
+		endpoint.NewEndpointWithTTL("*.escaped.foo.com", endpoint.RecordTypeA, endpoint.TTL(defaultTTL), "127.0.0.2"),
+	}, endpoints)
+}
+
+func TestOCIRecordsMultiTargetTTL(t *testing.T) {
+	zoneID := "ocid1.dns-zone.oc1..e1e042ef0bfbb5c251b9713fd7bf8959"
+	client := newMutableMockOCIDNSClient(
+		[]dns.ZoneSummary{{Id: common.String(zoneID), Name: common.String("foo.com")}},
+		map[string][]dns.Record{
+			zoneID: {{
+				Domain: common.String("multi.foo.com"),
+				Rdata:  common.String("127.0.0.1"),
+				Rtype:  common.String(endpoint.RecordTypeA),
+				Ttl:    common.Int(600),
+			}, {
+				Domain: common.String("multi.foo.com"),
+				Rdata:  common.String("127.0.0.2"),
+				Rtype:  common.String(endpoint.RecordTypeA),
+				Ttl:    common.Int(60),
+			}, {
+				Domain: common.String("multi.foo.com"),
+				Rdata:  common.String("127.0.0.3"),
+				Rtype:  common.String(endpoint.RecordTypeA),
+				Ttl:    common.Int(300),
+			}},
+		},
+	)
+	provider := newOCIProvider(client, endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), "", false)
+
+	endpoints, err := provider.Records(context.Background())
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	require.Equal(t, endpoint.TTL(60), endpoints[0].RecordTTL)
+	require.ElementsMatch(t, endpoint.Targets{"127.0.0.1", "127.0.0.2", "127.0.0.3"}, endpoints[0].Targets)
+}
+
+func TestOCIOwnershipTXT(t *testing.T) {
+	zoneID := "ocid1.dns-zone.oc1..e1e042ef0bfbb5c251b9713fd7bf8959"
+	registryTXT := "\"heritage=external-dns,external-dns/owner=default\""
+	client := newMutableMockOCIDNSClient(
+		[]dns.ZoneSummary{{Id: common.String(zoneID), Name: common.String("foo.com")}},
+		map[string][]dns.Record{
+			zoneID: {{
+				Domain: common.String("bar.foo.com"),
+				Rdata:  common.String(registryTXT),
+				Rtype:  common.String(endpoint.RecordTypeTXT),
+				Ttl:    common.Int(int(defaultTTL)),
+			}},
+		},
+	)
+	p := newOCIProvider(client, endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), "", false)
+	p.cfg.OwnershipTXT = true
+
+	a := endpoint.NewEndpointWithTTL("bar.foo.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.1", "127.0.0.2")
+	a.Labels[endpoint.OwnerLabelKey] = "default"
+	cname := endpoint.NewEndpointWithTTL("baz.foo.com", endpoint.RecordTypeCNAME, defaultTTL, "bar.foo.com")
+	require.NoError(t, p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{a, cname},
+	}))
+
+	companionRdata := "\"heritage=external-dns,external-dns/companion=a,external-dns/owner=default\""
+	companion, ok := client.records[zoneID][ociRecordKey(endpoint.RecordTypeTXT, "bar.foo.com", companionRdata)]
+	require.True(t, ok, "the A record should get a TXT companion")
+	require.Equal(t, int(defaultTTL), *companion.Ttl)
+	require.Len(t, client.records[zoneID], 5, "the A targets, CNAME, companion and registry TXT records should exist")
+
+	endpoints, err := p.Records(context.Background())
+	require.NoError(t, err)
+	sortEndpointTargets(endpoints)
+	require.ElementsMatch(t, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("bar.foo.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.1", "127.0.0.2"),
+		endpoint.NewEndpointWithTTL("bar.foo.com", endpoint.RecordTypeTXT, defaultTTL, registryTXT),
+		endpoint.NewEndpointWithTTL("baz.foo.com", endpoint.RecordTypeCNAME, defaultTTL, "bar.foo.com."),
+	}, endpoints)
+
+	require.NoError(t, p.ApplyChanges(context.Background(), &plan.Changes{
+		Delete: []*endpoint.Endpoint{a},
+	}))
+	_, ok = client.records[zoneID][ociRecordKey(endpoint.RecordTypeTXT, "bar.foo.com", companionRdata)]
+	require.False(t, ok, "the TXT companion should be removed with the A record")
+	_, ok = client.records[zoneID][ociRecordKey(endpoint.RecordTypeTXT, "bar.foo.com", registryTXT)]
+	require.True(t, ok, "the registry TXT record should be left alone")
+}
+
+func TestOCIDefaultTTL(t *testing.T) {
+	zoneID := "ocid1.dns-zone.oc1..e1e042ef0bfbb5c251b9713fd7bf8959"
+	client := newMutableMockOCIDNSClient(
+		[]dns.ZoneSummary{{Id: common.String(zoneID), Name: common.String("foo.com")}},
+		map[string][]dns.Record{
+			zoneID: {{
+				Domain: common.String("nottl.foo.com"),
+				Rdata:  common.String("127.0.0.1"),
+				Rtype:  common.String(endpoint.RecordTypeA),
+			}},
+		},
+	)
+	p := newOCIProvider(client, endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), "", false)
+	p.cfg.DefaultTTL = 900
+
+	err := p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("bar.foo.com", endpoint.RecordTypeA, "127.0.0.2"),
+			endpoint.NewEndpointWithTTL("baz.foo.com", endpoint.RecordTypeA, endpoint.TTL(60), "127.0.0.3"),
+		},
+	})
+	require.NoError(t, err)
+
+	endpoints, err := p.Records(context.Background())
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("nottl.foo.com", endpoint.RecordTypeA, endpoint.TTL(900), "127.0.0.1"),
+		endpoint.NewEndpointWithTTL("bar.foo.com", endpoint.RecordTypeA, endpoint.TTL(900), "127.0.0.2"),
+		endpoint.NewEndpointWithTTL("baz.foo.com", endpoint.RecordTypeA, endpoint.TTL(60), "127.0.0.3"),
+	}, endpoints)
+}
+
+func TestOCIPrivateZoneView(t *testing.T) {
+	zoneIDA := "ocid1.dns-zone.oc1..aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	zoneIDB := "ocid1.dns-zone.oc1..bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	viewIDB := "ocid1.dnsview.oc1..bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	client := newMutableMockOCIDNSClient(
+		[]dns.ZoneSummary{
+			{Id: common.String(zoneIDA), Name: common.String("foo.com"), Scope: dns.ScopePrivate, ViewId: common.String("ocid1.dnsview.oc1..aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")},
+			{Id: common.String(zoneIDB), Name: common.String("foo.com"), Scope: dns.ScopePrivate, ViewId: common.String(viewIDB)},
+		},
+		map[string][]dns.Record{
+			zoneIDA: {{
+				Domain: common.String("a.foo.com"),
+				Rdata:  common.String("10.0.0.1"),
+				Rtype:  common.String(endpoint.RecordTypeA),
+				Ttl:    common.Int(int(defaultTTL)),
+			}},
+			zoneIDB: {{
+				Domain: common.String("b.foo.com"),
+				Rdata:  common.String("10.0.0.2"),
+				Rtype:  common.String(endpoint.RecordTypeA),
+				Ttl:    common.Int(int(defaultTTL)),
+			}},
+		},
+	)
+	p := newOCIProvider(client, endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), string(dns.GetZoneScopePrivate), false)
+	p.cfg.ViewID = viewIDB
+
+	zones, err := p.zones(context.Background())
+	require.NoError(t, err)
+	require.Len(t, zones, 1)
+	require.Contains(t, zones, zoneIDB)
+
+	err = p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpointWithTTL("c.foo.com", endpoint.RecordTypeA, endpoint.TTL(defaultTTL), "10.0.0.3"),
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, client.records[zoneIDA], 1)
+
+	endpoints, err := p.Records(context.Background())
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("b.foo.com", endpoint.RecordTypeA, endpoint.TTL(defaultTTL), "10.0.0.2"),
+		endpoint.NewEndpointWithTTL("c.foo.com", endpoint.RecordTypeA, endpoint.TTL(defaultTTL), "10.0.0.3"),
+	}, endpoints)
+}
+
 func TestOCIApplyChanges(t *testing.T) {
 
 	testCases := []struct {
@@ -752,12 +1121,12 @@ func TestOCIApplyChanges(t *testing.T) {
 					Domain: common.String("foo.foo.com"),
 					Rdata:  common.String("127.0.0.1"),
 					Rtype:  common.String(endpoint.RecordTypeA),
-					Ttl:    common.Int(defaultTTL),
+					Ttl:    common.Int(int(defaultTTL)),
 				}, {
 					Domain: common.String("foo.foo.com"),
 					Rdata:  common.String("heritage=external-dns,external-dns/owner=default,external-dns/resource=service/default/my-svc"),
 					Rtype:  common.String(endpoint.RecordTypeTXT),
-					Ttl:    common.Int(defaultTTL),
+					Ttl:    common.Int(int(defaultTTL)),
 				}},
 			},
 			changes: &plan.Changes{
@@ -785,7 +1154,7 @@ func TestOCIApplyChanges(t *testing.T) {
 					Domain: common.String("foo.foo.com"),
 					Rdata:  common.String("127.0.0.1"),
 					Rtype:  common.String(endpoint.RecordTypeA),
-					Ttl:    common.Int(defaultTTL),
+					Ttl:    common.Int(int(defaultTTL)),
 				}},
 			},
 			changes: &plan.Changes{
@@ -823,7 +1192,7 @@ func TestOCIApplyChanges(t *testing.T) {
 					Domain: common.String("foo.foo.com"),
 					Rdata:  common.String("127.0.0.1"),
 					Rtype:  common.String(endpoint.RecordTypeA),
-					Ttl:    common.Int(defaultTTL),
+					Ttl:    common.Int(int(defaultTTL)),
 				}},
 			},
 			changes: &plan.Changes{
@@ -852,17 +1221,17 @@ func TestOCIApplyChanges(t *testing.T) {
 					Domain: common.String("foo.foo.com"),
 					Rdata:  common.String("127.0.0.1"),
 					Rtype:  common.String(endpoint.RecordTypeA),
-					Ttl:    common.Int(defaultTTL),
+					Ttl:    common.Int(int(defaultTTL)),
 				}, {
 					Domain: common.String("car.foo.com"),
 					Rdata:  common.String("bar.com."),
 					Rtype:  common.String(endpoint.RecordTypeCNAME),
-					Ttl:    common.Int(defaultTTL),
+					Ttl:    common.Int(int(defaultTTL)),
 				}, {
 					Domain: common.String("bar.foo.com"),
 					Rdata:  common.String("baz.com."),
 					Rtype:  common.String(endpoint.RecordTypeCNAME),
-					Ttl:    common.Int(defaultTTL),
+					Ttl:    common.Int(int(defaultTTL)),
 				}},
 			},
 			changes: &plan.Changes{
@@ -946,12 +1315,12 @@ func TestOCIApplyChanges(t *testing.T) {
 					Domain: common.String("foo.foo.com"),
 					Rdata:  common.String("192.168.1.2"),
 					Rtype:  common.String(endpoint.RecordTypeA),
-					Ttl:    common.Int(defaultTTL),
+					Ttl:    common.Int(int(defaultTTL)),
 				}, {
 					Domain: common.String("foo.foo.com"),
 					Rdata:  common.String("192.168.2.5"),
 					Rtype:  common.String(endpoint.RecordTypeA),
-					Ttl:    common.Int(defaultTTL),
+					Ttl:    common.Int(int(defaultTTL)),
 				}},
 			},
 			changes: &plan.Changes{
@@ -979,7 +1348,7 @@ func TestOCIApplyChanges(t *testing.T) {
 					Domain: common.String("first.foo.com"),
 					Rdata:  common.String("10.77.4.5"),
 					Rtype:  common.String(endpoint.RecordTypeA),
-					Ttl:    common.Int(defaultTTL),
+					Ttl:    common.Int(int(defaultTTL)),
 				}},
 			},
 			changes: &plan.Changes{
@@ -1018,7 +1387,7 @@ func TestOCIApplyChanges(t *testing.T) {
 					Domain: common.String("first.foo.com"),
 					Rdata:  common.String("10.77.4.5"),
 					Rtype:  common.String(endpoint.RecordTypeA),
-					Ttl:    common.Int(defaultTTL),
+					Ttl:    common.Int(int(defaultTTL)),
 				}},
 			},
 			changes: &plan.Changes{
@@ -1036,6 +1405,63 @@ func TestOCIApplyChanges(t *testing.T) {
 				"10.77.4.5", "10.77.6.10",
 			)},
 		},
+		{
+			name: "mixed_scopes",
+			zones: []dns.ZoneSummary{{
+				Id:    common.String("ocid1.dns-zone.oc1..e1e042ef0bfbb5c251b9713fd7bf8959"),
+				Name:  common.String("foo.com"),
+				Scope: dns.ScopeGlobal,
+			}, {
+				Id:    common.String("ocid1.dns-zone.oc1..789012ef0bfbb5c251b9713fd7bf8959"),
+				Name:  common.String("baz.com"),
+				Scope: dns.ScopePrivate,
+			}},
+			changes: &plan.Changes{
+				Create: []*endpoint.Endpoint{endpoint.NewEndpointWithTTL(
+					"foo.foo.com",
+					endpoint.RecordTypeA,
+					endpoint.TTL(defaultTTL),
+					"127.0.0.1",
+				), endpoint.NewEndpointWithTTL(
+					"foo.baz.com",
+					endpoint.RecordTypeA,
+					endpoint.TTL(defaultTTL),
+					"10.0.0.1",
+				)},
+			},
+			expectedEndpoints: []*endpoint.Endpoint{endpoint.NewEndpointWithTTL(
+				"foo.foo.com",
+				endpoint.RecordTypeA,
+				endpoint.TTL(defaultTTL),
+				"127.0.0.1",
+			), endpoint.NewEndpointWithTTL(
+				"foo.baz.com",
+				endpoint.RecordTypeA,
+				endpoint.TTL(defaultTTL),
+				"10.0.0.1",
+			)},
+		},
+		{
+			name: "add_wildcard",
+			zones: []dns.ZoneSummary{{
+				Id:   common.String("ocid1.dns-zone.oc1..e1e042ef0bfbb5c251b9713fd7bf8959"),
+				Name: common.String("foo.com"),
+			}},
+			changes: &plan.Changes{
+				Create: []*endpoint.Endpoint{endpoint.NewEndpointWithTTL(
+					"*.apps.foo.com",
+					endpoint.RecordTypeA,
+					endpoint.TTL(defaultTTL),
+					"127.0.0.1", "127.0.0.2",
+				)},
+			},
+			expectedEndpoints: []*endpoint.Endpoint{endpoint.NewEndpointWithTTL(
+				"*.apps.foo.com",
+				endpoint.RecordTypeA,
+				endpoint.TTL(defaultTTL),
+				"127.0.0.1", "127.0.0.2",
+			)},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1060,3 +1486,392 @@ func TestOCIApplyChanges(t *testing.T) {
 		})
 	}
 }
+
+// ociServiceError is a minimal common.ServiceError carrying an HTTP status code.
+type ociServiceError struct {
+	status int
+}
+
+func (e ociServiceError) Error() string           { return fmt.Sprintf("service error: %d", e.status) }
+func (e ociServiceError) GetHTTPStatusCode() int  { return e.status }
+func (e ociServiceError) GetMessage() string      { return http.StatusText(e.status) }
+func (e ociServiceError) GetCode() string         { return http.StatusText(e.status) }
+func (e ociServiceError) GetOpcRequestID() string { return "" }
+
+// failingOCIDNSClient lists a single zone and fails every other call with the configured error.
+type failingOCIDNSClient struct {
+	listErr  error
+	err      error
+	zoneList []dns.ZoneSummary
+}
+
+func (c *failingOCIDNSClient) ListZones(_ context.Context, _ dns.ListZonesRequest) (dns.ListZonesResponse, error) {
+	return dns.ListZonesResponse{Items: c.zoneList}, c.listErr
+}
+
+func (c *failingOCIDNSClient) GetZoneRecords(_ context.Context, _ dns.GetZoneRecordsRequest) (dns.GetZoneRecordsResponse, error) {
+	return dns.GetZoneRecordsResponse{}, c.err
+}
+
+func (c *failingOCIDNSClient) PatchZoneRecords(_ context.Context, _ dns.PatchZoneRecordsRequest) (dns.PatchZoneRecordsResponse, error) {
+	return dns.PatchZoneRecordsResponse{}, c.err
+}
+
+func TestOCISoftErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		soft bool
+	}{
+		{name: "throttled", err: ociServiceError{status: http.StatusTooManyRequests}, soft: true},
+		{name: "unavailable", err: ociServiceError{status: http.StatusServiceUnavailable}, soft: true},
+		{name: "network", err: errors.New("connection reset by peer"), soft: true},
+		{name: "unauthorized", err: ociServiceError{status: http.StatusUnauthorized}, soft: false},
+	}
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{endpoint.NewEndpoint("foo.foo.com", endpoint.RecordTypeA, "127.0.0.1")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			p := newOCIProvider(&failingOCIDNSClient{listErr: tc.err}, endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), "", false)
+			_, err := p.Records(ctx)
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.soft, errors.Is(err, provider.SoftError))
+
+			client := &failingOCIDNSClient{err: tc.err, zoneList: []dns.ZoneSummary{testGlobalZoneSummaryFoo}}
+			p = newOCIProvider(client, endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), "", false)
+			_, err = p.Records(ctx)
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.soft, errors.Is(err, provider.SoftError))
+
+			err = p.ApplyChanges(ctx, changes)
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.soft, errors.Is(err, provider.SoftError))
+		})
+	}
+}
+
+// concurrentOCIDNSClient serializes access to the mutable mock and records the maximum number of
+// PatchZoneRecords calls in flight.
+type concurrentOCIDNSClient struct {
+	*mutableMockOCIDNSClient
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (c *concurrentOCIDNSClient) PatchZoneRecords(ctx context.Context, request dns.PatchZoneRecordsRequest) (dns.PatchZoneRecordsResponse, error) {
+	c.mu.Lock()
+	c.inFlight++
+	c.maxInFlight = max(c.maxInFlight, c.inFlight)
+	c.mu.Unlock()
+
+	// give the other zones the chance to be patched at the same time
+	time.Sleep(20 * time.Millisecond)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inFlight--
+	return c.mutableMockOCIDNSClient.PatchZoneRecords(ctx, request)
+}
+
+func TestOCIApplyChangesConcurrency(t *testing.T) {
+	zones := []dns.ZoneSummary{
+		{Id: common.String("ocid1.dns-zone.oc1..foo"), Name: common.String("foo.com")},
+		{Id: common.String("ocid1.dns-zone.oc1..bar"), Name: common.String("bar.com")},
+		{Id: common.String("ocid1.dns-zone.oc1..baz"), Name: common.String("baz.com")},
+	}
+	existing := map[string][]dns.Record{
+		"ocid1.dns-zone.oc1..foo": {{
+			Domain: common.String("www.foo.com"),
+			Rdata:  common.String("127.0.0.1"),
+			Rtype:  common.String(endpoint.RecordTypeA),
+			Ttl:    common.Int(int(defaultTTL)),
+		}},
+	}
+
+	for _, tc := range []struct {
+		name        string
+		concurrency int
+		maxInFlight int
+	}{
+		{name: "default", concurrency: 0, maxInFlight: 1},
+		{name: "sequential", concurrency: 1, maxInFlight: 1},
+		{name: "bounded", concurrency: 2, maxInFlight: 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &concurrentOCIDNSClient{mutableMockOCIDNSClient: newMutableMockOCIDNSClient(zones, existing)}
+			p := newOCIProvider(client, endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), "", false)
+			p.cfg.ApplyConcurrency = tc.concurrency
+
+			err := p.ApplyChanges(context.Background(), &plan.Changes{
+				Create: []*endpoint.Endpoint{
+					endpoint.NewEndpointWithTTL("www.bar.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.2"),
+					endpoint.NewEndpointWithTTL("www.baz.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.3"),
+				},
+				Update: []*plan.Update{{
+					Old: endpoint.NewEndpointWithTTL("www.foo.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.1"),
+					New: endpoint.NewEndpointWithTTL("www.foo.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.4"),
+				}},
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.maxInFlight, client.maxInFlight)
+
+			endpoints, err := p.Records(context.Background())
+			require.NoError(t, err)
+			require.ElementsMatch(t, []*endpoint.Endpoint{
+				endpoint.NewEndpointWithTTL("www.foo.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.4"),
+				endpoint.NewEndpointWithTTL("www.bar.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.2"),
+				endpoint.NewEndpointWithTTL("www.baz.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.3"),
+			}, endpoints)
+		})
+	}
+}
+
+// zoneFailingOCIDNSClient fails to patch the records of a single zone.
+type zoneFailingOCIDNSClient struct {
+	*mutableMockOCIDNSClient
+
+	failZoneID string
+	err        error
+}
+
+func (c *zoneFailingOCIDNSClient) PatchZoneRecords(ctx context.Context, request dns.PatchZoneRecordsRequest) (dns.PatchZoneRecordsResponse, error) {
+	if *request.ZoneNameOrId == c.failZoneID {
+		return dns.PatchZoneRecordsResponse{}, c.err
+	}
+	return c.mutableMockOCIDNSClient.PatchZoneRecords(ctx, request)
+}
+
+func TestOCIApplyChangesZoneFailureIsolated(t *testing.T) {
+	zones := []dns.ZoneSummary{
+		{Id: common.String("ocid1.dns-zone.oc1..foo"), Name: common.String("foo.com")},
+		{Id: common.String("ocid1.dns-zone.oc1..bar"), Name: common.String("bar.com")},
+		{Id: common.String("ocid1.dns-zone.oc1..baz"), Name: common.String("baz.com")},
+	}
+	patchErr := ociServiceError{status: http.StatusBadRequest}
+	client := &zoneFailingOCIDNSClient{
+		mutableMockOCIDNSClient: newMutableMockOCIDNSClient(zones, nil),
+		failZoneID:              "ocid1.dns-zone.oc1..bar",
+		err:                     patchErr,
+	}
+	p := newOCIProvider(client, endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), "", false)
+	p.cfg.ApplyConcurrency = 1
+
+	err := p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpointWithTTL("www.foo.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.1"),
+			endpoint.NewEndpointWithTTL("www.bar.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.2"),
+			endpoint.NewEndpointWithTTL("www.baz.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.3"),
+		},
+	})
+	require.ErrorIs(t, err, patchErr)
+	require.ErrorIs(t, err, provider.SoftError)
+	require.ErrorContains(t, err, `"ocid1.dns-zone.oc1..bar"`)
+
+	endpoints, err := p.Records(context.Background())
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("www.foo.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.1"),
+		endpoint.NewEndpointWithTTL("www.baz.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.3"),
+	}, endpoints)
+}
+
+// rtypeRecordingOCIDNSClient records the record type filter of each records request and applies it.
+type rtypeRecordingOCIDNSClient struct {
+	*mutableMockOCIDNSClient
+
+	rtypes []*string
+}
+
+func (c *rtypeRecordingOCIDNSClient) GetZoneRecords(ctx context.Context, request dns.GetZoneRecordsRequest) (dns.GetZoneRecordsResponse, error) {
+	c.rtypes = append(c.rtypes, request.Rtype)
+	response, err := c.mutableMockOCIDNSClient.GetZoneRecords(ctx, request)
+	if err != nil || request.Rtype == nil {
+		return response, err
+	}
+	var items []dns.Record
+	for _, record := range response.Items {
+		if *record.Rtype == *request.Rtype {
+			items = append(items, record)
+		}
+	}
+	response.Items = items
+	return response, nil
+}
+
+func TestOCIRecordsManagedRecordTypes(t *testing.T) {
+	zones := []dns.ZoneSummary{
+		{Id: common.String("ocid1.dns-zone.oc1..foo"), Name: common.String("foo.com")},
+	}
+	records := map[string][]dns.Record{
+		"ocid1.dns-zone.oc1..foo": {
+			{Domain: common.String("www.foo.com"), Rdata: common.String("127.0.0.1"), Rtype: common.String(endpoint.RecordTypeA), Ttl: common.Int(int(defaultTTL))},
+			{Domain: common.String("www.foo.com"), Rdata: common.String("\"heritage=external-dns\""), Rtype: common.String(endpoint.RecordTypeTXT), Ttl: common.Int(int(defaultTTL))},
+			{Domain: common.String("api.foo.com"), Rdata: common.String("www.foo.com."), Rtype: common.String(endpoint.RecordTypeCNAME), Ttl: common.Int(int(defaultTTL))},
+		},
+	}
+
+	for _, tc := range []struct {
+		name               string
+		managedRecordTypes []string
+		expectedRtypes     []*string
+		expected           []*endpoint.Endpoint
+	}{
+		{
+			name:           "all supported types",
+			expectedRtypes: []*string{nil},
+			expected: []*endpoint.Endpoint{
+				endpoint.NewEndpointWithTTL("www.foo.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.1"),
+				endpoint.NewEndpointWithTTL("www.foo.com", endpoint.RecordTypeTXT, defaultTTL, "\"heritage=external-dns\""),
+				endpoint.NewEndpointWithTTL("api.foo.com", endpoint.RecordTypeCNAME, defaultTTL, "www.foo.com."),
+			},
+		},
+		{
+			name:               "few managed types",
+			managedRecordTypes: []string{endpoint.RecordTypeTXT, endpoint.RecordTypeA},
+			expectedRtypes:     []*string{common.String(endpoint.RecordTypeA), common.String(endpoint.RecordTypeTXT)},
+			expected: []*endpoint.Endpoint{
+				endpoint.NewEndpointWithTTL("www.foo.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.1"),
+				endpoint.NewEndpointWithTTL("www.foo.com", endpoint.RecordTypeTXT, defaultTTL, "\"heritage=external-dns\""),
+			},
+		},
+		{
+			name: "many managed types",
+			managedRecordTypes: []string{
+				endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME, endpoint.RecordTypeTXT,
+			},
+			expectedRtypes: []*string{nil},
+			expected: []*endpoint.Endpoint{
+				endpoint.NewEndpointWithTTL("www.foo.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.1"),
+				endpoint.NewEndpointWithTTL("www.foo.com", endpoint.RecordTypeTXT, defaultTTL, "\"heritage=external-dns\""),
+				endpoint.NewEndpointWithTTL("api.foo.com", endpoint.RecordTypeCNAME, defaultTTL, "www.foo.com."),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &rtypeRecordingOCIDNSClient{mutableMockOCIDNSClient: newMutableMockOCIDNSClient(zones, records)}
+			p := newOCIProvider(client, endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), "", false)
+			p.cfg.ManagedRecordTypes = tc.managedRecordTypes
+
+			endpoints, err := p.Records(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedRtypes, client.rtypes)
+			require.ElementsMatch(t, tc.expected, endpoints)
+		})
+	}
+}
+
+func TestOCIApplyChangesManagedRecordTypes(t *testing.T) {
+	zones := []dns.ZoneSummary{
+		{Id: common.String("ocid1.dns-zone.oc1..foo"), Name: common.String("foo.com")},
+	}
+	client := newMutableMockOCIDNSClient(zones, nil)
+	p := newOCIProvider(client, endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), "", false)
+	p.cfg.ManagedRecordTypes = []string{endpoint.RecordTypeA}
+
+	err := p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpointWithTTL("www.foo.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.1"),
+			endpoint.NewEndpointWithTTL("api.foo.com", endpoint.RecordTypeCNAME, defaultTTL, "www.foo.com."),
+		},
+	})
+	require.NoError(t, err)
+
+	p.cfg.ManagedRecordTypes = nil
+	endpoints, err := p.Records(context.Background())
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("www.foo.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.1"),
+	}, endpoints)
+}
+
+// rejectingOCIDNSClient rejects the first patches of the records, recording the operations of every patch.
+type rejectingOCIDNSClient struct {
+	*mutableMockOCIDNSClient
+
+	rejections int
+	patches    [][]dns.RecordOperation
+}
+
+func (c *rejectingOCIDNSClient) PatchZoneRecords(ctx context.Context, request dns.PatchZoneRecordsRequest) (dns.PatchZoneRecordsResponse, error) {
+	c.patches = append(c.patches, slices.Clone(request.Items))
+	if c.rejections > 0 {
+		c.rejections--
+		return dns.PatchZoneRecordsResponse{}, ociServiceError{status: http.StatusBadRequest}
+	}
+	return c.mutableMockOCIDNSClient.PatchZoneRecords(ctx, request)
+}
+
+func TestOCIApplyChangesRejectedPatchRetriedWhole(t *testing.T) {
+	zones := []dns.ZoneSummary{
+		{Id: common.String("ocid1.dns-zone.oc1..foo"), Name: common.String("foo.com")},
+	}
+	client := &rejectingOCIDNSClient{
+		mutableMockOCIDNSClient: newMutableMockOCIDNSClient(zones, nil),
+		rejections:              1,
+	}
+	p := newOCIProvider(client, endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), "", false)
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpointWithTTL("a.foo.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.1"),
+			endpoint.NewEndpointWithTTL("b.foo.com", endpoint.RecordTypeA, defaultTTL, "127.0.0.2"),
+		},
+	}
+
+	// a rejected patch applies none of its operations
+	require.Error(t, p.ApplyChanges(context.Background(), changes))
+	endpoints, err := p.Records(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, endpoints)
+
+	// the next sync sends all operations of the zone again
+	require.NoError(t, p.ApplyChanges(context.Background(), changes))
+	require.Len(t, client.patches, 2)
+	require.ElementsMatch(t, client.patches[0], client.patches[1])
+	endpoints, err = p.Records(context.Background())
+	require.NoError(t, err)
+	require.ElementsMatch(t, changes.Create, endpoints)
+}
+
+func TestOCIApplyChangesTTLOnlyUpdate(t *testing.T) {
+	zones := []dns.ZoneSummary{
+		{Id: common.String("ocid1.dns-zone.oc1..foo"), Name: common.String("foo.com")},
+	}
+	records := map[string][]dns.Record{
+		"ocid1.dns-zone.oc1..foo": {
+			{Domain: common.String("www.foo.com"), Rdata: common.String("127.0.0.1"), Rtype: common.String(endpoint.RecordTypeA), Ttl: common.Int(300)},
+			{Domain: common.String("www.foo.com"), Rdata: common.String("127.0.0.2"), Rtype: common.String(endpoint.RecordTypeA), Ttl: common.Int(300)},
+		},
+	}
+	client := &rejectingOCIDNSClient{mutableMockOCIDNSClient: newMutableMockOCIDNSClient(zones, records)}
+	p := newOCIProvider(client, endpoint.NewDomainFilter([]string{""}), provider.NewZoneIDFilter([]string{""}), "", false)
+
+	err := p.ApplyChanges(context.Background(), &plan.Changes{
+		Update: []*plan.Update{{
+			Old: endpoint.NewEndpointWithTTL("www.foo.com", endpoint.RecordTypeA, endpoint.TTL(300), "127.0.0.1", "127.0.0.2"),
+			New: endpoint.NewEndpointWithTTL("www.foo.com", endpoint.RecordTypeA, endpoint.TTL(600), "127.0.0.1", "127.0.0.2"),
+		}},
+	})
+	require.NoError(t, err)
+
+	// only the existing records are sent again with the new TTL, none is removed
+	require.Len(t, client.patches, 1)
+	require.Len(t, client.patches[0], 2)
+	for _, op := range client.patches[0] {
+		require.Equal(t, dns.RecordOperationOperationAdd, op.Operation)
+		require.Equal(t, 600, *op.Ttl)
+	}
+
+	endpoints, err := p.Records(context.Background())
+	require.NoError(t, err)
+	sortEndpointTargets(endpoints)
+	require.Equal(t, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("www.foo.com", endpoint.RecordTypeA, endpoint.TTL(600), "127.0.0.1", "127.0.0.2"),
+	}, endpoints)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/dns"
+	log "github.com/sirupsen/logrus"
+)
+
+// refreshingDNSClient is an ociDNSClient which, when a call is rejected because the
+// credentials have expired, rebuilds the client from fresh credentials and retries the
+// call once. Requests rejected as unauthenticated have not been applied, so retrying
+// them is safe.
+type refreshingDNSClient struct {
+	newClient func() (ociDNSClient, error)
+
+	mu     sync.Mutex
+	client ociDNSClient
+}
+
+var _ ociDNSClient = &refreshingDNSClient{}
+
+func newRefreshingDNSClient(client ociDNSClient, newClient func() (ociDNSClient, error)) *refreshingDNSClient {
+	return &refreshingDNSClient{client: client, newClient: newClient}
+}
+
+func (c *refreshingDNSClient) ListZones(ctx context.Context, request dns.ListZonesRequest) (dns.ListZonesResponse, error) {
+	return withRefresh(c, func(client ociDNSClient) (dns.ListZonesResponse, error) {
+		return client.ListZones(ctx, request)
+	})
+}
+
+func (c *refreshingDNSClient) GetZoneRecords(ctx context.Context, request dns.GetZoneRecordsRequest) (dns.GetZoneRecordsResponse, error) {
+	return withRefresh(c, func(client ociDNSClient) (dns.GetZoneRecordsResponse, error) {
+		return client.GetZoneRecords(ctx, request)
+	})
+}
+
+func (c *refreshingDNSClient) PatchZoneRecords(ctx context.Context, request dns.PatchZoneRecordsRequest) (dns.PatchZoneRecordsResponse, error) {
+	return withRefresh(c, func(client ociDNSClient) (dns.PatchZoneRecordsResponse, error) {
+		return client.PatchZoneRecords(ctx, request)
+	})
+}
+
+// current returns the client to use for the next call.
+func (c *refreshingDNSClient) current() ociDNSClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.client
+}
+
+// refresh replaces the given stale client, unless a concurrent call already did.
+func (c *refreshingDNSClient) refresh(stale ociDNSClient) (ociDNSClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client != stale {
+		return c.client, nil
+	}
+	client, err := c.newClient()
+	if err != nil {
+		return nil, err
+	}
+	c.client = client
+	return client, nil
+}
+
+// withRefresh performs the call, retrying it once with fresh credentials if they have expired.
+func withRefresh[T any](c *refreshingDNSClient, call func(ociDNSClient) (T, error)) (T, error) {
+	client := c.current()
+	response, err := call(client)
+	if !isAuthExpired(err) {
+		return response, err
+	}
+	log.Info("OCI credentials have expired, refreshing them")
+	client, refreshErr := c.refresh(client)
+	if refreshErr != nil {
+		return response, errors.Join(err, fmt.Errorf("refreshing OCI credentials: %w", refreshErr))
+	}
+	return call(client)
+}
+
+// isAuthExpired reports whether the error is the rejection of a call for lack of valid credentials.
+func isAuthExpired(err error) bool {
+	var serviceErr common.ServiceError
+	return errors.As(err, &serviceErr) && serviceErr.GetHTTPStatusCode() == http.StatusUnauthorized
+}
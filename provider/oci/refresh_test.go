@@ -0,0 +1,84 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/oracle/oci-go-sdk/v65/dns"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshingDNSClient(t *testing.T) {
+	expired := ociServiceError{status: http.StatusUnauthorized}
+	zones := []dns.ZoneSummary{testGlobalZoneSummaryFoo}
+
+	t.Run("expired credentials are refreshed", func(t *testing.T) {
+		refreshes := 0
+		c := newRefreshingDNSClient(&failingOCIDNSClient{listErr: expired}, func() (ociDNSClient, error) {
+			refreshes++
+			return newMutableMockOCIDNSClient(zones, nil), nil
+		})
+
+		resp, err := c.ListZones(context.Background(), dns.ListZonesRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Items, 1)
+		require.Equal(t, 1, refreshes)
+
+		// the refreshed client is kept
+		_, err = c.ListZones(context.Background(), dns.ListZonesRequest{})
+		require.NoError(t, err)
+		require.Equal(t, 1, refreshes)
+	})
+
+	t.Run("retried once", func(t *testing.T) {
+		refreshes := 0
+		c := newRefreshingDNSClient(&failingOCIDNSClient{listErr: expired}, func() (ociDNSClient, error) {
+			refreshes++
+			return &failingOCIDNSClient{listErr: expired}, nil
+		})
+
+		_, err := c.ListZones(context.Background(), dns.ListZonesRequest{})
+		require.ErrorIs(t, err, expired)
+		require.Equal(t, 1, refreshes)
+	})
+
+	t.Run("refresh failure", func(t *testing.T) {
+		errRefresh := errors.New("metadata service unavailable")
+		c := newRefreshingDNSClient(&failingOCIDNSClient{listErr: expired}, func() (ociDNSClient, error) {
+			return nil, errRefresh
+		})
+
+		_, err := c.ListZones(context.Background(), dns.ListZonesRequest{})
+		require.ErrorIs(t, err, expired)
+		require.ErrorIs(t, err, errRefresh)
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		throttled := ociServiceError{status: http.StatusTooManyRequests}
+		c := newRefreshingDNSClient(&failingOCIDNSClient{zoneList: zones, err: throttled}, func() (ociDNSClient, error) {
+			t.Fatal("unexpected refresh")
+			return nil, nil
+		})
+
+		_, err := c.GetZoneRecords(context.Background(), dns.GetZoneRecordsRequest{})
+		require.ErrorIs(t, err, throttled)
+	})
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// ErrMalformedOwnershipTXT is returned when the text of an ownership TXT record cannot be parsed.
+var ErrMalformedOwnershipTXT = errors.New("malformed ownership TXT record")
+
+// EncodeOwnershipTXT returns the quoted text of an ownership TXT record holding the labels, in the
+// format of the TXT registry, e.g. "heritage=external-dns,external-dns/owner=default".
+func EncodeOwnershipTXT(labels endpoint.Labels) string {
+	return labels.SerializePlain(true)
+}
+
+// DecodeOwnershipTXT returns the labels held by the text of an ownership TXT record, quoted or not.
+// Unlike the TXT registry, which skips them, tokens which are not a single key=value pair are
+// rejected with ErrMalformedOwnershipTXT. Text without the external-dns heritage is rejected with
+// endpoint.ErrInvalidHeritage.
+func DecodeOwnershipTXT(text string) (endpoint.Labels, error) {
+	unquoted := strings.Trim(strings.TrimSpace(text), "\"")
+	for _, token := range strings.Split(unquoted, ",") {
+		if key, _, _ := strings.Cut(token, "="); key == "" || strings.Count(token, "=") != 1 {
+			return nil, fmt.Errorf("%w: invalid token %q in %q", ErrMalformedOwnershipTXT, token, text)
+		}
+	}
+	return endpoint.NewLabelsFromStringPlain(unquoted)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestEncodeOwnershipTXT(t *testing.T) {
+	text := EncodeOwnershipTXT(endpoint.Labels{
+		endpoint.OwnerLabelKey:    "default",
+		endpoint.ResourceLabelKey: "service/default/my-svc",
+	})
+	assert.Equal(t, `"heritage=external-dns,external-dns/owner=default,external-dns/resource=service/default/my-svc"`, text)
+}
+
+func TestOwnershipTXTRoundTrip(t *testing.T) {
+	for _, labels := range []endpoint.Labels{
+		{},
+		{endpoint.OwnerLabelKey: "default"},
+		{endpoint.OwnerLabelKey: "default", endpoint.ResourceLabelKey: "ingress/default/my-ingress", "companion": "a"},
+	} {
+		decoded, err := DecodeOwnershipTXT(EncodeOwnershipTXT(labels))
+		require.NoError(t, err)
+		assert.Equal(t, labels, decoded)
+	}
+}
+
+func TestDecodeOwnershipTXT(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		text     string
+		expected endpoint.Labels
+		err      error
+	}{
+		{
+			name:     "unquoted",
+			text:     "heritage=external-dns,external-dns/owner=default",
+			expected: endpoint.Labels{endpoint.OwnerLabelKey: "default"},
+		},
+		{
+			name:     "quoted with surrounding spaces",
+			text:     ` "heritage=external-dns,external-dns/owner=default" `,
+			expected: endpoint.Labels{endpoint.OwnerLabelKey: "default"},
+		},
+		{
+			name:     "foreign labels are ignored",
+			text:     "heritage=external-dns,external-dns/owner=default,team=dns",
+			expected: endpoint.Labels{endpoint.OwnerLabelKey: "default"},
+		},
+		{
+			name: "empty",
+			text: "",
+			err:  ErrMalformedOwnershipTXT,
+		},
+		{
+			name: "token without value",
+			text: "heritage=external-dns,external-dns/owner",
+			err:  ErrMalformedOwnershipTXT,
+		},
+		{
+			name: "token with several values",
+			text: "heritage=external-dns,external-dns/owner=a=b",
+			err:  ErrMalformedOwnershipTXT,
+		},
+		{
+			name: "token without key",
+			text: "heritage=external-dns,=default",
+			err:  ErrMalformedOwnershipTXT,
+		},
+		{
+			name: "missing heritage",
+			text: "external-dns/owner=default",
+			err:  endpoint.ErrInvalidHeritage,
+		},
+		{
+			name: "foreign heritage",
+			text: "heritage=other,external-dns/owner=default",
+			err:  endpoint.ErrInvalidHeritage,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			labels, err := DecodeOwnershipTXT(tc.text)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, labels)
+		})
+	}
+}
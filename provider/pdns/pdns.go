@@ -26,7 +26,10 @@ import (
 	"math"
 	"net"
 	"net/http"
+	"net/url"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -44,8 +47,6 @@ type pdnsChangeType string
 const (
 	apiBase = "/api/v1"
 
-	defaultTTL = 300
-
 	// PdnsDelete and PdnsReplace are effectively an enum for "pgo.RrSet.changetype"
 	// TODO: Can we somehow get this from the pgo swagger client library itself?
 
@@ -58,16 +59,53 @@ const (
 	retryLimit = 3
 	// time in milliseconds
 	retryAfterTime = 250 * time.Millisecond
+	// Estimated size of the rrsets sent in a single PATCH request, staying well below
+	// the default webserver-max-bodysize of 2MB of PowerDNS
+	defaultMaxPatchSize = 1 << 20
+
+	// providerSpecificAlias marks CNAME endpoints managed as ALIAS rrsets below the zone apex,
+	// where CNAME endpoints are always managed as ALIAS rrsets
+	providerSpecificAlias = "alias"
 )
 
+// retryBackoff retries failed PDNS requests with a jittered exponential backoff
+var retryBackoff = provider.Backoff{Base: retryAfterTime, Attempts: retryLimit}
+
+// defaultRecordTypes are the rrset types returned by Records unless configured otherwise
+var defaultRecordTypes = []string{
+	endpoint.RecordTypeA,
+	endpoint.RecordTypeAAAA,
+	endpoint.RecordTypeCNAME,
+	endpoint.RecordTypeTXT,
+	endpoint.RecordTypeMX,
+	endpoint.RecordTypeSRV,
+	"ALIAS",
+}
+
 // PDNSConfig is comprised of the fields necessary to create a new PDNSProvider
 type PDNSConfig struct {
 	DomainFilter *endpoint.DomainFilter
-	DryRun       bool
-	Server       string
-	ServerID     string
-	APIKey       string
-	TLSConfig    TLSConfig
+	// ZoneIDFilter restricts the managed zones to the given zone IDs, after domain filtering
+	ZoneIDFilter provider.ZoneIDFilter
+	// ZoneExclusionFilter holds zones which are never managed, even if they match DomainFilter
+	ZoneExclusionFilter *endpoint.DomainFilter
+	DryRun              bool
+	CreateZones         bool
+	Server              string
+	// ServerID is the id of the PowerDNS server, discovered through the API if empty
+	ServerID  string
+	APIKey    string
+	TLSConfig TLSConfig
+	// RecordTypes are the rrset types returned by Records, defaulting to defaultRecordTypes
+	RecordTypes []string
+	// RequestTimeout bounds each API request including reading the response, 0 means no limit
+	RequestTimeout time.Duration
+	// ResponseHeaderTimeout bounds the wait for the response headers once a request is written, 0 means no limit
+	ResponseHeaderTimeout time.Duration
+	// ZoneTTLs maps zone names to the TTL in seconds forced on the records of the zone and its subdomains
+	ZoneTTLs map[string]string
+	// DefaultTTL is used for records without a TTL, defaulting to provider.DefaultTTL
+	DefaultTTL endpoint.TTL
 }
 
 // TLSConfig is comprised of the TLS-related fields necessary to create a new PDNSProvider
@@ -78,7 +116,7 @@ type TLSConfig struct {
 	ClientCertKeyFilePath string
 }
 
-func (tlsConfig *TLSConfig) setHTTPClient(pdnsClientConfig *pgo.Configuration) error {
+func (tlsConfig *TLSConfig) setHTTPClient(pdnsClientConfig *pgo.Configuration, requestTimeout, responseHeaderTimeout time.Duration) error {
 	log.Debug("Configuring TLS for PDNS Provider.")
 	tlsClientConfig, err := tlsutils.NewTLSConfig(
 		tlsConfig.ClientCertFilePath,
@@ -104,10 +142,12 @@ func (tlsConfig *TLSConfig) setHTTPClient(pdnsClientConfig *pgo.Configuration) e
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
+		ResponseHeaderTimeout: responseHeaderTimeout,
 		TLSClientConfig:       tlsClientConfig,
 	}
 	pdnsClientConfig.HTTPClient = &http.Client{
 		Transport: transporter,
+		Timeout:   requestTimeout,
 	}
 
 	return nil
@@ -127,49 +167,124 @@ func stringifyHTTPResponseBody(r *http.Response) string {
 // PDNSAPIProvider : Interface used and extended by the PDNSAPIClient struct as
 // well as mock APIClients used in testing
 type PDNSAPIProvider interface {
+	ListServers() ([]pgo.Server, *http.Response, error)
 	ListZones() ([]pgo.Zone, *http.Response, error)
 	PartitionZones(zones []pgo.Zone) ([]pgo.Zone, []pgo.Zone)
 	ListZone(zoneID string) (pgo.Zone, *http.Response, error)
 	PatchZone(zoneID string, zoneStruct pgo.Zone) (*http.Response, error)
+	CreateZone(zoneStruct pgo.Zone) (pgo.Zone, *http.Response, error)
 }
 
 // PDNSAPIClient : Struct that encapsulates all the PowerDNS specific implementation details
 type PDNSAPIClient struct {
-	dryRun       bool
-	serverID     string
-	authCtx      context.Context
-	client       *pgo.APIClient
+	dryRun bool
+	// serverID is resolved through ListServers on first use if empty
+	serverID string
+	authCtx  context.Context
+	client   *pgo.APIClient
+	// config is the configuration of client, used for requests the generated client cannot send
+	config       *pgo.Configuration
 	domainFilter *endpoint.DomainFilter
+	// zoneExclusionFilter moves matching zones into the residual set
+	zoneExclusionFilter *endpoint.DomainFilter
+	// zoneIDFilter moves zones whose ID does not match into the residual set
+	zoneIDFilter provider.ZoneIDFilter
+}
+
+// ListServers : Method returns the servers of the PowerDNS API
+// ref: https://doc.powerdns.com/authoritative/http-api/server.html#get--servers
+func (c *PDNSAPIClient) ListServers() ([]pgo.Server, *http.Response, error) {
+	var servers []pgo.Server
+	var resp *http.Response
+	err := retryBackoff.Retry(func(attempt int) error {
+		var err error
+		servers, resp, err = c.client.ServersApi.ListServers(c.authCtx)
+		if err != nil {
+			log.Debugf("Unable to fetch servers %v", err)
+			log.Debugf("Retrying ListServers() ... %d", attempt)
+		}
+		return err
+	})
+	if err != nil {
+		return servers, resp, provider.NewSoftErrorf("unable to list servers: %v", err)
+	}
+	return servers, resp, nil
+}
+
+// resolveServerID returns the configured server ID, discovering it through ListServers
+// and remembering it if none is configured.
+func (c *PDNSAPIClient) resolveServerID() (string, error) {
+	if c.serverID != "" {
+		return c.serverID, nil
+	}
+	servers, _, err := c.ListServers()
+	if err != nil {
+		return "", err
+	}
+	serverID, err := selectServerID(servers)
+	if err != nil {
+		return "", err
+	}
+	log.Infof("Using PowerDNS server %q discovered through the API", serverID)
+	c.serverID = serverID
+	return serverID, nil
+}
+
+// selectServerID picks the server to manage: the only server, or localhost if the API
+// lists several servers.
+func selectServerID(servers []pgo.Server) (string, error) {
+	ids := make([]string, 0, len(servers))
+	for _, server := range servers {
+		ids = append(ids, server.Id)
+	}
+	switch {
+	case len(ids) == 1:
+		return ids[0], nil
+	case slices.Contains(ids, "localhost"):
+		return "localhost", nil
+	case len(ids) == 0:
+		return "", errors.New("the PowerDNS API lists no servers, specify one using --pdns-server-id=")
+	default:
+		return "", fmt.Errorf("the PowerDNS API lists several servers (%s), specify one using --pdns-server-id=", strings.Join(ids, ", "))
+	}
 }
 
 // ListZones : Method returns all enabled zones from PowerDNS
 // ref: https://doc.powerdns.com/authoritative/http-api/zone.html#get--servers-server_id-zones
 func (c *PDNSAPIClient) ListZones() ([]pgo.Zone, *http.Response, error) {
+	serverID, err := c.resolveServerID()
+	if err != nil {
+		return nil, nil, err
+	}
 	var zones []pgo.Zone
 	var resp *http.Response
-	var err error
-	for i := 0; i < retryLimit; i++ {
-		zones, resp, err = c.client.ZonesApi.ListZones(c.authCtx, c.serverID)
+	err = retryBackoff.Retry(func(attempt int) error {
+		var err error
+		zones, resp, err = c.client.ZonesApi.ListZones(c.authCtx, serverID)
 		if err != nil {
 			log.Debugf("Unable to fetch zones %v", err)
-			log.Debugf("Retrying ListZones() ... %d", i)
-			time.Sleep(retryAfterTime * (1 << uint(i)))
-			continue
+			log.Debugf("Retrying ListZones() ... %d", attempt)
 		}
-		return zones, resp, err
+		return err
+	})
+	if err != nil {
+		return zones, resp, provider.NewSoftErrorf("unable to list zones: %v", err)
 	}
-
-	return zones, resp, provider.NewSoftErrorf("unable to list zones: %v", err)
+	return zones, resp, nil
 }
 
-// PartitionZones : Method returns a slice of zones that adhere to the domain filter and a slice of ones that does not adhere to the filter
+// PartitionZones : Method returns a slice of zones that adhere to the domain filter and a slice of ones that does not adhere to the filter.
+// Zones matching the zone exclusion filter or not matching the zone ID filter are always part of the residual slice.
 func (c *PDNSAPIClient) PartitionZones(zones []pgo.Zone) ([]pgo.Zone, []pgo.Zone) {
 	var filteredZones []pgo.Zone
 	var residualZones []pgo.Zone
 
-	if c.domainFilter.IsConfigured() {
+	if c.domainFilter.IsConfigured() || c.zoneExclusionFilter.IsConfigured() || c.zoneIDFilter.IsConfigured() {
 		for _, zone := range zones {
-			if c.domainFilter.Match(zone.Name) {
+			if c.zoneExclusionFilter.IsConfigured() && c.zoneExclusionFilter.Match(zone.Name) {
+				log.Debugf("Excluding zone %s because it matches the zone exclusion filter", zone.Name)
+				residualZones = append(residualZones, zone)
+			} else if c.domainFilter.Match(zone.Name) && c.zoneIDFilter.Match(zone.Id) {
 				filteredZones = append(filteredZones, zone)
 			} else {
 				residualZones = append(residualZones, zone)
@@ -184,43 +299,123 @@ func (c *PDNSAPIClient) PartitionZones(zones []pgo.Zone) ([]pgo.Zone, []pgo.Zone
 // ListZone : Method returns the details of a specific zone from PowerDNS
 // ref: https://doc.powerdns.com/authoritative/http-api/zone.html#get--servers-server_id-zones-zone_id
 func (c *PDNSAPIClient) ListZone(zoneID string) (pgo.Zone, *http.Response, error) {
-	for i := 0; i < retryLimit; i++ {
-		zone, resp, err := c.client.ZonesApi.ListZone(c.authCtx, c.serverID, zoneID)
+	serverID, err := c.resolveServerID()
+	if err != nil {
+		return pgo.Zone{}, nil, err
+	}
+	var zone pgo.Zone
+	var resp *http.Response
+	err = retryBackoff.Retry(func(attempt int) error {
+		var err error
+		zone, resp, err = c.client.ZonesApi.ListZone(c.authCtx, serverID, zoneID)
 		if err != nil {
 			log.Debugf("Unable to fetch zone %v", err)
-			log.Debugf("Retrying ListZone() ... %d", i)
-			time.Sleep(retryAfterTime * (1 << uint(i)))
-			continue
+			log.Debugf("Retrying ListZone() ... %d", attempt)
 		}
-		return zone, resp, err
+		return err
+	})
+	if err != nil {
+		return pgo.Zone{}, nil, provider.NewSoftErrorf("unable to list zone")
 	}
-
-	return pgo.Zone{}, nil, provider.NewSoftErrorf("unable to list zone")
+	return zone, resp, nil
 }
 
 // PatchZone : Method used to update the contents of a particular zone from PowerDNS
 // ref: https://doc.powerdns.com/authoritative/http-api/zone.html#patch--servers-server_id-zones-zone_id
 func (c *PDNSAPIClient) PatchZone(zoneID string, zoneStruct pgo.Zone) (*http.Response, error) {
+	serverID, err := c.resolveServerID()
+	if err != nil {
+		return nil, err
+	}
 	var resp *http.Response
-	var err error
-	for i := 0; i < retryLimit; i++ {
-		resp, err = c.client.ZonesApi.PatchZone(c.authCtx, c.serverID, zoneID, zoneStruct)
+	err = retryBackoff.Retry(func(attempt int) error {
+		var err error
+		resp, err = c.client.ZonesApi.PatchZone(c.authCtx, serverID, zoneID, zoneStruct)
 		if err != nil {
 			log.Debugf("Unable to patch zone %v", err)
-			log.Debugf("Retrying PatchZone() ... %d", i)
-			time.Sleep(retryAfterTime * (1 << uint(i)))
-			continue
+			log.Debugf("Retrying PatchZone() ... %d", attempt)
 		}
-		return resp, err
+		return err
+	})
+	if err != nil {
+		return resp, provider.NewSoftErrorf("unable to patch zone: %v", err)
 	}
+	return resp, nil
+}
 
-	return resp, provider.NewSoftErrorf("unable to patch zone: %v", err)
+// CreateZone : Method used to create a new zone in PowerDNS
+// ref: https://doc.powerdns.com/authoritative/http-api/zone.html#post--servers-server_id-zones
+func (c *PDNSAPIClient) CreateZone(zoneStruct pgo.Zone) (pgo.Zone, *http.Response, error) {
+	serverID, err := c.resolveServerID()
+	if err != nil {
+		return pgo.Zone{}, nil, err
+	}
+	var zone pgo.Zone
+	var resp *http.Response
+	err = retryBackoff.Retry(func(attempt int) error {
+		var err error
+		zone, resp, err = c.postZone(serverID, zoneStruct)
+		if err != nil {
+			log.Debugf("Unable to create zone %v", err)
+			log.Debugf("Retrying CreateZone() ... %d", attempt)
+		}
+		return err
+	})
+	if err != nil {
+		return zone, resp, provider.NewSoftErrorf("unable to create zone: %v", err)
+	}
+	return zone, resp, nil
+}
+
+// postZone sends the zone to the zone creation endpoint. The generated ZonesApi.CreateZone
+// does not send a request body, so the request is built here.
+func (c *PDNSAPIClient) postZone(serverID string, zoneStruct pgo.Zone) (pgo.Zone, *http.Response, error) {
+	body, err := json.Marshal(zoneStruct)
+	if err != nil {
+		return pgo.Zone{}, nil, err
+	}
+	req, err := http.NewRequestWithContext(c.authCtx, http.MethodPost, c.config.BasePath+"/servers/"+url.PathEscape(serverID)+"/zones", bytes.NewReader(body))
+	if err != nil {
+		return pgo.Zone{}, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if auth, ok := c.authCtx.Value(pgo.ContextAPIKey).(pgo.APIKey); ok {
+		req.Header.Set("X-API-Key", auth.Key)
+	}
+	httpClient := c.config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return pgo.Zone{}, resp, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return pgo.Zone{}, resp, fmt.Errorf("status: %s, body: %s", resp.Status, stringifyHTTPResponseBody(resp))
+	}
+	var zone pgo.Zone
+	if err := json.NewDecoder(resp.Body).Decode(&zone); err != nil {
+		return pgo.Zone{}, resp, err
+	}
+	return zone, resp, nil
 }
 
 // PDNSProvider is an implementation of the Provider interface for PowerDNS
 type PDNSProvider struct {
 	provider.BaseProvider
-	client PDNSAPIProvider
+	client              PDNSAPIProvider
+	domainFilter        *endpoint.DomainFilter
+	zoneExclusionFilter *endpoint.DomainFilter
+	createZones         bool
+	recordTypes         []string
+	// maxPatchSize bounds the estimated size of the rrsets of a PATCH request, defaulting to defaultMaxPatchSize
+	maxPatchSize int
+	// zoneTTLs maps canonical zone names to the TTL forced on the records of the zone and its subdomains
+	zoneTTLs map[string]endpoint.TTL
+	// defaultTTL is used for records without a TTL
+	defaultTTL endpoint.TTL
 }
 
 // NewPDNSProvider initializes a new PowerDNS based Provider.
@@ -241,25 +436,78 @@ func NewPDNSProvider(ctx context.Context, config PDNSConfig) (*PDNSProvider, err
 		log.Warnf("PDNS Server is set to localhost, this may not be what you want. Specify using --pdns-server=")
 	}
 
+	zoneTTLs, err := parseZoneTTLs(config.ZoneTTLs)
+	if err != nil {
+		return nil, err
+	}
+
 	pdnsClientConfig := pgo.NewConfiguration()
 	pdnsClientConfig.BasePath = config.Server + apiBase
-	if err := config.TLSConfig.setHTTPClient(pdnsClientConfig); err != nil {
+	if err := config.TLSConfig.setHTTPClient(pdnsClientConfig, config.RequestTimeout, config.ResponseHeaderTimeout); err != nil {
 		return nil, err
 	}
 
 	provider := &PDNSProvider{
 		client: &PDNSAPIClient{
-			dryRun:       config.DryRun,
-			serverID:     config.ServerID,
-			authCtx:      context.WithValue(ctx, pgo.ContextAPIKey, pgo.APIKey{Key: config.APIKey}),
-			client:       pgo.NewAPIClient(pdnsClientConfig),
-			domainFilter: config.DomainFilter,
+			dryRun:              config.DryRun,
+			serverID:            config.ServerID,
+			authCtx:             context.WithValue(ctx, pgo.ContextAPIKey, pgo.APIKey{Key: config.APIKey}),
+			client:              pgo.NewAPIClient(pdnsClientConfig),
+			config:              pdnsClientConfig,
+			domainFilter:        config.DomainFilter,
+			zoneExclusionFilter: config.ZoneExclusionFilter,
+			zoneIDFilter:        config.ZoneIDFilter,
 		},
+		domainFilter:        config.DomainFilter,
+		zoneExclusionFilter: config.ZoneExclusionFilter,
+		createZones:         config.CreateZones,
+		recordTypes:         config.RecordTypes,
+		zoneTTLs:            zoneTTLs,
+		defaultTTL:          config.DefaultTTL,
 	}
 	return provider, nil
 }
 
-func (p *PDNSProvider) convertRRSetToEndpoints(rr pgo.RrSet) ([]*endpoint.Endpoint, error) {
+// parseZoneTTLs validates the TTL overrides given per zone name and keys them by canonical zone name.
+func parseZoneTTLs(zoneTTLs map[string]string) (map[string]endpoint.TTL, error) {
+	parsed := make(map[string]endpoint.TTL, len(zoneTTLs))
+	for zone, value := range zoneTTLs {
+		ttl, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("invalid TTL %q for PDNS zone %s, must be a positive number of seconds", value, zone)
+		}
+		parsed[provider.EnsureTrailingDot(strings.ToLower(zone))] = endpoint.TTL(ttl)
+	}
+	return parsed, nil
+}
+
+// zoneTTL returns the TTL override of the longest zone containing dnsname, if any.
+func (p *PDNSProvider) zoneTTL(dnsname string) (endpoint.TTL, bool) {
+	dnsname = provider.EnsureTrailingDot(strings.ToLower(dnsname))
+	var ttl endpoint.TTL
+	zoneName := ""
+	for name, zoneTTL := range p.zoneTTLs {
+		if len(name) > len(zoneName) && (dnsname == name || strings.HasSuffix(dnsname, "."+name)) {
+			zoneName, ttl = name, zoneTTL
+		}
+	}
+	return ttl, zoneName != ""
+}
+
+// managesRecordType reports whether rrsets of the given type are returned by Records.
+func (p *PDNSProvider) managesRecordType(rrType string) bool {
+	recordTypes := p.recordTypes
+	if len(recordTypes) == 0 {
+		recordTypes = defaultRecordTypes
+	}
+	return slices.ContainsFunc(recordTypes, func(t string) bool {
+		return strings.EqualFold(t, rrType)
+	})
+}
+
+// convertRRSetToEndpoints converts an rrset of the named zone into endpoints. ALIAS rrsets are
+// read as CNAME endpoints, marked with the alias property unless they are at the zone apex.
+func (p *PDNSProvider) convertRRSetToEndpoints(rr pgo.RrSet, zoneName string) ([]*endpoint.Endpoint, error) {
 	endpoints := make([]*endpoint.Endpoint, 0)
 	targets := make([]string, 0)
 	rrType_ := rr.Type_
@@ -273,12 +521,29 @@ func (p *PDNSProvider) convertRRSetToEndpoints(rr pgo.RrSet) ([]*endpoint.Endpoi
 	if rr.Type_ == "ALIAS" {
 		rrType_ = "CNAME"
 	}
-	endpoints = append(endpoints, endpoint.NewEndpointWithTTL(rr.Name, rrType_, endpoint.TTL(rr.Ttl), targets...))
+	ep := endpoint.NewEndpointWithTTL(rr.Name, rrType_, endpoint.TTL(rr.Ttl), targets...)
+	if rr.Type_ == "ALIAS" && rr.Name != zoneName {
+		ep = ep.WithProviderSpecific(providerSpecificAlias, "true")
+	}
+	endpoints = append(endpoints, ep)
 	return endpoints, nil
 }
 
+// isAlias reports whether the endpoint is a CNAME endpoint marked to be managed as an ALIAS rrset.
+func isAlias(ep *endpoint.Endpoint) bool {
+	if ep.RecordType != endpoint.RecordTypeCNAME {
+		return false
+	}
+	value, ok := ep.GetProviderSpecificProperty(providerSpecificAlias)
+	return ok && value == "true"
+}
+
 // ConvertEndpointsToZones marshals endpoints into pdns compatible Zone structs
 func (p *PDNSProvider) ConvertEndpointsToZones(eps []*endpoint.Endpoint, changetype pdnsChangeType) ([]pgo.Zone, error) {
+	return p.convertEndpointsToZones(eps, changetype, p.createZones && changetype == PdnsReplace)
+}
+
+func (p *PDNSProvider) convertEndpointsToZones(eps []*endpoint.Endpoint, changetype pdnsChangeType, createMissingZones bool) ([]pgo.Zone, error) {
 	var zoneList = make([]pgo.Zone, 0)
 	endpoints := make([]*endpoint.Endpoint, len(eps))
 	copy(endpoints, eps)
@@ -304,6 +569,16 @@ func (p *PDNSProvider) ConvertEndpointsToZones(eps []*endpoint.Endpoint, changet
 
 	sort.SliceStable(filteredZones, func(i, j int) bool { return len(filteredZones[i].Name) > len(filteredZones[j].Name) })
 
+	// Endpoints of excluded zones must not be added to a matching parent zone
+	for i := 0; i < len(endpoints); {
+		if p.zoneExclusionFilter.IsConfigured() && p.zoneExclusionFilter.Match(endpoints[i].DNSName) {
+			log.Debugf("Ignoring Endpoint because it was matched to an excluded zone: %s", endpoints[i].DNSName)
+			endpoints = append(endpoints[0:i], endpoints[i+1:]...)
+		} else {
+			i++
+		}
+	}
+
 	// NOTE: Complexity of this loop is O(FilteredZones*Endpoints).
 	// A possibly faster implementation would be a search of the reversed
 	// DNSName in a trie of Zone names, which should be O(Endpoints), but at this point it's not
@@ -329,6 +604,8 @@ func (p *PDNSProvider) ConvertEndpointsToZones(eps []*endpoint.Endpoint, changet
 				if dnsname == zone.Name && ep.RecordType == "CNAME" {
 					log.Debugf("Converting APEX record %s from CNAME to ALIAS", dnsname)
 					RecordType_ = "ALIAS"
+				} else if isAlias(ep) {
+					RecordType_ = "ALIAS"
 				}
 
 				rrset := pgo.RrSet{
@@ -340,15 +617,14 @@ func (p *PDNSProvider) ConvertEndpointsToZones(eps []*endpoint.Endpoint, changet
 
 				// DELETEs explicitly forbid a TTL, therefore only PATCHes need the TTL
 				if changetype == PdnsReplace {
-					if int64(ep.RecordTTL) > int64(math.MaxInt32) {
-						return nil, provider.NewSoftError(fmt.Errorf("value of record TTL overflows, limited to int32"))
+					ttl := provider.TTLOrDefault(ep, p.defaultTTL)
+					if zoneTTL, ok := p.zoneTTL(dnsname); ok {
+						ttl = zoneTTL
 					}
-					if ep.RecordTTL == 0 {
-						// No TTL was specified for the record, we use the default
-						rrset.Ttl = int32(defaultTTL)
-					} else {
-						rrset.Ttl = int32(ep.RecordTTL)
+					if int64(ttl) > int64(math.MaxInt32) {
+						return nil, provider.NewSoftError(fmt.Errorf("value of record TTL overflows, limited to int32"))
 					}
+					rrset.Ttl = int32(ttl)
 				}
 
 				zone.Rrsets = append(zone.Rrsets, rrset)
@@ -380,7 +656,17 @@ func (p *PDNSProvider) ConvertEndpointsToZones(eps []*endpoint.Endpoint, changet
 			}
 		}
 	}
-	// If we still have some endpoints left, it means we couldn't find a matching zone (filtered or residual) for them
+	// If we still have some endpoints left, it means we couldn't find a matching zone (filtered or residual) for them.
+	// When zone creation is enabled, create the missing zones and match all endpoints again.
+	if len(endpoints) > 0 && createMissingZones {
+		created, err := p.createMissingZones(endpoints)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			return p.convertEndpointsToZones(eps, changetype, false)
+		}
+	}
 	// We warn instead of hard fail here because we don't want a misconfig to cause everything to go down
 	if len(endpoints) > 0 {
 		log.Warnf("No matching zones were found for the following endpoints: %+v", endpoints)
@@ -391,28 +677,95 @@ func (p *PDNSProvider) ConvertEndpointsToZones(eps []*endpoint.Endpoint, changet
 	return zoneList, nil
 }
 
+// createMissingZones creates the zones of the domain filter entries matching the given endpoints.
+// It returns true if at least one zone was created.
+func (p *PDNSProvider) createMissingZones(endpoints []*endpoint.Endpoint) (bool, error) {
+	zoneNames := map[string]bool{}
+	for _, ep := range endpoints {
+		zoneName := p.zoneNameForEndpoint(ep)
+		if zoneName == "" {
+			log.Warnf("Unable to create a zone for endpoint %s because it does not match any domain filter", ep.DNSName)
+			continue
+		}
+		zoneNames[zoneName] = true
+	}
+
+	names := make([]string, 0, len(zoneNames))
+	for name := range zoneNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		log.Infof("Creating zone %s", name)
+		_, resp, err := p.client.CreateZone(pgo.Zone{Name: name, Kind: "Native"})
+		if err != nil {
+			log.Debugf("PDNS API response: %s", stringifyHTTPResponseBody(resp))
+			return false, err
+		}
+	}
+	return len(names) > 0, nil
+}
+
+// zoneNameForEndpoint returns the longest domain filter entry matching the endpoint as a zone name.
+func (p *PDNSProvider) zoneNameForEndpoint(ep *endpoint.Endpoint) string {
+	if p.domainFilter == nil {
+		return ""
+	}
+	dnsname := provider.EnsureTrailingDot(ep.DNSName)
+	zoneName := ""
+	for _, filter := range p.domainFilter.Filters {
+		name := provider.EnsureTrailingDot(strings.TrimPrefix(filter, "."))
+		if name == "." || len(name) <= len(zoneName) {
+			continue
+		}
+		if dnsname == name || strings.HasSuffix(dnsname, "."+name) {
+			zoneName = name
+		}
+	}
+	return zoneName
+}
+
 // mutateRecords takes a list of endpoints and creates, replaces or deletes them based on the changetype
 func (p *PDNSProvider) mutateRecords(endpoints []*endpoint.Endpoint, changetype pdnsChangeType) error {
 	zonelist, err := p.ConvertEndpointsToZones(endpoints, changetype)
 	if err != nil {
 		return err
 	}
+	maxPatchSize := p.maxPatchSize
+	if maxPatchSize == 0 {
+		maxPatchSize = defaultMaxPatchSize
+	}
 	for _, zone := range zonelist {
-		jso, err := json.Marshal(zone)
-		if err != nil {
-			log.Errorf("JSON Marshal for zone struct failed!")
-		} else {
-			log.Debugf("Struct for PatchZone:\n%s", string(jso))
-		}
-		resp, err := p.client.PatchZone(zone.Id, zone)
-		if err != nil {
-			log.Debugf("PDNS API response: %s", stringifyHTTPResponseBody(resp))
-			return err
+		// split large changes into several requests to avoid oversized PATCH bodies
+		for _, rrsets := range provider.BatchBySize(zone.Rrsets, maxPatchSize, rrsetSize) {
+			patch := zone
+			patch.Rrsets = rrsets
+			jso, err := json.Marshal(patch)
+			if err != nil {
+				log.Errorf("JSON Marshal for zone struct failed!")
+			} else {
+				log.Debugf("Struct for PatchZone:\n%s", string(jso))
+			}
+			resp, err := p.client.PatchZone(patch.Id, patch)
+			if err != nil {
+				log.Debugf("PDNS API response: %s", stringifyHTTPResponseBody(resp))
+				return err
+			}
 		}
 	}
 	return nil
 }
 
+// rrsetSize estimates the size of the rrset in the body of a PATCH request.
+func rrsetSize(rrset pgo.RrSet) int {
+	jso, err := json.Marshal(rrset)
+	if err != nil {
+		return 0
+	}
+	return len(jso)
+}
+
 // Records returns all DNS records controlled by the configured PDNS server (for all zones)
 func (p *PDNSProvider) Records(_ context.Context) ([]*endpoint.Endpoint, error) {
 	zones, _, err := p.client.ListZones()
@@ -430,7 +783,10 @@ func (p *PDNSProvider) Records(_ context.Context) ([]*endpoint.Endpoint, error)
 		}
 
 		for _, rr := range z.Rrsets {
-			e, err := p.convertRRSetToEndpoints(rr)
+			if !p.managesRecordType(rr.Type_) {
+				continue
+			}
+			e, err := p.convertRRSetToEndpoints(rr, z.Name)
 			if err != nil {
 				return nil, err
 			}
@@ -443,6 +799,7 @@ func (p *PDNSProvider) Records(_ context.Context) ([]*endpoint.Endpoint, error)
 }
 
 // AdjustEndpoints performs checks on the provided endpoints and will skip any potentially failing changes.
+// The TTL of endpoints in zones with a TTL override is set to the override, as it is the TTL written.
 func (p *PDNSProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
 	var validEndpoints []*endpoint.Endpoint
 	for i := 0; i < len(endpoints); i++ {
@@ -450,6 +807,9 @@ func (p *PDNSProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpo
 			log.Warnf("Ignoring Endpoint because of invalid %v record formatting: {Target: '%v'}", endpoints[i].RecordType, endpoints[i].Targets)
 			continue
 		}
+		if ttl, ok := p.zoneTTL(endpoints[i].DNSName); ok {
+			endpoints[i].RecordTTL = ttl
+		}
 		validEndpoints = append(validEndpoints, endpoints[i])
 	}
 	return validEndpoints, nil
@@ -477,12 +837,25 @@ func (p *PDNSProvider) ApplyChanges(ctx context.Context, changes *plan.Changes)
 	}
 
 	// Update
-	for _, change := range changes.UpdateOld() {
+	var retyped []*endpoint.Endpoint
+	for _, update := range changes.Update {
 		// Since PDNS "Patches", we don't need to specify the "old"
 		// record. The Update New change type will automatically take
 		// care of replacing the old RRSet with the new one We simply
 		// leave this logging here for information
-		log.Debugf("UPDATE-OLD (ignored): %+v", change)
+		if isAlias(update.Old) == isAlias(update.New) {
+			log.Debugf("UPDATE-OLD (ignored): %+v", update.Old)
+			continue
+		}
+		// Switching between CNAME and ALIAS changes the rrset type, the old rrset must go
+		log.Infof("UPDATE-OLD: %+v", update.Old)
+		retyped = append(retyped, update.Old)
+	}
+	if len(retyped) > 0 {
+		err := p.mutateRecords(retyped, PdnsDelete)
+		if err != nil {
+			return err
+		}
 	}
 
 	updateNew := changes.UpdateNew()
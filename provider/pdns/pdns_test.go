@@ -18,16 +18,20 @@ package pdns
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	pgo "github.com/ffledgling/pdns-go"
 	"github.com/stretchr/testify/suite"
 
 	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
 	"sigs.k8s.io/external-dns/provider"
 )
 
@@ -182,7 +186,7 @@ var (
 		endpoint.NewEndpointWithTTL("cname.example.com", endpoint.RecordTypeCNAME, endpoint.TTL(300), "example.com"),
 		endpoint.NewEndpointWithTTL("example.com", endpoint.RecordTypeTXT, endpoint.TTL(300), "'would smell as sweet'"),
 		endpoint.NewEndpointWithTTL("example.com", endpoint.RecordTypeA, endpoint.TTL(300), "8.8.8.8", "8.8.4.4", "4.4.4.4"),
-		endpoint.NewEndpointWithTTL("alias.example.com", endpoint.RecordTypeCNAME, endpoint.TTL(300), "example.by.any.other.name.com"),
+		endpoint.NewEndpointWithTTL("alias.example.com", endpoint.RecordTypeCNAME, endpoint.TTL(300), "example.by.any.other.name.com").WithProviderSpecific("alias", "true"),
 		endpoint.NewEndpointWithTTL("example.com", endpoint.RecordTypeMX, endpoint.TTL(300), "10 mailhost1.example.com", "10 mailhost2.example.com"),
 		endpoint.NewEndpointWithTTL("_service._tls.example.com", endpoint.RecordTypeSRV, endpoint.TTL(300), "100 1 443 service.example.com"),
 	}
@@ -644,6 +648,14 @@ var (
 		domainFilter: DomainFilterChildListMultiple,
 	}
 
+	ZoneExclusionFilterClient = &PDNSAPIClient{
+		dryRun:              false,
+		authCtx:             context.WithValue(context.Background(), pgo.ContextAPIKey, pgo.APIKey{Key: "TEST-API-KEY"}),
+		client:              pgo.NewAPIClient(pgo.NewConfiguration()),
+		domainFilter:        DomainFilterListSingle,
+		zoneExclusionFilter: endpoint.NewDomainFilter([]string{"long.domainname.example.com"}),
+	}
+
 	RegexDomainFilterClient = &PDNSAPIClient{
 		dryRun:       false,
 		authCtx:      context.WithValue(context.Background(), pgo.ContextAPIKey, pgo.APIKey{Key: "TEST-API-KEY"}),
@@ -656,6 +668,10 @@ var (
 // API that returns a zone with multiple record types
 type PDNSAPIClientStub struct{}
 
+func (c *PDNSAPIClientStub) ListServers() ([]pgo.Server, *http.Response, error) {
+	return []pgo.Server{{Id: "localhost"}}, nil, nil
+}
+
 func (c *PDNSAPIClientStub) ListZones() ([]pgo.Zone, *http.Response, error) {
 	return []pgo.Zone{ZoneMixed}, nil, nil
 }
@@ -672,6 +688,10 @@ func (c *PDNSAPIClientStub) PatchZone(zoneID string, zoneStruct pgo.Zone) (*http
 	return &http.Response{}, nil
 }
 
+func (c *PDNSAPIClientStub) CreateZone(zoneStruct pgo.Zone) (pgo.Zone, *http.Response, error) {
+	return zoneStruct, &http.Response{}, nil
+}
+
 /******************************************************************************/
 // API that returns a zones with no records
 type PDNSAPIClientStubEmptyZones struct {
@@ -679,6 +699,10 @@ type PDNSAPIClientStubEmptyZones struct {
 	patchedZones []pgo.Zone
 }
 
+func (c *PDNSAPIClientStubEmptyZones) ListServers() ([]pgo.Server, *http.Response, error) {
+	return []pgo.Server{{Id: "localhost"}}, nil, nil
+}
+
 func (c *PDNSAPIClientStubEmptyZones) ListZones() ([]pgo.Zone, *http.Response, error) {
 	return []pgo.Zone{ZoneEmpty, ZoneEmptyLong, ZoneEmpty2}, nil, nil
 }
@@ -703,6 +727,10 @@ func (c *PDNSAPIClientStubEmptyZones) PatchZone(zoneID string, zoneStruct pgo.Zo
 	return &http.Response{}, nil
 }
 
+func (c *PDNSAPIClientStubEmptyZones) CreateZone(zoneStruct pgo.Zone) (pgo.Zone, *http.Response, error) {
+	return zoneStruct, &http.Response{}, nil
+}
+
 /******************************************************************************/
 // API that returns error on PatchZone()
 type PDNSAPIClientStubPatchZoneFailure struct {
@@ -727,6 +755,32 @@ func (c *PDNSAPIClientStubListZoneFailure) ListZone(zoneID string) (pgo.Zone, *h
 	return pgo.Zone{}, nil, provider.NewSoftError(fmt.Errorf("Generic PDNS Error"))
 }
 
+/******************************************************************************/
+// API that returns a zone with SOA and NS rrsets
+type PDNSAPIClientStubSOANS struct {
+	// Anonymous struct for composition
+	PDNSAPIClientStub
+}
+
+func (c *PDNSAPIClientStubSOANS) ListZone(zoneID string) (pgo.Zone, *http.Response, error) {
+	zone := ZoneMixed
+	zone.Rrsets = append([]pgo.RrSet{
+		{
+			Name:    "example.com.",
+			Type_:   "SOA",
+			Ttl:     3600,
+			Records: []pgo.Record{{Content: "ns1.example.com. hostmaster.example.com. 1 10800 3600 604800 3600"}},
+		},
+		{
+			Name:    "example.com.",
+			Type_:   "NS",
+			Ttl:     3600,
+			Records: []pgo.Record{{Content: "ns1.example.com."}},
+		},
+	}, zone.Rrsets...)
+	return zone, nil, nil
+}
+
 /******************************************************************************/
 // API that returns error on ListZones() (Zones - plural)
 type PDNSAPIClientStubListZonesFailure struct {
@@ -768,6 +822,36 @@ func (c *PDNSAPIClientStubPartitionZones) PartitionZones(zones []pgo.Zone) ([]pg
 	return []pgo.Zone{ZoneEmpty}, []pgo.Zone{ZoneEmptyLong, ZoneEmpty2}
 }
 
+/******************************************************************************/
+// API that partitions zones using a zone exclusion filter
+type PDNSAPIClientStubZoneExclusion struct {
+	// Anonymous struct for composition
+	PDNSAPIClientStubEmptyZones
+}
+
+func (c *PDNSAPIClientStubZoneExclusion) PartitionZones(zones []pgo.Zone) ([]pgo.Zone, []pgo.Zone) {
+	return ZoneExclusionFilterClient.PartitionZones(zones)
+}
+
+/******************************************************************************/
+// API that only lists zones once they have been created
+type PDNSAPIClientStubCreateZone struct {
+	// Anonymous struct for composition
+	PDNSAPIClientStubEmptyZones
+	zones        []pgo.Zone
+	createdZones []pgo.Zone
+}
+
+func (c *PDNSAPIClientStubCreateZone) ListZones() ([]pgo.Zone, *http.Response, error) {
+	return c.zones, nil, nil
+}
+
+func (c *PDNSAPIClientStubCreateZone) CreateZone(zoneStruct pgo.Zone) (pgo.Zone, *http.Response, error) {
+	c.createdZones = append(c.createdZones, zoneStruct)
+	c.zones = append(c.zones, ZoneEmpty)
+	return ZoneEmpty, &http.Response{}, nil
+}
+
 /******************************************************************************/
 
 type NewPDNSProviderTestSuite struct {
@@ -853,8 +937,111 @@ func (suite *NewPDNSProviderTestSuite) TestPDNSProviderCreateTLS() {
 	}), "Enabled TLS Config with all flags should raise no error")
 }
 
+func (suite *NewPDNSProviderTestSuite) TestPDNSProviderCreateTimeouts() {
+	pdnsClientConfig := pgo.NewConfiguration()
+	suite.NoError((&TLSConfig{}).setHTTPClient(pdnsClientConfig, time.Minute, 45*time.Second))
+	suite.Equal(time.Minute, pdnsClientConfig.HTTPClient.Timeout)
+	transport, ok := pdnsClientConfig.HTTPClient.Transport.(*http.Transport)
+	suite.Require().True(ok)
+	suite.Equal(45*time.Second, transport.ResponseHeaderTimeout)
+	suite.Equal(10*time.Second, transport.TLSHandshakeTimeout)
+
+	pdnsClientConfig = pgo.NewConfiguration()
+	suite.NoError((&TLSConfig{}).setHTTPClient(pdnsClientConfig, 0, 0))
+	suite.Zero(pdnsClientConfig.HTTPClient.Timeout, "Unset request timeout should not limit requests")
+}
+
+func (suite *NewPDNSProviderTestSuite) TestPDNSServerIDAutoResolved() {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case apiBase + "/servers":
+			_, _ = w.Write([]byte(`[{"type": "Server", "id": "pdns-1", "daemon_type": "authoritative"}]`))
+		case apiBase + "/servers/pdns-1/zones":
+			_, _ = w.Write([]byte(`[{"id": "example.com.", "name": "example.com.", "kind": "Native"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p, err := NewPDNSProvider(
+		context.Background(),
+		PDNSConfig{
+			Server:       server.URL,
+			APIKey:       "foo",
+			DomainFilter: endpoint.NewDomainFilter([]string{""}),
+		})
+	suite.Require().NoError(err)
+
+	zones, _, err := p.client.ListZones()
+	suite.Require().NoError(err)
+	suite.Require().Len(zones, 1)
+	suite.Equal("example.com.", zones[0].Name)
+	_, _, err = p.client.ListZones()
+	suite.Require().NoError(err)
+	suite.Equal([]string{
+		apiBase + "/servers",
+		apiBase + "/servers/pdns-1/zones",
+		apiBase + "/servers/pdns-1/zones",
+	}, requested, "the server ID should be discovered once")
+}
+
+func (suite *NewPDNSProviderTestSuite) TestPDNSClientCreateZone() {
+	var method, apiKey string
+	var body pgo.Zone
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != apiBase+"/servers/localhost/zones" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		method = r.Method
+		apiKey = r.Header.Get("X-API-Key")
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": "example.com.", "name": "example.com.", "kind": "Native"}`))
+	}))
+	defer server.Close()
+
+	p, err := NewPDNSProvider(
+		context.Background(),
+		PDNSConfig{
+			Server:       server.URL,
+			ServerID:     "localhost",
+			APIKey:       "foo",
+			DomainFilter: endpoint.NewDomainFilter([]string{""}),
+		})
+	suite.Require().NoError(err)
+
+	zone, _, err := p.client.CreateZone(pgo.Zone{Name: "example.com.", Kind: "Native"})
+	suite.Require().NoError(err)
+	suite.Equal("example.com.", zone.Id)
+	suite.Equal(http.MethodPost, method)
+	suite.Equal("foo", apiKey)
+	suite.Equal(pgo.Zone{Name: "example.com.", Kind: "Native"}, body, "the zone should be sent as the request body")
+}
+
+func (suite *NewPDNSProviderTestSuite) TestPDNSSelectServerID() {
+	serverID, err := selectServerID([]pgo.Server{{Id: "pdns-1"}})
+	suite.NoError(err)
+	suite.Equal("pdns-1", serverID)
+
+	serverID, err = selectServerID([]pgo.Server{{Id: "pdns-1"}, {Id: "localhost"}})
+	suite.NoError(err)
+	suite.Equal("localhost", serverID)
+
+	_, err = selectServerID([]pgo.Server{{Id: "pdns-1"}, {Id: "pdns-2"}})
+	suite.ErrorContains(err, "pdns-1, pdns-2")
+
+	_, err = selectServerID(nil)
+	suite.Error(err)
+}
+
 func (suite *NewPDNSProviderTestSuite) TestPDNSRRSetToEndpoints() {
-	// Function definition: convertRRSetToEndpoints(rr pgo.RrSet) (endpoints []*endpoint.Endpoint, _ error)
+	// Function definition: convertRRSetToEndpoints(rr pgo.RrSet, zoneName string) (endpoints []*endpoint.Endpoint, _ error)
 
 	// Create a new provider to run tests against
 	p := &PDNSProvider{
@@ -864,7 +1051,7 @@ func (suite *NewPDNSProviderTestSuite) TestPDNSRRSetToEndpoints() {
 	/* given an RRSet with three records, we test:
 	   - We correctly create corresponding endpoints
 	*/
-	eps, err := p.convertRRSetToEndpoints(RRSetMultipleRecords)
+	eps, err := p.convertRRSetToEndpoints(RRSetMultipleRecords, "example.com.")
 	suite.Require().NoError(err)
 	suite.Equal(endpointsMultipleRecords, eps)
 
@@ -872,7 +1059,7 @@ func (suite *NewPDNSProviderTestSuite) TestPDNSRRSetToEndpoints() {
 	   - We can correctly convert the RRSet into a list of valid endpoints
 	   - We correctly discard/ignore the disabled record.
 	*/
-	eps, err = p.convertRRSetToEndpoints(RRSetDisabledRecord)
+	eps, err = p.convertRRSetToEndpoints(RRSetDisabledRecord, "example.com.")
 	suite.Require().NoError(err)
 	suite.Equal(endpointsDisabledRecord, eps)
 }
@@ -910,6 +1097,59 @@ func (suite *NewPDNSProviderTestSuite) TestPDNSRecords() {
 	suite.ErrorIs(err, provider.SoftError)
 }
 
+func (suite *NewPDNSProviderTestSuite) TestPDNSAliasRecords() {
+	p := &PDNSProvider{
+		client: &PDNSAPIClientStub{},
+	}
+
+	// An ALIAS rrset read back is desired as a CNAME with the alias annotation, no change follows
+	current, err := p.Records(context.Background())
+	suite.Require().NoError(err)
+	changes := (&plan.Plan{
+		Current: current,
+		Desired: []*endpoint.Endpoint{
+			endpoint.NewEndpointWithTTL("alias.example.com", endpoint.RecordTypeCNAME, endpoint.TTL(300), "example.by.any.other.name.com").WithProviderSpecific("alias", "true"),
+		},
+		DomainFilter:   endpoint.MatchAllDomainFilters{endpoint.NewDomainFilter([]string{"alias.example.com"})},
+		ManagedRecords: []string{endpoint.RecordTypeCNAME},
+	}).Calculate().Changes
+	suite.False(changes.HasChanges(), "an unchanged ALIAS should not be updated")
+
+	// Turning a CNAME into an ALIAS removes the CNAME rrset before adding the ALIAS rrset
+	stub := &PDNSAPIClientStubEmptyZones{}
+	p = &PDNSProvider{
+		client: stub,
+	}
+	cname := endpoint.NewEndpointWithTTL("alias.example.com", endpoint.RecordTypeCNAME, endpoint.TTL(300), "example.by.any.other.name.com")
+	err = p.ApplyChanges(context.Background(), &plan.Changes{
+		Update: []*plan.Update{{Old: cname, New: cname.DeepCopy().WithProviderSpecific("alias", "true")}},
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(stub.patchedZones, 2)
+	suite.Equal("CNAME", stub.patchedZones[0].Rrsets[0].Type_)
+	suite.Equal(string(PdnsDelete), stub.patchedZones[0].Rrsets[0].Changetype)
+	suite.Equal("ALIAS", stub.patchedZones[1].Rrsets[0].Type_)
+	suite.Equal(string(PdnsReplace), stub.patchedZones[1].Rrsets[0].Changetype)
+}
+
+func (suite *NewPDNSProviderTestSuite) TestPDNSRecordsRecordTypes() {
+	// SOA and NS rrsets are not returned by default
+	p := &PDNSProvider{
+		client: &PDNSAPIClientStubSOANS{},
+	}
+	eps, err := p.Records(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal(endpointsMixedRecords, eps)
+
+	// Only the configured record types are returned
+	p.recordTypes = []string{"NS"}
+	eps, err = p.Records(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal([]*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("example.com", endpoint.RecordTypeNS, endpoint.TTL(3600), "ns1.example.com"),
+	}, eps)
+}
+
 func (suite *NewPDNSProviderTestSuite) TestPDNSConvertEndpointsToZones() {
 	// Function definition: ConvertEndpointsToZones(endpoints []*endpoint.Endpoint, changetype pdnsChangeType) (zonelist []pgo.Zone, _ error)
 
@@ -1064,6 +1304,38 @@ func (suite *NewPDNSProviderTestSuite) TestPDNSmutateRecords() {
 	suite.ErrorIs(err, provider.SoftError)
 }
 
+func (suite *NewPDNSProviderTestSuite) TestPDNSmutateRecordsBatchedBySize() {
+	c := &PDNSAPIClientStubEmptyZones{}
+	p := &PDNSProvider{
+		client:       c,
+		maxPatchSize: 2500,
+	}
+
+	large := "\"" + strings.Repeat("x", 900) + "\""
+	endpoints := []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("a.example.com", endpoint.RecordTypeTXT, endpoint.TTL(300), large),
+		endpoint.NewEndpointWithTTL("b.example.com", endpoint.RecordTypeTXT, endpoint.TTL(300), large),
+		endpoint.NewEndpointWithTTL("c.example.com", endpoint.RecordTypeTXT, endpoint.TTL(300), large),
+		endpoint.NewEndpointWithTTL("d.example.com", endpoint.RecordTypeA, endpoint.TTL(300), "8.8.8.8"),
+	}
+
+	err := p.mutateRecords(endpoints, PdnsReplace)
+	suite.Require().NoError(err)
+	suite.Len(c.patchedZones, 2, "Large TXT records should be split into several PATCH requests")
+
+	var names []string
+	for _, zone := range c.patchedZones {
+		suite.Equal("example.com.", zone.Id)
+		size := 0
+		for _, rrset := range zone.Rrsets {
+			names = append(names, rrset.Name)
+			size += rrsetSize(rrset)
+		}
+		suite.LessOrEqual(size, p.maxPatchSize)
+	}
+	suite.ElementsMatch([]string{"a.example.com.", "b.example.com.", "c.example.com.", "d.example.com."}, names)
+}
+
 func (suite *NewPDNSProviderTestSuite) TestPDNSClientPartitionZones() {
 	zoneList := []pgo.Zone{
 		ZoneEmpty,
@@ -1113,6 +1385,169 @@ func (suite *NewPDNSProviderTestSuite) TestPDNSClientPartitionZones() {
 	suite.Equal(partitionResultResidualSingleFilter, residualZones)
 }
 
+func (suite *NewPDNSProviderTestSuite) TestPDNSClientPartitionZonesRegex() {
+	zoneList := []pgo.Zone{
+		ZoneEmpty,
+		ZoneEmptyLong,
+		ZoneEmpty2,
+	}
+
+	c := &PDNSAPIClient{
+		domainFilter: endpoint.NewRegexDomainFilter(regexp.MustCompile(`^(.+\.)?example\.com$`), nil),
+	}
+
+	// Regex inclusion keeps every zone matching the expression
+	filteredZones, residualZones := c.PartitionZones(zoneList)
+	suite.Equal([]pgo.Zone{ZoneEmpty, ZoneEmptyLong}, filteredZones)
+	suite.Equal([]pgo.Zone{ZoneEmpty2}, residualZones)
+
+	// Regex exclusion takes precedence over the inclusion expression
+	c.domainFilter = endpoint.NewRegexDomainFilter(regexp.MustCompile(`(example\.com|mock\.test)$`), regexp.MustCompile(`^long\.`))
+	filteredZones, residualZones = c.PartitionZones(zoneList)
+	suite.Equal([]pgo.Zone{ZoneEmpty, ZoneEmpty2}, filteredZones)
+	suite.Equal([]pgo.Zone{ZoneEmptyLong}, residualZones)
+}
+
+func (suite *NewPDNSProviderTestSuite) TestPDNSClientPartitionZonesExclusion() {
+	zoneList := []pgo.Zone{
+		ZoneEmpty,
+		ZoneEmptyLong,
+		ZoneEmpty2,
+	}
+
+	// Excluded subzones end up in the residual zones even though they match the domain filter
+	filteredZones, residualZones := ZoneExclusionFilterClient.PartitionZones(zoneList)
+	suite.Equal([]pgo.Zone{ZoneEmpty}, filteredZones)
+	suite.Equal([]pgo.Zone{ZoneEmptyLong, ZoneEmpty2}, residualZones)
+}
+
+func (suite *NewPDNSProviderTestSuite) TestPDNSClientPartitionZonesZoneIDFilter() {
+	zoneList := []pgo.Zone{
+		ZoneEmpty,
+		ZoneEmptyLong,
+		ZoneEmpty2,
+	}
+
+	c := &PDNSAPIClient{
+		zoneIDFilter: provider.NewZoneIDFilter([]string{"mock.test."}),
+	}
+
+	// Only the zone selected by ID is managed
+	filteredZones, residualZones := c.PartitionZones(zoneList)
+	suite.Equal([]pgo.Zone{ZoneEmpty2}, filteredZones)
+	suite.Equal([]pgo.Zone{ZoneEmpty, ZoneEmptyLong}, residualZones)
+
+	// The zone ID filter is applied after domain filtering
+	c.domainFilter = endpoint.NewDomainFilter([]string{"example.com"})
+	filteredZones, residualZones = c.PartitionZones(zoneList)
+	suite.Empty(filteredZones)
+	suite.Equal(zoneList, residualZones)
+}
+
+func (suite *NewPDNSProviderTestSuite) TestPDNSConvertEndpointsToZonesZoneExclusion() {
+	p := &PDNSProvider{
+		client:              &PDNSAPIClientStubZoneExclusion{},
+		zoneExclusionFilter: ZoneExclusionFilterClient.zoneExclusionFilter,
+	}
+
+	// Check endpoints of a zone which is not excluded are still converted
+	zlist, err := p.ConvertEndpointsToZones(endpointsSimpleRecord, PdnsReplace)
+	suite.Require().NoError(err)
+	suite.Equal([]pgo.Zone{ZoneEmptyToSimplePatch}, zlist)
+
+	// Check endpoints of an excluded subzone are ignored instead of being added to the parent zone
+	zlist, err = p.ConvertEndpointsToZones(endpointsLongRecord, PdnsReplace)
+	suite.Require().NoError(err)
+	suite.Empty(zlist)
+}
+
+func (suite *NewPDNSProviderTestSuite) TestPDNSConvertEndpointsToZonesZoneTTL() {
+	zoneTTLs, err := parseZoneTTLs(map[string]string{"Mock.Test": "3600"})
+	suite.Require().NoError(err)
+	p := &PDNSProvider{
+		client:   &PDNSAPIClientStubEmptyZones{},
+		zoneTTLs: zoneTTLs,
+	}
+
+	// Check the zone override replaces the TTL of the endpoints, other zones keep theirs
+	zlist, err := p.ConvertEndpointsToZones(endpointsMultipleZones, PdnsReplace)
+	suite.Require().NoError(err)
+	ttls := map[string]int32{}
+	for _, zone := range zlist {
+		for _, rrset := range zone.Rrsets {
+			ttls[rrset.Name+" "+rrset.Type_] = rrset.Ttl
+		}
+	}
+	suite.Equal(map[string]int32{
+		"example.com. A":   300,
+		"example.com. TXT": 300,
+		"mock.test. A":     3600,
+		"mock.test. TXT":   3600,
+	}, ttls)
+
+	// Check desired endpoints carry the override so they match the records read back
+	adjusted, err := p.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("sub.mock.test", endpoint.RecordTypeA, endpoint.TTL(60), "9.9.9.9"),
+		endpoint.NewEndpointWithTTL("example.com", endpoint.RecordTypeA, endpoint.TTL(60), "8.8.8.8"),
+	})
+	suite.Require().NoError(err)
+	suite.Equal(endpoint.TTL(3600), adjusted[0].RecordTTL)
+	suite.Equal(endpoint.TTL(60), adjusted[1].RecordTTL)
+}
+
+func (suite *NewPDNSProviderTestSuite) TestPDNSConvertEndpointsToZonesDefaultTTL() {
+	p := &PDNSProvider{
+		client:     &PDNSAPIClientStubEmptyZones{},
+		defaultTTL: 120,
+	}
+
+	zlist, err := p.ConvertEndpointsToZones([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeA, "8.8.8.8"),
+		endpoint.NewEndpointWithTTL("mock.test", endpoint.RecordTypeA, endpoint.TTL(60), "9.9.9.9"),
+	}, PdnsReplace)
+	suite.Require().NoError(err)
+	ttls := map[string]int32{}
+	for _, zone := range zlist {
+		for _, rrset := range zone.Rrsets {
+			ttls[rrset.Name] = rrset.Ttl
+		}
+	}
+	suite.Equal(map[string]int32{"example.com.": 120, "mock.test.": 60}, ttls)
+}
+
+func (suite *NewPDNSProviderTestSuite) TestPDNSParseZoneTTLs() {
+	zoneTTLs, err := parseZoneTTLs(map[string]string{"example.com": "300", "mock.test.": "60"})
+	suite.Require().NoError(err)
+	suite.Equal(map[string]endpoint.TTL{"example.com.": 300, "mock.test.": 60}, zoneTTLs)
+
+	for _, value := range []string{"", "0", "-1", "5m", "4294967296"} {
+		_, err := parseZoneTTLs(map[string]string{"example.com": value})
+		suite.Error(err, value)
+	}
+}
+
+func (suite *NewPDNSProviderTestSuite) TestPDNSmutateRecordsCreateZones() {
+	c := &PDNSAPIClientStubCreateZone{}
+	p := &PDNSProvider{
+		client:       c,
+		domainFilter: endpoint.NewDomainFilter([]string{"example.com"}),
+		createZones:  true,
+	}
+
+	// Check the missing zone is created and the endpoints are patched into it
+	err := p.mutateRecords(endpointsSimpleRecord, PdnsReplace)
+	suite.Require().NoError(err)
+	suite.Equal([]pgo.Zone{{Name: "example.com.", Kind: "Native"}}, c.createdZones)
+	suite.Equal([]pgo.Zone{ZoneEmptyToSimplePatch}, c.patchedZones)
+
+	// Check zones are not created for endpoints outside of the domain filter
+	c.patchedZones = nil
+	err = p.mutateRecords(endpointsNonexistantZone, PdnsReplace)
+	suite.Require().NoError(err)
+	suite.Len(c.createdZones, 1)
+	suite.Empty(c.patchedZones)
+}
+
 // Validate whether invalid endpoints are removed by AdjustEndpoints
 func (suite *NewPDNSProviderTestSuite) TestPDNSAdjustEndpoints() {
 	// Function definition: AdjustEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"slices"
 	"strings"
 
 	"sigs.k8s.io/external-dns/endpoint"
@@ -55,6 +56,25 @@ type Provider interface {
 	// Endpoints. It is permitted to modify the supplied endpoints.
 	AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error)
 	GetDomainFilter() endpoint.DomainFilterInterface
+	// SupportedRecordTypes returns the record types the provider can manage.
+	// It returns nil if the record types are not known.
+	SupportedRecordTypes() []string
+}
+
+// DefaultTTL is the TTL, in seconds, of records whose endpoint does not specify one
+// and for which no other default has been configured.
+const DefaultTTL endpoint.TTL = 300
+
+// TTLOrDefault returns the TTL configured on the endpoint. If the endpoint does not
+// specify one, defaultTTL is returned, or DefaultTTL if defaultTTL is not configured either.
+func TTLOrDefault(ep *endpoint.Endpoint, defaultTTL endpoint.TTL) endpoint.TTL {
+	if ep.RecordTTL.IsConfigured() {
+		return ep.RecordTTL
+	}
+	if defaultTTL.IsConfigured() {
+		return defaultTTL
+	}
+	return DefaultTTL
 }
 
 type BaseProvider struct{}
@@ -67,6 +87,10 @@ func (b BaseProvider) GetDomainFilter() endpoint.DomainFilterInterface {
 	return &endpoint.DomainFilter{}
 }
 
+func (b BaseProvider) SupportedRecordTypes() []string {
+	return slices.Clone(defaultRecordTypes)
+}
+
 type contextKey struct {
 	name string
 }
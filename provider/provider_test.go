@@ -23,6 +23,8 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/external-dns/endpoint"
 )
 
 func TestMain(m *testing.M) {
@@ -54,3 +56,33 @@ func TestDifference(t *testing.T) {
 	assert.Equal(t, []string{"foo"}, remove)
 	assert.Equal(t, []string{"bar"}, leave)
 }
+
+func TestTTLOrDefault(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		ttl        endpoint.TTL
+		defaultTTL endpoint.TTL
+		expected   endpoint.TTL
+	}{
+		{"endpoint TTL wins", 60, 120, 60},
+		{"configured default", 0, 120, 120},
+		{"provider default", 0, 0, DefaultTTL},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ep := endpoint.NewEndpointWithTTL("example.org", endpoint.RecordTypeA, tc.ttl, "1.2.3.4")
+			assert.Equal(t, tc.expected, TTLOrDefault(ep, tc.defaultTTL))
+		})
+	}
+}
+
+func TestBaseProviderSupportedRecordTypes(t *testing.T) {
+	types := BaseProvider{}.SupportedRecordTypes()
+	assert.ElementsMatch(t, []string{"A", "AAAA", "CNAME", "SRV", "TXT", "NS"}, types)
+	for _, recordType := range types {
+		assert.True(t, SupportedRecordType(recordType))
+	}
+
+	// the returned slice may be modified by the caller
+	types[0] = "MX"
+	assert.Contains(t, BaseProvider{}.SupportedRecordTypes(), "A")
+}
@@ -16,13 +16,24 @@ limitations under the License.
 
 package provider
 
+import (
+	"slices"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// defaultRecordTypes are the record types supported by providers not reporting their own.
+var defaultRecordTypes = []string{
+	endpoint.RecordTypeA,
+	endpoint.RecordTypeAAAA,
+	endpoint.RecordTypeCNAME,
+	endpoint.RecordTypeSRV,
+	endpoint.RecordTypeTXT,
+	endpoint.RecordTypeNS,
+}
+
 // SupportedRecordType returns true only for supported record types.
 // Currently A, AAAA, CNAME, SRV, TXT and NS record types are supported.
 func SupportedRecordType(recordType string) bool {
-	switch recordType {
-	case "A", "AAAA", "CNAME", "SRV", "TXT", "NS":
-		return true
-	default:
-		return false
-	}
+	return slices.Contains(defaultRecordTypes, recordType)
 }
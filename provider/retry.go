@@ -0,0 +1,77 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff retries operations with exponential backoff and jitter, so that
+// clients failing at the same time don't retry in lockstep.
+type Backoff struct {
+	// Base is the delay before the first retry, doubled for every further retry.
+	Base time.Duration
+	// Max caps the delay before a retry. Zero means no cap.
+	Max time.Duration
+	// Attempts is the maximum number of attempts, including the first one.
+	Attempts int
+
+	// sleep is overridden in tests.
+	sleep func(time.Duration)
+}
+
+// Delay returns the delay before the given retry, counted from zero. It is
+// drawn uniformly from the upper half of the exponential delay, [d/2, d].
+func (b Backoff) Delay(retry int) time.Duration {
+	d := b.Base
+	for range retry {
+		if d > math.MaxInt64/2 || (b.Max > 0 && d >= b.Max) {
+			break
+		}
+		d *= 2
+	}
+	if b.Max > 0 && d > b.Max {
+		d = b.Max
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// Retry calls fn until it succeeds or the attempts are exhausted, sleeping
+// between attempts. fn is passed the attempt number, counted from zero. The
+// error of the last attempt is returned.
+func (b Backoff) Retry(fn func(attempt int) error) error {
+	sleep := b.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	var err error
+	for attempt := range max(b.Attempts, 1) {
+		if attempt > 0 {
+			sleep(b.Delay(attempt - 1))
+		}
+		if err = fn(attempt); err == nil {
+			return nil
+		}
+	}
+	return err
+}
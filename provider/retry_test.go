@@ -0,0 +1,92 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoffRetryAttempts(t *testing.T) {
+	errFailed := errors.New("failed")
+	for _, tc := range []struct {
+		name          string
+		attempts      int
+		failures      int
+		expectedCalls int
+		expectedErr   error
+	}{
+		{name: "success", attempts: 3, failures: 0, expectedCalls: 1},
+		{name: "success after retries", attempts: 3, failures: 2, expectedCalls: 3},
+		{name: "attempts exhausted", attempts: 3, failures: 5, expectedCalls: 3, expectedErr: errFailed},
+		{name: "zero attempts", attempts: 0, failures: 5, expectedCalls: 1, expectedErr: errFailed},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var sleeps []time.Duration
+			b := Backoff{
+				Base:     100 * time.Millisecond,
+				Attempts: tc.attempts,
+				sleep:    func(d time.Duration) { sleeps = append(sleeps, d) },
+			}
+			var calls []int
+			err := b.Retry(func(attempt int) error {
+				calls = append(calls, attempt)
+				if attempt < tc.failures {
+					return errFailed
+				}
+				return nil
+			})
+			assert.Equal(t, tc.expectedErr, err)
+			require.Len(t, calls, tc.expectedCalls)
+			for i, attempt := range calls {
+				assert.Equal(t, i, attempt)
+			}
+			// no sleep before the first attempt nor after the last one
+			assert.Len(t, sleeps, tc.expectedCalls-1)
+		})
+	}
+}
+
+func TestBackoffDelayBounds(t *testing.T) {
+	b := Backoff{Base: 100 * time.Millisecond, Max: time.Second}
+	for retry, upper := range []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	} {
+		for range 100 {
+			d := b.Delay(retry)
+			assert.GreaterOrEqual(t, d, upper/2, "retry %d", retry)
+			assert.LessOrEqual(t, d, upper, "retry %d", retry)
+		}
+	}
+}
+
+func TestBackoffDelayOverflow(t *testing.T) {
+	b := Backoff{Base: time.Hour}
+	d := b.Delay(100)
+	assert.Positive(t, d)
+
+	assert.Zero(t, Backoff{}.Delay(3))
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+var (
+	// ErrInvalidIPv4Target is returned for A records with a target that is not an IPv4 address.
+	ErrInvalidIPv4Target = errors.New("target is not an IPv4 address")
+	// ErrInvalidIPv6Target is returned for AAAA records with a target that is not an IPv6 address.
+	ErrInvalidIPv6Target = errors.New("target is not an IPv6 address")
+	// ErrInvalidCNAMETargets is returned for CNAME records without exactly one target.
+	ErrInvalidCNAMETargets = errors.New("CNAME records must have exactly one target")
+	// ErrInvalidMXTarget is returned for MX records with a malformed target.
+	ErrInvalidMXTarget = errors.New("invalid MX record target")
+	// ErrInvalidSRVTarget is returned for SRV records with a malformed target.
+	ErrInvalidSRVTarget = errors.New("invalid SRV record target")
+)
+
+// RejectedEndpoint is an endpoint rejected by ValidateEndpoints along with the reason.
+type RejectedEndpoint struct {
+	Endpoint *endpoint.Endpoint
+	Err      error
+}
+
+// ValidateEndpoints checks that the targets of each endpoint are consistent with its
+// record type. It returns the valid endpoints and the rejected ones, logging a warning
+// for each rejection.
+func ValidateEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, []RejectedEndpoint) {
+	valid := make([]*endpoint.Endpoint, 0, len(endpoints))
+	var rejected []RejectedEndpoint
+	for _, ep := range endpoints {
+		if err := validateEndpoint(ep); err != nil {
+			log.WithFields(log.Fields{
+				"dnsName":    ep.DNSName,
+				"recordType": ep.RecordType,
+				"targets":    ep.Targets,
+			}).Warnf("Ignoring invalid endpoint: %v", err)
+			rejected = append(rejected, RejectedEndpoint{Endpoint: ep, Err: err})
+			continue
+		}
+		valid = append(valid, ep)
+	}
+	return valid, rejected
+}
+
+func validateEndpoint(ep *endpoint.Endpoint) error {
+	switch ep.RecordType {
+	case endpoint.RecordTypeA:
+		for _, target := range ep.Targets {
+			if addr, err := netip.ParseAddr(target); err != nil || !addr.Is4() {
+				return fmt.Errorf("%w: %q", ErrInvalidIPv4Target, target)
+			}
+		}
+	case endpoint.RecordTypeAAAA:
+		for _, target := range ep.Targets {
+			if addr, err := netip.ParseAddr(target); err != nil || !addr.Is6() || addr.Is4In6() {
+				return fmt.Errorf("%w: %q", ErrInvalidIPv6Target, target)
+			}
+		}
+	case endpoint.RecordTypeCNAME:
+		if len(ep.Targets) != 1 {
+			return fmt.Errorf("%w, got %d", ErrInvalidCNAMETargets, len(ep.Targets))
+		}
+	case endpoint.RecordTypeMX:
+		for _, target := range ep.Targets {
+			if _, err := endpoint.NewMXRecord(target); err != nil {
+				return fmt.Errorf("%w: %q", ErrInvalidMXTarget, target)
+			}
+		}
+	case endpoint.RecordTypeSRV:
+		for _, target := range ep.Targets {
+			if !(endpoint.Targets{target}).ValidateSRVRecord() {
+				return fmt.Errorf("%w: %q", ErrInvalidSRVTarget, target)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestValidateEndpoints(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		endpoint *endpoint.Endpoint
+		err      error
+	}{
+		{
+			name:     "valid A record",
+			endpoint: endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.2.3.4", "1.2.3.5"),
+		},
+		{
+			name:     "A record with IPv6 target",
+			endpoint: endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.2.3.4", "2001:db8::1"),
+			err:      ErrInvalidIPv4Target,
+		},
+		{
+			name:     "A record with hostname target",
+			endpoint: endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "foo.example.com"),
+			err:      ErrInvalidIPv4Target,
+		},
+		{
+			name:     "valid AAAA record",
+			endpoint: endpoint.NewEndpoint("aaaa.example.com", endpoint.RecordTypeAAAA, "2001:db8::1"),
+		},
+		{
+			name:     "AAAA record with IPv4 target",
+			endpoint: endpoint.NewEndpoint("aaaa.example.com", endpoint.RecordTypeAAAA, "1.2.3.4"),
+			err:      ErrInvalidIPv6Target,
+		},
+		{
+			name:     "AAAA record with IPv4-mapped target",
+			endpoint: endpoint.NewEndpoint("aaaa.example.com", endpoint.RecordTypeAAAA, "::ffff:1.2.3.4"),
+			err:      ErrInvalidIPv6Target,
+		},
+		{
+			name:     "valid CNAME record",
+			endpoint: endpoint.NewEndpoint("cname.example.com", endpoint.RecordTypeCNAME, "foo.example.com"),
+		},
+		{
+			name:     "CNAME record with multiple targets",
+			endpoint: endpoint.NewEndpoint("cname.example.com", endpoint.RecordTypeCNAME, "foo.example.com", "bar.example.com"),
+			err:      ErrInvalidCNAMETargets,
+		},
+		{
+			name:     "CNAME record without target",
+			endpoint: endpoint.NewEndpoint("cname.example.com", endpoint.RecordTypeCNAME),
+			err:      ErrInvalidCNAMETargets,
+		},
+		{
+			name:     "valid MX record",
+			endpoint: endpoint.NewEndpoint("mx.example.com", endpoint.RecordTypeMX, "10 mail.example.com"),
+		},
+		{
+			name:     "MX record without preference",
+			endpoint: endpoint.NewEndpoint("mx.example.com", endpoint.RecordTypeMX, "mail.example.com"),
+			err:      ErrInvalidMXTarget,
+		},
+		{
+			name:     "valid SRV record",
+			endpoint: endpoint.NewEndpoint("_sip._tcp.example.com", endpoint.RecordTypeSRV, "10 5 5060 sip.example.com"),
+		},
+		{
+			name:     "SRV record without port",
+			endpoint: endpoint.NewEndpoint("_sip._tcp.example.com", endpoint.RecordTypeSRV, "10 5 sip.example.com"),
+			err:      ErrInvalidSRVTarget,
+		},
+		{
+			name:     "TXT record",
+			endpoint: endpoint.NewEndpoint("txt.example.com", endpoint.RecordTypeTXT, "v=spf1 -all", "foo"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			valid, rejected := ValidateEndpoints([]*endpoint.Endpoint{tc.endpoint})
+			if tc.err == nil {
+				assert.Equal(t, []*endpoint.Endpoint{tc.endpoint}, valid)
+				assert.Empty(t, rejected)
+				return
+			}
+			assert.Empty(t, valid)
+			require.Len(t, rejected, 1)
+			assert.Same(t, tc.endpoint, rejected[0].Endpoint)
+			assert.ErrorIs(t, rejected[0].Err, tc.err)
+		})
+	}
+}
+
+func TestValidateEndpointsKeepsOrder(t *testing.T) {
+	a := endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.2.3.4")
+	invalid := endpoint.NewEndpoint("b.example.com", endpoint.RecordTypeA, "2001:db8::1")
+	c := endpoint.NewEndpoint("c.example.com", endpoint.RecordTypeCNAME, "a.example.com")
+
+	valid, rejected := ValidateEndpoints([]*endpoint.Endpoint{a, invalid, c})
+	assert.Equal(t, []*endpoint.Endpoint{a, c}, valid)
+	require.Len(t, rejected, 1)
+	assert.Same(t, invalid, rejected[0].Endpoint)
+}
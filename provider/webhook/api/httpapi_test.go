@@ -73,6 +73,10 @@ func (p FakeWebhookProvider) GetDomainFilter() endpoint.DomainFilterInterface {
 	return p.domainFilter
 }
 
+func (p FakeWebhookProvider) SupportedRecordTypes() []string {
+	return nil
+}
+
 func TestMain(m *testing.M) {
 	records = []*endpoint.Endpoint{
 		{
@@ -297,6 +297,11 @@ func (p WebhookProvider) GetDomainFilter() endpoint.DomainFilterInterface {
 	return p.DomainFilter
 }
 
+// SupportedRecordTypes returns nil, the webhook API does not expose the record types of the remote provider
+func (p WebhookProvider) SupportedRecordTypes() []string {
+	return nil
+}
+
 // isRetryableError returns true for HTTP status codes between 500 and 510 (inclusive)
 func isRetryableError(statusCode int) bool {
 	return statusCode >= http.StatusInternalServerError && statusCode <= http.StatusNotExtended
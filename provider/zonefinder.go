@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"sort"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
@@ -42,7 +43,43 @@ func (z ZoneIDName) Add(zoneID, zoneName string) {
 // IDNA-aware and cannot represent non-ASCII labels. Skipping these labels
 // ensures compatibility with such use cases.
 func (z ZoneIDName) FindZone(hostname string) (string, string) {
-	var name string
+	matches := z.FindZones(hostname)
+	if len(matches) == 0 {
+		return "", ""
+	}
+	return matches[0].ID, matches[0].Name
+}
+
+// ZoneMatch is a zone whose name is a suffix of a hostname.
+type ZoneMatch struct {
+	ID   string
+	Name string
+}
+
+// FindZones returns every zone the hostname belongs to, sorted from the longest
+// zone name to the shortest, so the first entry is the one FindZone picks.
+// More than one match means the hostname falls into overlapping zones.
+func (z ZoneIDName) FindZones(hostname string) []ZoneMatch {
+	name := toUnicodeHostname(hostname)
+
+	var matches []ZoneMatch
+	for zoneID, zoneName := range z {
+		if name == zoneName || strings.HasSuffix(name, "."+zoneName) {
+			matches = append(matches, ZoneMatch{ID: zoneID, Name: zoneName})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if len(matches[i].Name) != len(matches[j].Name) {
+			return len(matches[i].Name) > len(matches[j].Name)
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	return matches
+}
+
+// toUnicodeHostname converts the labels of a hostname to their Unicode form,
+// leaving labels that contain underscores untouched.
+func toUnicodeHostname(hostname string) string {
 	domainLabels := strings.Split(hostname, ".")
 	for i, label := range domainLabels {
 		if strings.Contains(label, "_") {
@@ -55,17 +92,5 @@ func (z ZoneIDName) FindZone(hostname string) (string, string) {
 		}
 		domainLabels[i] = convertedLabel
 	}
-	name = strings.Join(domainLabels, ".")
-
-	var suitableZoneID, suitableZoneName string
-
-	for zoneID, zoneName := range z {
-		if name == zoneName || strings.HasSuffix(name, "."+zoneName) {
-			if suitableZoneName == "" || len(zoneName) > len(suitableZoneName) {
-				suitableZoneID = zoneID
-				suitableZoneName = zoneName
-			}
-		}
-	}
-	return suitableZoneID, suitableZoneName
+	return strings.Join(domainLabels, ".")
 }
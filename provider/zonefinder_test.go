@@ -82,3 +82,31 @@ func TestZoneIDName(t *testing.T) {
 
 	testutils.TestHelperLogContains("Failed to convert label \"???\" of hostname \"???\" to its Unicode form: idna: disallowed rune U+003F", hook, t)
 }
+
+func TestZoneIDNameFindZones(t *testing.T) {
+	z := ZoneIDName{}
+	z.Add("1", "example.com")
+	z.Add("2", "sub.example.com")
+	z.Add("3", "deep.sub.example.com")
+	z.Add("4", "other.com")
+	z.Add("5", "sub.example.com")
+
+	// overlapping zones are sorted from the longest name to the shortest
+	assert.Equal(t, []ZoneMatch{
+		{ID: "3", Name: "deep.sub.example.com"},
+		{ID: "2", Name: "sub.example.com"},
+		{ID: "5", Name: "sub.example.com"},
+		{ID: "1", Name: "example.com"},
+	}, z.FindZones("www.deep.sub.example.com"))
+
+	zoneID, zoneName := z.FindZone("www.deep.sub.example.com")
+	assert.Equal(t, "3", zoneID)
+	assert.Equal(t, "deep.sub.example.com", zoneName)
+
+	// a single zone
+	assert.Equal(t, []ZoneMatch{{ID: "4", Name: "other.com"}}, z.FindZones("www.other.com"))
+
+	// no zone
+	assert.Empty(t, z.FindZones("www.example.org"))
+	assert.Empty(t, z.FindZones("notexample.com"))
+}
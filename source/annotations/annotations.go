@@ -30,6 +30,7 @@ const (
 
 	AWSPrefix        = AnnotationKeyPrefix + "aws-"
 	SCWPrefix        = AnnotationKeyPrefix + "scw-"
+	CoreDNSPrefix    = AnnotationKeyPrefix + "coredns-"
 	WebhookPrefix    = AnnotationKeyPrefix + "webhook-"
 	CloudflarePrefix = AnnotationKeyPrefix + "cloudflare-"
 
@@ -39,7 +40,11 @@ const (
 
 	SetIdentifierKey = AnnotationKeyPrefix + "set-identifier"
 	AliasKey         = AnnotationKeyPrefix + "alias"
+	// The annotation used for naming the health check of the records, mapped to the AWS health check property
+	HealthCheckIDKey = AnnotationKeyPrefix + "healthcheck-id"
 	TargetKey        = AnnotationKeyPrefix + "target"
+	// The annotation used for naming the Service, as name or namespace/name, whose load balancer provides the targets of an ingress
+	TargetServiceKey = AnnotationKeyPrefix + "target-service"
 	// The annotation used for figuring out which controller is responsible
 	ControllerKey = AnnotationKeyPrefix + "controller"
 	// The annotation used for defining the desired hostname
@@ -64,6 +64,16 @@ func TestProviderSpecificAnnotations(t *testing.T) {
 			},
 			setIdentifier: "",
 		},
+		{
+			name: "Health check annotation",
+			annotations: map[string]string{
+				HealthCheckIDKey: "abc-123",
+			},
+			expected: endpoint.ProviderSpecific{
+				{Name: "aws/health-check-id", Value: "abc-123"},
+			},
+			setIdentifier: "",
+		},
 		{
 			name: "Set identifier annotation",
 			annotations: map[string]string{
@@ -300,6 +310,15 @@ func TestGetProviderSpecificIdentifierAnnotations(t *testing.T) {
 			},
 			expectedIdentifier: "id1",
 		},
+		{
+			title: "coredns- provider specific annotations are set correctly",
+			annotations: map[string]string{
+				"external-dns.alpha.kubernetes.io/coredns-group": "web",
+			},
+			expectedResult: map[string]string{
+				"coredns/group": "web",
+			},
+		},
 		{
 			title: "webhook- provider specific annotations are set correctly",
 			annotations: map[string]string{
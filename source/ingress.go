@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"text/template"
@@ -27,8 +28,8 @@ import (
 	log "github.com/sirupsen/logrus"
 	networkv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/apimachinery/pkg/selection"
 	kubeinformers "k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	netinformers "k8s.io/client-go/informers/networking/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -46,6 +47,10 @@ const (
 	IngressHostnameSourceDefinedHostsOnlyValue = "defined-hosts-only"
 
 	IngressClassAnnotationKey = "kubernetes.io/ingress.class"
+
+	// Possible values for the handling of ingresses without an address
+	IngressPendingAddressSkip = "skip"
+	IngressPendingAddressWait = "wait"
 )
 
 // ingressSource is an implementation of Source for Kubernetes ingress objects.
@@ -56,6 +61,7 @@ type ingressSource struct {
 	client                   kubernetes.Interface
 	namespace                string
 	annotationFilter         string
+	annotationFilters        map[string]string // per-namespace overrides of annotationFilter
 	ingressClassNames        []string
 	fqdnTemplate             *template.Template
 	combineFQDNAnnotation    bool
@@ -64,29 +70,78 @@ type ingressSource struct {
 	ignoreIngressTLSSpec     bool
 	ignoreIngressRulesSpec   bool
 	labelSelector            labels.Selector
+	cacheEndpoints           bool
+	// endpoints generated by the last call to Endpoints and the key of the ingresses they were generated from
+	cachedEndpoints    []*endpoint.Endpoint
+	cachedEndpointsKey string
+	// how to handle ingresses that have neither a load balancer address nor a target annotation
+	pendingAddressPolicy string
+	// endpoints last generated per ingress, kept for ingresses waiting for an address
+	lastIngressEndpoints map[string][]*endpoint.Endpoint
+	// informer for the ingress classes providing default TTLs, nil if disabled
+	ingressClassInformer netinformers.IngressClassInformer
+	// domain of the records generated from the default backend service of ingresses without hosts, empty if disabled
+	defaultBackendDomain string
+	// informer for the services named by target service annotations, nil if disabled
+	serviceInformer coreinformers.ServiceInformer
+}
+
+// IngressConfig is comprised of the fields necessary to create a new ingressSource
+type IngressConfig struct {
+	Namespace                string
+	AnnotationFilter         string
+	FQDNTemplate             string
+	CombineFQDNAnnotation    bool
+	IgnoreHostnameAnnotation bool
+	IgnoreIngressTLSSpec     bool
+	IgnoreIngressRulesSpec   bool
+	LabelSelector            labels.Selector
+	IngressClassNames        []string
+	// AnnotationFilters are per-namespace overrides of AnnotationFilter
+	AnnotationFilters map[string]string
+	// CacheEndpoints reuses the endpoints of the last call to Endpoints while the ingresses are unchanged
+	CacheEndpoints bool
+	// PendingAddressPolicy handles ingresses without an address or target annotation, defaulting to IngressPendingAddressSkip
+	PendingAddressPolicy string
+	// IngressClassTTL reads default TTLs from the ingress classes, which requires permission to list and watch them
+	IngressClassTTL bool
+	// TargetService reads targets from the services named by target service annotations, which requires permission to list and watch them
+	TargetService bool
+	// DefaultBackendDomain is the domain of the records generated from the default backend service of ingresses without hosts, empty if disabled
+	DefaultBackendDomain string
 }
 
 // NewIngressSource creates a new ingressSource with the given config.
-func NewIngressSource(
-	ctx context.Context,
-	kubeClient kubernetes.Interface,
-	namespace, annotationFilter, fqdnTemplate string,
-	combineFqdnAnnotation, ignoreHostnameAnnotation, ignoreIngressTLSSpec, ignoreIngressRulesSpec bool,
-	labelSelector labels.Selector,
-	ingressClassNames []string) (Source, error) {
-	tmpl, err := fqdn.ParseTemplate(fqdnTemplate)
+func NewIngressSource(ctx context.Context, kubeClient kubernetes.Interface, config IngressConfig) (Source, error) {
+	tmpl, err := fqdn.ParseTemplate(config.FQDNTemplate)
 	if err != nil {
 		return nil, err
 	}
 
-	// ensure that ingress class is only set in either the ingressClassNames or
-	// annotationFilter but not both
-	if ingressClassNames != nil && annotationFilter != "" {
-		selector, err := getLabelSelector(annotationFilter)
+	pendingAddressPolicy := config.PendingAddressPolicy
+	switch pendingAddressPolicy {
+	case "":
+		pendingAddressPolicy = IngressPendingAddressSkip
+	case IngressPendingAddressSkip, IngressPendingAddressWait:
+	default:
+		return nil, fmt.Errorf("invalid ingress pending address policy %q, must be %q or %q", pendingAddressPolicy, IngressPendingAddressSkip, IngressPendingAddressWait)
+	}
+
+	filters := []string{config.AnnotationFilter}
+	for _, filter := range config.AnnotationFilters {
+		filters = append(filters, filter)
+	}
+	for _, filter := range filters {
+		selector, err := getLabelSelector(filter)
 		if err != nil {
 			return nil, err
 		}
 
+		// ensure that ingress class is only set in either the ingressClassNames or
+		// annotationFilter but not both
+		if config.IngressClassNames == nil {
+			continue
+		}
 		requirements, _ := selector.Requirements()
 		for _, requirement := range requirements {
 			if requirement.Key() == "kubernetes.io/ingress.class" {
@@ -96,7 +151,7 @@ func NewIngressSource(
 	}
 	// Use shared informer to listen for add/update/delete of ingresses in the specified namespace.
 	// Set resync period to 0, to prevent processing when nothing has changed.
-	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace(namespace))
+	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace(config.Namespace))
 	ingressInformer := informerFactory.Networking().V1().Ingresses()
 
 	// Add default resource event handlers to properly initialize informer.
@@ -107,6 +162,32 @@ func NewIngressSource(
 		},
 	)
 
+	// Services are only watched if they provide the targets of ingresses, as this requires
+	// permission to list and watch them.
+	var serviceInformer coreinformers.ServiceInformer
+	if config.TargetService {
+		serviceInformer = informerFactory.Core().V1().Services()
+		serviceInformer.Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc: func(obj interface{}) {
+				},
+			},
+		)
+	}
+
+	// IngressClasses are only watched if they provide default TTLs, as this requires
+	// permission to list and watch them.
+	var ingressClassInformer netinformers.IngressClassInformer
+	if config.IngressClassTTL {
+		ingressClassInformer = informerFactory.Networking().V1().IngressClasses()
+		ingressClassInformer.Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc: func(obj interface{}) {
+				},
+			},
+		)
+	}
+
 	informerFactory.Start(ctx.Done())
 
 	// wait for the local cache to be populated.
@@ -116,38 +197,60 @@ func NewIngressSource(
 
 	sc := &ingressSource{
 		client:                   kubeClient,
-		namespace:                namespace,
-		annotationFilter:         annotationFilter,
-		ingressClassNames:        ingressClassNames,
+		namespace:                config.Namespace,
+		annotationFilter:         config.AnnotationFilter,
+		annotationFilters:        config.AnnotationFilters,
+		ingressClassNames:        config.IngressClassNames,
 		fqdnTemplate:             tmpl,
-		combineFQDNAnnotation:    combineFqdnAnnotation,
-		ignoreHostnameAnnotation: ignoreHostnameAnnotation,
+		combineFQDNAnnotation:    config.CombineFQDNAnnotation,
+		ignoreHostnameAnnotation: config.IgnoreHostnameAnnotation,
 		ingressInformer:          ingressInformer,
-		ignoreIngressTLSSpec:     ignoreIngressTLSSpec,
-		ignoreIngressRulesSpec:   ignoreIngressRulesSpec,
-		labelSelector:            labelSelector,
+		ignoreIngressTLSSpec:     config.IgnoreIngressTLSSpec,
+		ignoreIngressRulesSpec:   config.IgnoreIngressRulesSpec,
+		labelSelector:            config.LabelSelector,
+		cacheEndpoints:           config.CacheEndpoints,
+		pendingAddressPolicy:     pendingAddressPolicy,
+		lastIngressEndpoints:     map[string][]*endpoint.Endpoint{},
+		ingressClassInformer:     ingressClassInformer,
+		defaultBackendDomain:     strings.Trim(config.DefaultBackendDomain, "."),
+		serviceInformer:          serviceInformer,
 	}
 	return sc, nil
 }
 
 // Endpoints returns endpoint objects for each host-target combination that should be processed.
 // Retrieves all ingress resources on all namespaces
-func (sc *ingressSource) Endpoints(_ context.Context) ([]*endpoint.Endpoint, error) {
+func (sc *ingressSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	ingresses, err := sc.ingressInformer.Lister().Ingresses(sc.namespace).List(sc.labelSelector)
 	if err != nil {
 		return nil, err
 	}
-	ingresses, err = sc.filterByAnnotations(ingresses)
-	if err != nil {
-		return nil, err
+
+	var cacheKey string
+	if sc.cacheEndpoints {
+		cacheKey = ingressesCacheKey(ingresses)
+		if cacheKey != "" && sc.ingressClassInformer != nil {
+			classes, err := sc.ingressClassInformer.Lister().List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			cacheKey = ingressClassesCacheKey(cacheKey, classes)
+		}
+		if cacheKey != "" && cacheKey == sc.cachedEndpointsKey {
+			log.Debugf("Ingresses have not changed, reusing %d cached endpoints", len(sc.cachedEndpoints))
+			return copyEndpoints(sc.cachedEndpoints), nil
+		}
 	}
 
-	ingresses, err = sc.filterByIngressClass(ingresses)
+	ingresses, err = sc.filterByAnnotations(ingresses)
 	if err != nil {
 		return nil, err
 	}
 
+	ingresses = sc.filterByIngressClass(ingresses)
+
 	endpoints := []*endpoint.Endpoint{}
+	lastIngressEndpoints := map[string][]*endpoint.Endpoint{}
 
 	for _, ing := range ingresses {
 		// Check the controller annotation to see if we are responsible.
@@ -157,16 +260,35 @@ func (sc *ingressSource) Endpoints(_ context.Context) ([]*endpoint.Endpoint, err
 			continue
 		}
 
-		ingEndpoints := endpointsFromIngress(ing, sc.ignoreHostnameAnnotation, sc.ignoreIngressTLSSpec, sc.ignoreIngressRulesSpec)
+		ingKey := ing.Namespace + "/" + ing.Name
+		serviceTargets := sc.targetsFromTargetService(ing)
+		if !hasIngressTargets(ing) && len(serviceTargets) == 0 {
+			last, ok := sc.lastIngressEndpoints[ingKey]
+			if sc.pendingAddressPolicy != IngressPendingAddressWait || !ok {
+				log.Debugf("Skipping ingress %s/%s because it has no load balancer address and no target annotation", ing.Namespace, ing.Name)
+				continue
+			}
+			log.Debugf("Keeping the endpoints of ingress %s/%s until it is assigned a load balancer address", ing.Namespace, ing.Name)
+			lastIngressEndpoints[ingKey] = last
+			endpoints = append(endpoints, copyEndpoints(last)...)
+			continue
+		}
+
+		ingEndpoints := endpointsFromIngress(ing, sc.ignoreHostnameAnnotation, sc.ignoreIngressTLSSpec, sc.ignoreIngressRulesSpec, sc.ingressClassTTL(ing), serviceTargets)
+
+		// name the default backend if the ingress has no host
+		if len(ingEndpoints) == 0 {
+			ingEndpoints = sc.endpointsFromDefaultBackend(ing, serviceTargets)
+		}
 
 		// apply template if host is missing on ingress
 		if (sc.combineFQDNAnnotation || len(ingEndpoints) == 0) && sc.fqdnTemplate != nil {
-			iEndpoints, err := sc.endpointsFromTemplate(ing)
+			iEndpoints, err := sc.endpointsFromTemplate(ing, serviceTargets)
 			if err != nil {
 				return nil, err
 			}
 
-			ingEndpoints = append(ingEndpoints, iEndpoints...)
+			ingEndpoints = mergeTemplateEndpoints(ingEndpoints, iEndpoints)
 		}
 
 		if len(ingEndpoints) == 0 {
@@ -175,27 +297,133 @@ func (sc *ingressSource) Endpoints(_ context.Context) ([]*endpoint.Endpoint, err
 		}
 
 		log.Debugf("Endpoints generated from ingress: %s/%s: %v", ing.Namespace, ing.Name, ingEndpoints)
+		if sc.pendingAddressPolicy == IngressPendingAddressWait {
+			lastIngressEndpoints[ingKey] = copyEndpoints(ingEndpoints)
+		}
 		endpoints = append(endpoints, ingEndpoints...)
 	}
+	sc.lastIngressEndpoints = lastIngressEndpoints
 
 	for _, ep := range endpoints {
 		sort.Sort(ep.Targets)
 	}
 
+	if sc.cacheEndpoints {
+		sc.cachedEndpoints = copyEndpoints(endpoints)
+		sc.cachedEndpointsKey = cacheKey
+	}
+
 	return endpoints, nil
 }
 
-func (sc *ingressSource) endpointsFromTemplate(ing *networkv1.Ingress) ([]*endpoint.Endpoint, error) {
+// ingressesCacheKey returns a key identifying the given versions of the ingresses. It returns
+// an empty key, disabling the cache, if any ingress lacks a resource version or takes its
+// targets from a service, whose changes the key does not track.
+func ingressesCacheKey(ingresses []*networkv1.Ingress) string {
+	keys := make([]string, 0, len(ingresses))
+	for _, ing := range ingresses {
+		if ing.ResourceVersion == "" || ing.Annotations[targetServiceAnnotationKey] != "" {
+			return ""
+		}
+		keys = append(keys, ing.Namespace+"/"+ing.Name+"/"+string(ing.UID)+"/"+ing.ResourceVersion)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// ingressClassesCacheKey extends the cache key of the ingresses with the versions of the
+// ingress classes, whose annotations provide default TTLs.
+func ingressClassesCacheKey(key string, classes []*networkv1.IngressClass) string {
+	keys := make([]string, 0, len(classes))
+	for _, class := range classes {
+		keys = append(keys, class.Name+"/"+string(class.UID)+"/"+class.ResourceVersion)
+	}
+	sort.Strings(keys)
+	return key + ";" + strings.Join(keys, ",")
+}
+
+// ingressClassTTL returns the TTL annotated on the class of the ingress, used for endpoints
+// whose ingress does not carry a TTL annotation itself. It returns 0 if class TTLs are
+// disabled or the class does not carry a TTL annotation.
+func (sc *ingressSource) ingressClassTTL(ing *networkv1.Ingress) endpoint.TTL {
+	if sc.ingressClassInformer == nil {
+		return 0
+	}
+	className := ingressClassName(ing)
+	if className == "" {
+		return 0
+	}
+	class, err := sc.ingressClassInformer.Lister().Get(className)
+	if err != nil {
+		log.Debugf("Unable to get ingress class %s of ingress %s/%s: %v", className, ing.Namespace, ing.Name, err)
+		return 0
+	}
+	return annotations.TTLFromAnnotations(class.Annotations, "ingressclass/"+class.Name)
+}
+
+// targetsFromTargetService returns the load balancer targets of the service named by the target
+// service annotation of the ingress, given as name or namespace/name and defaulting to the
+// namespace of the ingress. It returns no targets if the annotation is missing or the service
+// cannot be found among the services watched in the namespace of the source.
+func (sc *ingressSource) targetsFromTargetService(ing *networkv1.Ingress) endpoint.Targets {
+	service := ing.Annotations[targetServiceAnnotationKey]
+	if service == "" || sc.serviceInformer == nil {
+		return nil
+	}
+	namespace, name := ing.Namespace, service
+	if ns, n, ok := strings.Cut(service, "/"); ok {
+		namespace, name = ns, n
+	}
+	svc, err := sc.serviceInformer.Lister().Services(namespace).Get(name)
+	if err != nil {
+		log.Warnf("Unable to get target service %s/%s of ingress %s/%s: %v", namespace, name, ing.Namespace, ing.Name, err)
+		return nil
+	}
+	return extractLoadBalancerTargets(svc, false)
+}
+
+// copyEndpoints deep copies endpoints so that callers cannot modify the cached ones.
+func copyEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	result := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		result = append(result, ep.DeepCopy())
+	}
+	return result
+}
+
+func (sc *ingressSource) endpointsFromTemplate(ing *networkv1.Ingress, serviceTargets endpoint.Targets) ([]*endpoint.Endpoint, error) {
 	hostnames, err := fqdn.ExecTemplate(sc.fqdnTemplate, ing)
 	if err != nil {
 		return nil, err
 	}
+	return sc.endpointsForHostnames(ing, hostnames, serviceTargets), nil
+}
+
+// endpointsFromDefaultBackend returns the endpoints named <service>.<domain> after the default
+// backend service of the ingress, if a default backend domain is configured.
+func (sc *ingressSource) endpointsFromDefaultBackend(ing *networkv1.Ingress, serviceTargets endpoint.Targets) []*endpoint.Endpoint {
+	if sc.defaultBackendDomain == "" || ing.Spec.DefaultBackend == nil || ing.Spec.DefaultBackend.Service == nil {
+		return nil
+	}
+	hostname := ing.Spec.DefaultBackend.Service.Name + "." + sc.defaultBackendDomain
+	return sc.endpointsForHostnames(ing, []string{hostname}, serviceTargets)
+}
 
+// endpointsForHostnames returns the endpoints of the given hostnames pointing to the targets of the ingress.
+// The targets, TTL, provider specific properties and set identifier are chosen as for the hosts of the
+// ingress rules, so that generated endpoints only differ from rule endpoints in their names.
+func (sc *ingressSource) endpointsForHostnames(ing *networkv1.Ingress, hostnames []string, serviceTargets endpoint.Targets) []*endpoint.Endpoint {
 	resource := fmt.Sprintf("ingress/%s/%s", ing.Namespace, ing.Name)
 
 	ttl := annotations.TTLFromAnnotations(ing.Annotations, resource)
+	if !ttl.IsConfigured() {
+		ttl = sc.ingressClassTTL(ing)
+	}
 
 	targets := annotations.TargetsFromTargetAnnotation(ing.Annotations)
+	if len(targets) == 0 {
+		targets = serviceTargets
+	}
 	if len(targets) == 0 {
 		targets = targetsFromIngressStatus(ing.Status)
 	}
@@ -206,26 +434,60 @@ func (sc *ingressSource) endpointsFromTemplate(ing *networkv1.Ingress) ([]*endpo
 	for _, hostname := range hostnames {
 		endpoints = append(endpoints, EndpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier, resource)...)
 	}
-	return endpoints, nil
+	return endpoints
+}
+
+// mergeTemplateEndpoints appends the endpoints generated from the FQDN template to the
+// endpoints defined on the ingress itself. Endpoints defined on the ingress take precedence:
+// a template endpoint is dropped if an endpoint with the same name, record type and set
+// identifier already exists, so that the result contains no duplicates.
+func mergeTemplateEndpoints(defined, templated []*endpoint.Endpoint) []*endpoint.Endpoint {
+	seen := make(map[endpoint.EndpointKey]struct{}, len(defined))
+	for _, ep := range defined {
+		seen[endpoint.EndpointKey{DNSName: ep.DNSName, RecordType: ep.RecordType, SetIdentifier: ep.SetIdentifier}] = struct{}{}
+	}
+
+	for _, ep := range templated {
+		key := endpoint.EndpointKey{DNSName: ep.DNSName, RecordType: ep.RecordType, SetIdentifier: ep.SetIdentifier}
+		if _, ok := seen[key]; ok {
+			log.Debugf("Skipping template endpoint %s %s because it is already defined on the ingress", ep.DNSName, ep.RecordType)
+			continue
+		}
+		seen[key] = struct{}{}
+		defined = append(defined, ep)
+	}
+	return defined
 }
 
 // filterByAnnotations filters a list of ingresses by a given annotation selector.
+// The annotation filter of an ingress's namespace takes precedence over the default one.
 func (sc *ingressSource) filterByAnnotations(ingresses []*networkv1.Ingress) ([]*networkv1.Ingress, error) {
 	selector, err := getLabelSelector(sc.annotationFilter)
 	if err != nil {
 		return nil, err
 	}
 
-	// empty filter returns original list
-	if selector.Empty() {
+	// empty filters return original list
+	if selector.Empty() && len(sc.annotationFilters) == 0 {
 		return ingresses, nil
 	}
 
+	namespaceSelectors := make(map[string]labels.Selector, len(sc.annotationFilters))
+	for namespace, filter := range sc.annotationFilters {
+		if namespaceSelectors[namespace], err = getLabelSelector(filter); err != nil {
+			return nil, err
+		}
+	}
+
 	filteredList := []*networkv1.Ingress{}
 
 	for _, ingress := range ingresses {
+		ingressSelector, ok := namespaceSelectors[ingress.Namespace]
+		if !ok {
+			ingressSelector = selector
+		}
 		// include ingress if its annotations match the selector
-		if matchLabelSelector(selector, ingress.Annotations) {
+		if matchLabelSelector(ingressSelector, ingress.Annotations) {
 			filteredList = append(filteredList, ingress)
 		}
 	}
@@ -234,64 +496,66 @@ func (sc *ingressSource) filterByAnnotations(ingresses []*networkv1.Ingress) ([]
 }
 
 // filterByIngressClass filters a list of ingresses based on a required ingress
-// class
-func (sc *ingressSource) filterByIngressClass(ingresses []*networkv1.Ingress) ([]*networkv1.Ingress, error) {
+// class, as decided by ingressClassName.
+func (sc *ingressSource) filterByIngressClass(ingresses []*networkv1.Ingress) []*networkv1.Ingress {
 	// if no class filter is specified then there's nothing to do
 	if len(sc.ingressClassNames) == 0 {
-		return ingresses, nil
-	}
-
-	classNameReq, err := labels.NewRequirement(IngressClassAnnotationKey, selection.In, sc.ingressClassNames)
-	if err != nil {
-		return nil, err
+		return ingresses
 	}
 
-	selector := labels.NewSelector()
-	selector = selector.Add(*classNameReq)
-
 	filteredList := []*networkv1.Ingress{}
 
 	for _, ingress := range ingresses {
-		var matched = false
-
-		for _, nameFilter := range sc.ingressClassNames {
-			if ingress.Spec.IngressClassName != nil && len(*ingress.Spec.IngressClassName) > 0 {
-				if nameFilter == *ingress.Spec.IngressClassName {
-					matched = true
-				}
-			} else if matchLabelSelector(selector, ingress.Annotations) {
-				matched = true
-			}
-
-			if matched {
-				filteredList = append(filteredList, ingress)
-				break
-			}
-		}
-
-		if !matched {
+		if !slices.Contains(sc.ingressClassNames, ingressClassName(ingress)) {
 			log.Debugf("Discarding ingress %s/%s because it does not match required ingress classes %v", ingress.Namespace, ingress.Name, sc.ingressClassNames)
+			continue
 		}
+		filteredList = append(filteredList, ingress)
 	}
 
-	return filteredList, nil
+	return filteredList
+}
+
+// ingressClassName returns the class of the ingress. The spec field takes precedence over
+// the legacy annotation, which only decides the class of ingresses without the spec field.
+func ingressClassName(ing *networkv1.Ingress) string {
+	annotated := ing.Annotations[IngressClassAnnotationKey]
+	if ing.Spec.IngressClassName == nil || *ing.Spec.IngressClassName == "" {
+		return annotated
+	}
+	className := *ing.Spec.IngressClassName
+	if annotated != "" && annotated != className {
+		log.Debugf("Ignoring ingress class annotation %s of ingress %s/%s in favour of its ingressClassName %s", annotated, ing.Namespace, ing.Name, className)
+	}
+	return className
 }
 
-// endpointsFromIngress extracts the endpoints from ingress object
-func endpointsFromIngress(ing *networkv1.Ingress, ignoreHostnameAnnotation bool, ignoreIngressTLSSpec bool, ignoreIngressRulesSpec bool) []*endpoint.Endpoint {
+// endpointsFromIngress extracts the endpoints from ingress object. The TTL annotation of the
+// ingress takes precedence over classTTL, the TTL annotated on its ingress class. The target
+// annotation takes precedence over serviceTargets, the targets of the service named by the
+// target service annotation, which take precedence over the load balancer status.
+func endpointsFromIngress(ing *networkv1.Ingress, ignoreHostnameAnnotation bool, ignoreIngressTLSSpec bool, ignoreIngressRulesSpec bool, classTTL endpoint.TTL, serviceTargets endpoint.Targets) []*endpoint.Endpoint {
 	resource := fmt.Sprintf("ingress/%s/%s", ing.Namespace, ing.Name)
 
 	ttl := annotations.TTLFromAnnotations(ing.Annotations, resource)
+	if !ttl.IsConfigured() {
+		ttl = classTTL
+	}
 
 	targets := annotations.TargetsFromTargetAnnotation(ing.Annotations)
 
+	if len(targets) == 0 {
+		targets = serviceTargets
+	}
 	if len(targets) == 0 {
 		targets = targetsFromIngressStatus(ing.Status)
 	}
 
 	providerSpecific, setIdentifier := annotations.ProviderSpecificAnnotations(ing.Annotations)
 
-	// Gather endpoints defined on hosts sections of the ingress
+	// Gather endpoints defined on hosts sections of the ingress. The rules and the tls
+	// sections are read independently, so an ingress with tls hosts but no rules still
+	// yields endpoints for its tls hosts.
 	var definedHostsEndpoints []*endpoint.Endpoint
 	// Skip endpoints if we do not want entries from Rules section
 	if !ignoreIngressRulesSpec {
@@ -340,15 +604,30 @@ func endpointsFromIngress(ing *networkv1.Ingress, ignoreHostnameAnnotation bool,
 	return endpoints
 }
 
+// hasIngressTargets reports whether the ingress has a target annotation or a load balancer address.
+func hasIngressTargets(ing *networkv1.Ingress) bool {
+	return len(annotations.TargetsFromTargetAnnotation(ing.Annotations)) > 0 ||
+		len(targetsFromIngressStatus(ing.Status)) > 0
+}
+
+// targetsFromIngressStatus returns the addresses and hostnames of the ingress load balancer.
+// Empty and duplicate entries are dropped, so that on IPv6-only or hostname-only clusters
+// only AAAA and CNAME endpoints are generated from the remaining targets. Addresses and
+// hostnames share the returned targets; EndpointsForHostname splits them by record type.
 func targetsFromIngressStatus(status networkv1.IngressStatus) endpoint.Targets {
 	var targets endpoint.Targets
+	seen := map[string]struct{}{}
 
 	for _, lb := range status.LoadBalancer.Ingress {
-		if lb.IP != "" {
-			targets = append(targets, lb.IP)
-		}
-		if lb.Hostname != "" {
-			targets = append(targets, lb.Hostname)
+		for _, target := range []string{lb.IP, lb.Hostname} {
+			if target == "" {
+				continue
+			}
+			if _, ok := seen[target]; ok {
+				continue
+			}
+			seen[target] = struct{}{}
+			targets = append(targets, target)
 		}
 	}
 
@@ -361,4 +640,10 @@ func (sc *ingressSource) AddEventHandler(ctx context.Context, handler func()) {
 	// Right now there is no way to remove event handler from informer, see:
 	// https://github.com/kubernetes/kubernetes/issues/79610
 	sc.ingressInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	if sc.ingressClassInformer != nil {
+		sc.ingressClassInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	}
+	if sc.serviceInformer != nil {
+		sc.serviceInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	}
 }
@@ -63,15 +63,12 @@ func TestIngressSourceNewNodeSourceWithFqdn(t *testing.T) {
 			_, err := NewIngressSource(
 				t.Context(),
 				fake.NewClientset(),
-				"",
-				"",
-				tt.fqdnTemplate,
-				false,
-				false,
-				false,
-				false,
-				labels.Everything(),
-				[]string{},
+				IngressConfig{
+					FQDNTemplate:         tt.fqdnTemplate,
+					LabelSelector:        labels.Everything(),
+					IngressClassNames:    []string{},
+					PendingAddressPolicy: IngressPendingAddressSkip,
+				},
 			)
 
 			if tt.expectError {
@@ -325,15 +322,13 @@ func TestIngressSourceFqdnTemplatingExamples(t *testing.T) {
 			src, err := NewIngressSource(
 				t.Context(),
 				kubeClient,
-				"",
-				"",
-				tt.fqdnTemplate,
-				true,
-				false,
-				false,
-				false,
-				labels.Everything(),
-				[]string{},
+				IngressConfig{
+					FQDNTemplate:          tt.fqdnTemplate,
+					CombineFQDNAnnotation: true,
+					LabelSelector:         labels.Everything(),
+					IngressClassNames:     []string{},
+					PendingAddressPolicy:  IngressPendingAddressSkip,
+				},
 			)
 
 			require.NoError(t, err)
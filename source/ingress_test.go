@@ -19,16 +19,19 @@ package source
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
 	networkv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes/fake"
 
 	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/source/annotations"
 )
 
 // Validates that ingressSource is a Source
@@ -56,15 +59,12 @@ func (suite *IngressSuite) SetupTest() {
 	suite.sc, err = NewIngressSource(
 		context.TODO(),
 		fakeClient,
-		"",
-		"",
-		"{{.Name}}",
-		false,
-		false,
-		false,
-		false,
-		labels.Everything(),
-		[]string{},
+		IngressConfig{
+			FQDNTemplate:         "{{.Name}}",
+			LabelSelector:        labels.Everything(),
+			IngressClassNames:    []string{},
+			PendingAddressPolicy: IngressPendingAddressSkip,
+		},
 	)
 	suite.NoError(err, "should initialize ingress source")
 }
@@ -95,6 +95,7 @@ func TestNewIngressSource(t *testing.T) {
 		combineFQDNAndAnnotation bool
 		expectError              bool
 		ingressClassNames        []string
+		annotationFilters        map[string]string
 	}{
 		{
 			title:            "non-empty annotation filter label",
@@ -112,6 +113,17 @@ func TestNewIngressSource(t *testing.T) {
 			ingressClassNames: []string{"internal", "external"},
 			annotationFilter:  "kubernetes.io/ingress.class=nginx",
 		},
+		{
+			title:             "ingress class name and namespace annotation filter jointly specified",
+			expectError:       true,
+			ingressClassNames: []string{"internal", "external"},
+			annotationFilters: map[string]string{"team-a": "kubernetes.io/ingress.class=nginx"},
+		},
+		{
+			title:             "invalid namespace annotation filter",
+			expectError:       true,
+			annotationFilters: map[string]string{"team-a": "kubernetes.io/ingress.name in (a b)"},
+		},
 	} {
 
 		t.Run(ti.title, func(t *testing.T) {
@@ -120,15 +132,15 @@ func TestNewIngressSource(t *testing.T) {
 			_, err := NewIngressSource(
 				t.Context(),
 				fake.NewClientset(),
-				"",
-				ti.annotationFilter,
-				ti.fqdnTemplate,
-				ti.combineFQDNAndAnnotation,
-				false,
-				false,
-				false,
-				labels.Everything(),
-				ti.ingressClassNames,
+				IngressConfig{
+					AnnotationFilter:      ti.annotationFilter,
+					FQDNTemplate:          ti.fqdnTemplate,
+					CombineFQDNAnnotation: ti.combineFQDNAndAnnotation,
+					LabelSelector:         labels.Everything(),
+					IngressClassNames:     ti.ingressClassNames,
+					AnnotationFilters:     ti.annotationFilters,
+					PendingAddressPolicy:  IngressPendingAddressSkip,
+				},
 			)
 			if ti.expectError {
 				assert.Error(t, err)
@@ -250,6 +262,49 @@ func testEndpointsFromIngress(t *testing.T) {
 			expected:               []*endpoint.Endpoint{},
 			ignoreIngressRulesSpec: true,
 		},
+		{
+			title: "tls-only ingress",
+			ingress: fakeIngress{
+				tlsdnsnames: [][]string{{"foo.bar", "baz.bar"}},
+				ips:         []string{"8.8.8.8"},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "foo.bar",
+					RecordType: endpoint.RecordTypeA,
+					Targets:    endpoint.Targets{"8.8.8.8"},
+				},
+				{
+					DNSName:    "baz.bar",
+					RecordType: endpoint.RecordTypeA,
+					Targets:    endpoint.Targets{"8.8.8.8"},
+				},
+			},
+		},
+		{
+			title: "tls-only ingress with ignored rules",
+			ingress: fakeIngress{
+				tlsdnsnames: [][]string{{"foo.bar"}},
+				ips:         []string{"8.8.8.8"},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "foo.bar",
+					RecordType: endpoint.RecordTypeA,
+					Targets:    endpoint.Targets{"8.8.8.8"},
+				},
+			},
+			ignoreIngressRulesSpec: true,
+		},
+		{
+			title: "tls-only ingress with ignored tls",
+			ingress: fakeIngress{
+				tlsdnsnames: [][]string{{"foo.bar"}},
+				ips:         []string{"8.8.8.8"},
+			},
+			expected:             []*endpoint.Endpoint{},
+			ignoreIngressTLSSpec: true,
+		},
 		{
 			title: "invalid hostname does not generate endpoints",
 			ingress: fakeIngress{
@@ -260,7 +315,7 @@ func testEndpointsFromIngress(t *testing.T) {
 	} {
 		t.Run(ti.title, func(t *testing.T) {
 			realIngress := ti.ingress.Ingress()
-			validateEndpoints(t, endpointsFromIngress(realIngress, ti.ignoreHostnameAnnotation, ti.ignoreIngressTLSSpec, ti.ignoreIngressRulesSpec), ti.expected)
+			validateEndpoints(t, endpointsFromIngress(realIngress, ti.ignoreHostnameAnnotation, ti.ignoreIngressTLSSpec, ti.ignoreIngressRulesSpec, 0, nil), ti.expected)
 		})
 	}
 }
@@ -359,7 +414,7 @@ func testEndpointsFromIngressHostnameSourceAnnotation(t *testing.T) {
 	} {
 		t.Run(ti.title, func(t *testing.T) {
 			realIngress := ti.ingress.Ingress()
-			validateEndpoints(t, endpointsFromIngress(realIngress, false, false, false), ti.expected)
+			validateEndpoints(t, endpointsFromIngress(realIngress, false, false, false, 0, nil), ti.expected)
 		})
 	}
 }
@@ -382,6 +437,7 @@ func testIngressEndpoints(t *testing.T) {
 		ignoreIngressRulesSpec   bool
 		ingressLabelSelector     labels.Selector
 		ingressClassNames        []string
+		annotationFilters        map[string]string
 	}{
 		{
 			title:           "no ingress",
@@ -633,6 +689,76 @@ func testIngressEndpoints(t *testing.T) {
 			},
 			expected: []*endpoint.Endpoint{},
 		},
+		{
+			title:            "per-namespace annotation filters",
+			targetNamespace:  "",
+			annotationFilter: "kubernetes.io/ingress.class=nginx",
+			annotationFilters: map[string]string{
+				"team-a": "team=a",
+				"team-b": "team=b",
+			},
+			ingressItems: []fakeIngress{
+				{
+					name:        "team-a-match",
+					namespace:   "team-a",
+					annotations: map[string]string{"team": "a"},
+					dnsnames:    []string{"a.example.org"},
+					ips:         []string{"1.1.1.1"},
+				},
+				{
+					name:        "team-a-no-match",
+					namespace:   "team-a",
+					annotations: map[string]string{"team": "b", "kubernetes.io/ingress.class": "nginx"},
+					dnsnames:    []string{"a-other.example.org"},
+					ips:         []string{"1.1.1.2"},
+				},
+				{
+					name:        "team-b-match",
+					namespace:   "team-b",
+					annotations: map[string]string{"team": "b"},
+					dnsnames:    []string{"b.example.org"},
+					ips:         []string{"2.2.2.2"},
+				},
+				{
+					name:        "team-b-no-match",
+					namespace:   "team-b",
+					annotations: map[string]string{"team": "a"},
+					dnsnames:    []string{"b-other.example.org"},
+					ips:         []string{"2.2.2.3"},
+				},
+				{
+					name:        "default-match",
+					namespace:   namespace,
+					annotations: map[string]string{"kubernetes.io/ingress.class": "nginx"},
+					dnsnames:    []string{"default.example.org"},
+					ips:         []string{"3.3.3.3"},
+				},
+				{
+					name:        "default-no-match",
+					namespace:   namespace,
+					annotations: map[string]string{"team": "a"},
+					dnsnames:    []string{"default-other.example.org"},
+					ips:         []string{"3.3.3.4"},
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "a.example.org",
+					RecordType: endpoint.RecordTypeA,
+					Targets:    endpoint.Targets{"1.1.1.1"},
+				},
+				{
+					DNSName:    "b.example.org",
+					RecordType: endpoint.RecordTypeA,
+					Targets:    endpoint.Targets{"2.2.2.2"},
+				},
+				{
+					DNSName:    "default.example.org",
+					RecordType: endpoint.RecordTypeA,
+					Targets:    endpoint.Targets{"3.3.3.3"},
+				},
+			},
+		},
 		{
 			title:           "our controller type is dns-controller",
 			targetNamespace: "",
@@ -794,6 +920,73 @@ func testIngressEndpoints(t *testing.T) {
 			fqdnTemplate:             "{{.Name}}.ext-dns.test.com, {{.Name}}.ext-dna.test.com",
 			combineFQDNAndAnnotation: true,
 		},
+		{
+			title:           "IPv6-only ingress status",
+			targetNamespace: "",
+			ingressItems: []fakeIngress{
+				{
+					name:      "fake1",
+					namespace: namespace,
+					dnsnames:  []string{"example.org"},
+					ips:       []string{"2001:db8::1", "2001:db8::2", "2001:db8::1"},
+				},
+				{
+					name:      "fake2",
+					namespace: namespace,
+					dnsnames:  []string{"example2.org"},
+					ips:       []string{"2001:db8::3"},
+					hostnames: []string{"lb.example.com"},
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "example.org",
+					Targets:    endpoint.Targets{"2001:db8::1", "2001:db8::2"},
+					RecordType: endpoint.RecordTypeAAAA,
+				},
+				{
+					DNSName:    "example2.org",
+					Targets:    endpoint.Targets{"2001:db8::3"},
+					RecordType: endpoint.RecordTypeAAAA,
+				},
+				{
+					DNSName:    "example2.org",
+					Targets:    endpoint.Targets{"lb.example.com"},
+					RecordType: endpoint.RecordTypeCNAME,
+				},
+			},
+		},
+		{
+			title:           "template and ingress rule producing the same host yield a single endpoint",
+			targetNamespace: "",
+			ingressItems: []fakeIngress{
+				{
+					name:      "fake1",
+					namespace: namespace,
+					dnsnames:  []string{"fake1.ext-dns.test.com", "example.org"},
+					ips:       []string{"8.8.8.8"},
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "fake1.ext-dns.test.com",
+					Targets:    endpoint.Targets{"8.8.8.8"},
+					RecordType: endpoint.RecordTypeA,
+				},
+				{
+					DNSName:    "example.org",
+					Targets:    endpoint.Targets{"8.8.8.8"},
+					RecordType: endpoint.RecordTypeA,
+				},
+				{
+					DNSName:    "fake1.ext-dna.test.com",
+					Targets:    endpoint.Targets{"8.8.8.8"},
+					RecordType: endpoint.RecordTypeA,
+				},
+			},
+			fqdnTemplate:             "{{.Name}}.ext-dns.test.com, {{.Name}}.ext-dna.test.com",
+			combineFQDNAndAnnotation: true,
+		},
 		{
 			title:           "ingress rules with annotation",
 			targetNamespace: "",
@@ -1417,15 +1610,19 @@ func testIngressEndpoints(t *testing.T) {
 			source, _ := NewIngressSource(
 				context.TODO(),
 				fakeClient,
-				ti.targetNamespace,
-				ti.annotationFilter,
-				ti.fqdnTemplate,
-				ti.combineFQDNAndAnnotation,
-				ti.ignoreHostnameAnnotation,
-				ti.ignoreIngressTLSSpec,
-				ti.ignoreIngressRulesSpec,
-				ti.ingressLabelSelector,
-				ti.ingressClassNames,
+				IngressConfig{
+					Namespace:                ti.targetNamespace,
+					AnnotationFilter:         ti.annotationFilter,
+					FQDNTemplate:             ti.fqdnTemplate,
+					CombineFQDNAnnotation:    ti.combineFQDNAndAnnotation,
+					IgnoreHostnameAnnotation: ti.ignoreHostnameAnnotation,
+					IgnoreIngressTLSSpec:     ti.ignoreIngressTLSSpec,
+					IgnoreIngressRulesSpec:   ti.ignoreIngressRulesSpec,
+					LabelSelector:            ti.ingressLabelSelector,
+					IngressClassNames:        ti.ingressClassNames,
+					AnnotationFilters:        ti.annotationFilters,
+					PendingAddressPolicy:     IngressPendingAddressSkip,
+				},
 			)
 			// Informer cache has all of the ingresses. Retrieve and validate their endpoints.
 			res, err := source.Endpoints(t.Context())
@@ -1444,6 +1641,461 @@ func testIngressEndpoints(t *testing.T) {
 	}
 }
 
+func TestIngressStatusWithIPAndHostname(t *testing.T) {
+	ing := (fakeIngress{
+		name:      "foo",
+		namespace: "default",
+		dnsnames:  []string{"foo.example.com"},
+	}).Ingress()
+	// some controllers report the address and the hostname of a load balancer in a single entry
+	ing.Status.LoadBalancer.Ingress = []networkv1.IngressLoadBalancerIngress{
+		{IP: "8.8.8.8", Hostname: "lb.example.net"},
+		{IP: "2606:4700:4700::1111"},
+	}
+
+	endpoints := endpointsFromIngress(ing, false, false, false, 0, nil)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		{
+			DNSName:    "foo.example.com",
+			RecordType: endpoint.RecordTypeA,
+			Targets:    endpoint.Targets{"8.8.8.8"},
+		},
+		{
+			DNSName:    "foo.example.com",
+			RecordType: endpoint.RecordTypeAAAA,
+			Targets:    endpoint.Targets{"2606:4700:4700::1111"},
+		},
+		{
+			DNSName:    "foo.example.com",
+			RecordType: endpoint.RecordTypeCNAME,
+			Targets:    endpoint.Targets{"lb.example.net"},
+		},
+	})
+}
+
+func TestIngressTemplateEndpointsMatchRuleEndpoints(t *testing.T) {
+	fakeClient := fake.NewClientset()
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "lb"},
+		Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{
+			Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+		}},
+	}
+	_, err := fakeClient.CoreV1().Services(svc.Namespace).Create(t.Context(), svc, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	ing := (fakeIngress{
+		name:      "foo",
+		namespace: "default",
+		dnsnames:  []string{"rule.example.org"},
+		ips:       []string{"8.8.8.8"},
+		annotations: map[string]string{
+			targetServiceAnnotationKey:       "lb",
+			annotations.SetIdentifierKey:     "blue",
+			annotations.CloudflareProxiedKey: "true",
+			annotations.AWSPrefix + "weight": "10",
+			annotations.TtlKey:               "60",
+		},
+	}).Ingress()
+	_, err = fakeClient.NetworkingV1().Ingresses(ing.Namespace).Create(t.Context(), ing, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	src, err := NewIngressSource(
+		t.Context(),
+		fakeClient,
+		IngressConfig{
+			FQDNTemplate:          "{{.Name}}.template.example.org",
+			CombineFQDNAnnotation: true,
+			LabelSelector:         labels.Everything(),
+			IngressClassNames:     []string{},
+			PendingAddressPolicy:  IngressPendingAddressSkip,
+		},
+	)
+	require.NoError(t, err)
+
+	endpoints, err := src.Endpoints(t.Context())
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+
+	byName := map[string]*endpoint.Endpoint{}
+	for _, ep := range endpoints {
+		byName[ep.DNSName] = ep
+	}
+	rule, template := byName["rule.example.org"], byName["foo.template.example.org"]
+	require.NotNil(t, rule)
+	require.NotNil(t, template)
+	assert.Equal(t, endpoint.Targets{"1.2.3.4"}, rule.Targets)
+	assert.Equal(t, "blue", rule.SetIdentifier)
+	assert.NotEmpty(t, rule.ProviderSpecific)
+
+	assert.Equal(t, rule.RecordType, template.RecordType)
+	assert.Equal(t, rule.Targets, template.Targets)
+	assert.Equal(t, rule.RecordTTL, template.RecordTTL)
+	assert.Equal(t, rule.SetIdentifier, template.SetIdentifier)
+	assert.ElementsMatch(t, rule.ProviderSpecific, template.ProviderSpecific)
+}
+
+func TestIngressHealthCheckAnnotation(t *testing.T) {
+	fakeClient := fake.NewClientset()
+	ing := (fakeIngress{
+		name:        "foo",
+		namespace:   "default",
+		dnsnames:    []string{"rule.example.org"},
+		tlsdnsnames: [][]string{{"tls.example.org"}},
+		ips:         []string{"8.8.8.8"},
+		annotations: map[string]string{
+			annotations.HostnameKey:      "annotation.example.org",
+			annotations.HealthCheckIDKey: "abc-123",
+		},
+	}).Ingress()
+	_, err := fakeClient.NetworkingV1().Ingresses(ing.Namespace).Create(t.Context(), ing, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	src, err := NewIngressSource(
+		t.Context(),
+		fakeClient,
+		IngressConfig{
+			FQDNTemplate:          "{{.Name}}.template.example.org",
+			CombineFQDNAnnotation: true,
+			LabelSelector:         labels.Everything(),
+			IngressClassNames:     []string{},
+			PendingAddressPolicy:  IngressPendingAddressSkip,
+		},
+	)
+	require.NoError(t, err)
+
+	endpoints, err := src.Endpoints(t.Context())
+	require.NoError(t, err)
+
+	var names []string
+	for _, ep := range endpoints {
+		names = append(names, ep.DNSName)
+		value, ok := ep.GetProviderSpecificProperty("aws/health-check-id")
+		assert.True(t, ok, "missing health check of %s", ep.DNSName)
+		assert.Equal(t, "abc-123", value)
+	}
+	assert.ElementsMatch(t, []string{
+		"rule.example.org",
+		"tls.example.org",
+		"annotation.example.org",
+		"foo.template.example.org",
+	}, names)
+}
+
+func TestIngressEndpointCache(t *testing.T) {
+	fakeClient := fake.NewClientset()
+	ing := (fakeIngress{
+		name:      "foo",
+		namespace: "default",
+		dnsnames:  []string{"foo.example.com"},
+		ips:       []string{"8.8.8.8"},
+	}).Ingress()
+	ing.ResourceVersion = "1"
+	_, err := fakeClient.NetworkingV1().Ingresses(ing.Namespace).Create(t.Context(), ing, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	src, err := NewIngressSource(
+		t.Context(),
+		fakeClient,
+		IngressConfig{
+			LabelSelector:        labels.Everything(),
+			IngressClassNames:    []string{},
+			CacheEndpoints:       true,
+			PendingAddressPolicy: IngressPendingAddressSkip,
+		},
+	)
+	require.NoError(t, err)
+	sc := src.(*ingressSource)
+
+	// update changes the host of the ingress and waits for the informer to observe it
+	update := func(resourceVersion, host string) {
+		updated := ing.DeepCopy()
+		updated.ResourceVersion = resourceVersion
+		updated.Spec.Rules[0].Host = host
+		_, err := fakeClient.NetworkingV1().Ingresses(ing.Namespace).Update(t.Context(), updated, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			got, err := sc.ingressInformer.Lister().Ingresses(ing.Namespace).Get(ing.Name)
+			return err == nil && got.ResourceVersion == resourceVersion && got.Spec.Rules[0].Host == host
+		}, time.Second, 10*time.Millisecond)
+	}
+
+	endpoints, err := sc.Endpoints(t.Context())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		{DNSName: "foo.example.com", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"8.8.8.8"}},
+	})
+
+	// modifying the returned endpoints does not affect the cache
+	endpoints[0].Targets = endpoint.Targets{"1.1.1.1"}
+
+	// without a new resource version the ingresses are considered unchanged
+	update("1", "bar.example.com")
+	endpoints, err = sc.Endpoints(t.Context())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		{DNSName: "foo.example.com", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"8.8.8.8"}},
+	})
+
+	update("2", "bar.example.com")
+	endpoints, err = sc.Endpoints(t.Context())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		{DNSName: "bar.example.com", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"8.8.8.8"}},
+	})
+}
+
+func TestIngressPendingAddress(t *testing.T) {
+	for _, policy := range []string{IngressPendingAddressSkip, IngressPendingAddressWait} {
+		t.Run(policy, func(t *testing.T) {
+			fakeClient := fake.NewClientset()
+			pending := (fakeIngress{
+				name:      "pending",
+				namespace: "default",
+				dnsnames:  []string{"pending.example.com"},
+			}).Ingress()
+			_, err := fakeClient.NetworkingV1().Ingresses(pending.Namespace).Create(t.Context(), pending, metav1.CreateOptions{})
+			require.NoError(t, err)
+			ing := (fakeIngress{
+				name:      "foo",
+				namespace: "default",
+				dnsnames:  []string{"foo.example.com"},
+				ips:       []string{"8.8.8.8"},
+			}).Ingress()
+			_, err = fakeClient.NetworkingV1().Ingresses(ing.Namespace).Create(t.Context(), ing, metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			src, err := NewIngressSource(
+				t.Context(),
+				fakeClient,
+				IngressConfig{
+					LabelSelector:        labels.Everything(),
+					IngressClassNames:    []string{},
+					PendingAddressPolicy: policy,
+				},
+			)
+			require.NoError(t, err)
+			sc := src.(*ingressSource)
+
+			// an ingress that never had an address produces no endpoints
+			endpoints, err := sc.Endpoints(t.Context())
+			require.NoError(t, err)
+			validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+				{DNSName: "foo.example.com", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"8.8.8.8"}},
+			})
+
+			// the ingress loses its address
+			updated := ing.DeepCopy()
+			updated.Status.LoadBalancer.Ingress = nil
+			_, err = fakeClient.NetworkingV1().Ingresses(ing.Namespace).Update(t.Context(), updated, metav1.UpdateOptions{})
+			require.NoError(t, err)
+			require.Eventually(t, func() bool {
+				got, err := sc.ingressInformer.Lister().Ingresses(ing.Namespace).Get(ing.Name)
+				return err == nil && len(got.Status.LoadBalancer.Ingress) == 0
+			}, time.Second, 10*time.Millisecond)
+
+			endpoints, err = sc.Endpoints(t.Context())
+			require.NoError(t, err)
+			if policy == IngressPendingAddressWait {
+				validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+					{DNSName: "foo.example.com", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"8.8.8.8"}},
+				})
+			} else {
+				validateEndpoints(t, endpoints, []*endpoint.Endpoint{})
+			}
+		})
+	}
+}
+
+func TestNewIngressSourceInvalidPendingAddress(t *testing.T) {
+	_, err := NewIngressSource(
+		t.Context(),
+		fake.NewClientset(),
+		IngressConfig{
+			LabelSelector:        labels.Everything(),
+			IngressClassNames:    []string{},
+			PendingAddressPolicy: "forever",
+		},
+	)
+	require.Error(t, err)
+}
+
+func TestIngressClassNamePrecedence(t *testing.T) {
+	ingress := func(name, className, annotated string) *networkv1.Ingress {
+		ing := &networkv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+		if className != "" {
+			ing.Spec.IngressClassName = &className
+		}
+		if annotated != "" {
+			ing.Annotations = map[string]string{IngressClassAnnotationKey: annotated}
+		}
+		return ing
+	}
+	ingresses := []*networkv1.Ingress{
+		ingress("spec-public-annotated-internal", "public", "internal"),
+		ingress("spec-internal-annotated-public", "internal", "public"),
+		ingress("annotated-public", "", "public"),
+		ingress("spec-public", "public", ""),
+	}
+
+	assert.Equal(t, "public", ingressClassName(ingresses[0]), "the spec field should win over the annotation")
+	assert.Equal(t, "internal", ingressClassName(ingresses[1]), "the spec field should win over the annotation")
+	assert.Equal(t, "public", ingressClassName(ingresses[2]), "the annotation should apply without the spec field")
+
+	sc := &ingressSource{ingressClassNames: []string{"public"}}
+	var names []string
+	for _, ing := range sc.filterByIngressClass(ingresses) {
+		names = append(names, ing.Name)
+	}
+	assert.Equal(t, []string{"spec-public-annotated-internal", "annotated-public", "spec-public"}, names)
+}
+
+func TestIngressClassTTL(t *testing.T) {
+	fakeClient := fake.NewClientset()
+	class := &networkv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "public",
+			Annotations: map[string]string{annotations.TtlKey: "600"},
+		},
+	}
+	_, err := fakeClient.NetworkingV1().IngressClasses().Create(t.Context(), class, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	for _, ing := range []fakeIngress{
+		{
+			name:        "class-only",
+			namespace:   "default",
+			dnsnames:    []string{"class-only.example.com"},
+			ips:         []string{"8.8.8.8"},
+			annotations: map[string]string{IngressClassAnnotationKey: "public"},
+		},
+		{
+			name:             "both",
+			namespace:        "default",
+			dnsnames:         []string{"both.example.com"},
+			ips:              []string{"8.8.8.8"},
+			annotations:      map[string]string{annotations.TtlKey: "60"},
+			ingressClassName: "public",
+		},
+		{
+			name:        "other-class",
+			namespace:   "default",
+			dnsnames:    []string{"other-class.example.com"},
+			ips:         []string{"8.8.8.8"},
+			annotations: map[string]string{IngressClassAnnotationKey: "internal"},
+		},
+	} {
+		_, err := fakeClient.NetworkingV1().Ingresses(ing.namespace).Create(t.Context(), ing.Ingress(), metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	for _, tc := range []struct {
+		title           string
+		ingressClassTTL bool
+		expected        []*endpoint.Endpoint
+	}{
+		{
+			title:           "class TTL enabled",
+			ingressClassTTL: true,
+			expected: []*endpoint.Endpoint{
+				{DNSName: "class-only.example.com", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"8.8.8.8"}, RecordTTL: 600},
+				{DNSName: "both.example.com", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"8.8.8.8"}, RecordTTL: 60},
+				{DNSName: "other-class.example.com", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"8.8.8.8"}},
+			},
+		},
+		{
+			title: "class TTL disabled",
+			expected: []*endpoint.Endpoint{
+				{DNSName: "class-only.example.com", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"8.8.8.8"}},
+				{DNSName: "both.example.com", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"8.8.8.8"}, RecordTTL: 60},
+				{DNSName: "other-class.example.com", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"8.8.8.8"}},
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			src, err := NewIngressSource(
+				t.Context(),
+				fakeClient,
+				IngressConfig{
+					LabelSelector:        labels.Everything(),
+					IngressClassNames:    []string{},
+					PendingAddressPolicy: IngressPendingAddressSkip,
+					IngressClassTTL:      tc.ingressClassTTL,
+				},
+			)
+			require.NoError(t, err)
+
+			endpoints, err := src.Endpoints(t.Context())
+			require.NoError(t, err)
+			validateEndpoints(t, endpoints, tc.expected)
+		})
+	}
+}
+
+func TestIngressDefaultBackend(t *testing.T) {
+	fakeClient := fake.NewClientset()
+	defaultBackendOnly := fakeIngress{
+		name:      "default-backend-only",
+		namespace: "default",
+		ips:       []string{"8.8.8.8"},
+	}.Ingress()
+	defaultBackendOnly.Spec.DefaultBackend = &networkv1.IngressBackend{
+		Service: &networkv1.IngressServiceBackend{Name: "web", Port: networkv1.ServiceBackendPort{Number: 80}},
+	}
+	withHost := fakeIngress{
+		name:      "with-host",
+		namespace: "default",
+		dnsnames:  []string{"app.example.org"},
+		ips:       []string{"8.8.4.4"},
+	}.Ingress()
+	withHost.Spec.DefaultBackend = &networkv1.IngressBackend{
+		Service: &networkv1.IngressServiceBackend{Name: "fallback", Port: networkv1.ServiceBackendPort{Number: 80}},
+	}
+	for _, ing := range []*networkv1.Ingress{defaultBackendOnly, withHost} {
+		_, err := fakeClient.NetworkingV1().Ingresses(ing.Namespace).Create(t.Context(), ing, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	for _, tc := range []struct {
+		title                string
+		defaultBackendDomain string
+		expected             []*endpoint.Endpoint
+	}{
+		{
+			title:                "default backend domain configured",
+			defaultBackendDomain: "backends.example.org.",
+			expected: []*endpoint.Endpoint{
+				{DNSName: "web.backends.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"8.8.8.8"}},
+				{DNSName: "app.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"8.8.4.4"}},
+			},
+		},
+		{
+			title: "default backend domain not configured",
+			expected: []*endpoint.Endpoint{
+				{DNSName: "app.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"8.8.4.4"}},
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			src, err := NewIngressSource(
+				t.Context(),
+				fakeClient,
+				IngressConfig{
+					LabelSelector:        labels.Everything(),
+					IngressClassNames:    []string{},
+					PendingAddressPolicy: IngressPendingAddressSkip,
+					DefaultBackendDomain: tc.defaultBackendDomain,
+				},
+			)
+			require.NoError(t, err)
+
+			endpoints, err := src.Endpoints(t.Context())
+			require.NoError(t, err)
+			validateEndpoints(t, endpoints, tc.expected)
+		})
+	}
+}
+
 // ingress specific helper functions
 type fakeIngress struct {
 	dnsnames         []string
@@ -1497,3 +2149,107 @@ func (ing fakeIngress) Ingress() *networkv1.Ingress {
 	}
 	return ingress
 }
+
+func TestIngressTargetService(t *testing.T) {
+	fakeClient := fake.NewClientset()
+	services := []*v1.Service{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "lb"},
+			Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "edge", Name: "lb"},
+			Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{{Hostname: "lb.example.com"}},
+			}},
+		},
+	}
+	for _, svc := range services {
+		_, err := fakeClient.CoreV1().Services(svc.Namespace).Create(t.Context(), svc, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	ingresses := []fakeIngress{
+		{
+			name:        "same-namespace",
+			namespace:   "default",
+			dnsnames:    []string{"same.example.org"},
+			annotations: map[string]string{targetServiceAnnotationKey: "lb"},
+		},
+		{
+			name:        "other-namespace",
+			namespace:   "default",
+			dnsnames:    []string{"other.example.org"},
+			ips:         []string{"8.8.8.8"},
+			annotations: map[string]string{targetServiceAnnotationKey: "edge/lb"},
+		},
+		{
+			name:      "target-annotation",
+			namespace: "default",
+			dnsnames:  []string{"target.example.org"},
+			annotations: map[string]string{
+				targetServiceAnnotationKey: "lb",
+				targetAnnotationKey:        "5.6.7.8",
+			},
+		},
+		{
+			name:        "missing-service",
+			namespace:   "default",
+			dnsnames:    []string{"missing.example.org"},
+			annotations: map[string]string{targetServiceAnnotationKey: "missing"},
+		},
+	}
+	for _, item := range ingresses {
+		ing := item.Ingress()
+		_, err := fakeClient.NetworkingV1().Ingresses(ing.Namespace).Create(t.Context(), ing, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	// without the option the annotation is ignored and services are not watched
+	src, err := NewIngressSource(
+		t.Context(),
+		fakeClient,
+		IngressConfig{
+			LabelSelector:        labels.Everything(),
+			IngressClassNames:    []string{},
+			PendingAddressPolicy: IngressPendingAddressSkip,
+		},
+	)
+	require.NoError(t, err)
+
+	endpoints, err := src.Endpoints(t.Context())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		{DNSName: "other.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"8.8.8.8"}},
+		{DNSName: "target.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"5.6.7.8"}},
+	})
+	for _, action := range fakeClient.Actions() {
+		assert.False(t, action.GetResource().Resource == "services" && action.GetVerb() != "create", "unexpected API call %v", action)
+	}
+
+	src, err = NewIngressSource(
+		t.Context(),
+		fakeClient,
+		IngressConfig{
+			LabelSelector:        labels.Everything(),
+			IngressClassNames:    []string{},
+			PendingAddressPolicy: IngressPendingAddressSkip,
+			TargetService:        true,
+		},
+	)
+	require.NoError(t, err)
+
+	endpoints, err = src.Endpoints(t.Context())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		{DNSName: "same.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"1.2.3.4"}},
+		{DNSName: "other.example.org", RecordType: endpoint.RecordTypeCNAME, Targets: endpoint.Targets{"lb.example.com"}},
+		{DNSName: "target.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"5.6.7.8"}},
+	})
+	// target services are read from the informer cache rather than the API
+	for _, action := range fakeClient.Actions() {
+		assert.False(t, action.Matches("get", "services"), "unexpected API call %v", action)
+	}
+}
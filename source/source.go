@@ -33,6 +33,7 @@ const (
 	accessAnnotationKey           = annotations.AccessKey
 	endpointsTypeAnnotationKey    = annotations.EndpointsTypeKey
 	targetAnnotationKey           = annotations.TargetKey
+	targetServiceAnnotationKey    = annotations.TargetServiceKey
 	ttlAnnotationKey              = annotations.TtlKey
 	aliasAnnotationKey            = annotations.AliasKey
 	ingressHostnameSourceKey      = annotations.IngressHostnameSourceKey
@@ -65,6 +65,12 @@ type Config struct {
 	AnnotationFilter               string
 	LabelFilter                    labels.Selector
 	IngressClassNames              []string
+	IngressAnnotationFilters       map[string]string
+	IngressEndpointCache           bool
+	IngressPendingAddress          string
+	IngressClassTTL                bool
+	IngressTargetService           bool
+	IngressDefaultBackendDomain    string
 	FQDNTemplate                   string
 	CombineFQDNAndAnnotation       bool
 	IgnoreHostnameAnnotation       bool
@@ -111,6 +117,12 @@ func NewSourceConfig(cfg *externaldns.Config) *Config {
 		AnnotationFilter:               cfg.AnnotationFilter,
 		LabelFilter:                    labelSelector,
 		IngressClassNames:              cfg.IngressClassNames,
+		IngressAnnotationFilters:       cfg.IngressAnnotationFilters,
+		IngressEndpointCache:           cfg.IngressEndpointCache,
+		IngressPendingAddress:          cfg.IngressPendingAddress,
+		IngressClassTTL:                cfg.IngressClassTTL,
+		IngressTargetService:           cfg.IngressTargetService,
+		IngressDefaultBackendDomain:    cfg.IngressDefaultBackendDomain,
 		FQDNTemplate:                   cfg.FQDNTemplate,
 		CombineFQDNAndAnnotation:       cfg.CombineFQDNAndAnnotation,
 		IgnoreHostnameAnnotation:       cfg.IgnoreHostnameAnnotation,
@@ -433,13 +445,33 @@ func buildServiceSource(ctx context.Context, p ClientGenerator, cfg *Config) (So
 }
 
 // buildIngressSource creates an Ingress source for exposing Kubernetes ingresses as DNS records.
-// Follows standard pattern: ctx, client, namespace, annotationFilter, fqdnTemplate, ...other
+// Takes ctx, client and an IngressConfig of the ingress options.
 func buildIngressSource(ctx context.Context, p ClientGenerator, cfg *Config) (Source, error) {
 	client, err := p.KubeClient()
 	if err != nil {
 		return nil, err
 	}
-	return NewIngressSource(ctx, client, cfg.Namespace, cfg.AnnotationFilter, cfg.FQDNTemplate, cfg.CombineFQDNAndAnnotation, cfg.IgnoreHostnameAnnotation, cfg.IgnoreIngressTLSSpec, cfg.IgnoreIngressRulesSpec, cfg.LabelFilter, cfg.IngressClassNames)
+	return NewIngressSource(
+		ctx,
+		client,
+		IngressConfig{
+			Namespace:                cfg.Namespace,
+			AnnotationFilter:         cfg.AnnotationFilter,
+			FQDNTemplate:             cfg.FQDNTemplate,
+			CombineFQDNAnnotation:    cfg.CombineFQDNAndAnnotation,
+			IgnoreHostnameAnnotation: cfg.IgnoreHostnameAnnotation,
+			IgnoreIngressTLSSpec:     cfg.IgnoreIngressTLSSpec,
+			IgnoreIngressRulesSpec:   cfg.IgnoreIngressRulesSpec,
+			LabelSelector:            cfg.LabelFilter,
+			IngressClassNames:        cfg.IngressClassNames,
+			AnnotationFilters:        cfg.IngressAnnotationFilters,
+			CacheEndpoints:           cfg.IngressEndpointCache,
+			PendingAddressPolicy:     cfg.IngressPendingAddress,
+			IngressClassTTL:          cfg.IngressClassTTL,
+			TargetService:            cfg.IngressTargetService,
+			DefaultBackendDomain:     cfg.IngressDefaultBackendDomain,
+		},
+	)
 }
 
 // buildPodSource creates a Pod source for exposing Kubernetes pods as DNS records.